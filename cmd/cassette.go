@@ -0,0 +1,208 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yockc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ansurfen/yock/util"
+)
+
+// CassetteRecord is the side effect of one call of entry point
+type CassetteRecord struct {
+	// Call is the name of entry point, such as Exec and SystemCtlStart
+	Call   string          `json:"call"`
+	Args   json.RawMessage `json:"args"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Err    string          `json:"err,omitempty"`
+}
+
+// Cassette records the arguments and results of the entry points touching
+// system, such as Exec, Curl, Cp, Rm, SystemCtl*, IPTables* and SSHClient,
+// and serves them in replay mode without touching system at all. The record
+// is matched by name and arguments of call, and each of them is served once
+// in order of recording, so that the same command returns what it returned
+// for the first, second... time.
+type Cassette struct {
+	file    string
+	replay  bool
+	mu      sync.Mutex
+	records []*CassetteRecord
+	used    []bool
+}
+
+// RecordCassette returns the cassette in record mode, which is written into file by Save
+func RecordCassette(file string) *Cassette {
+	return &Cassette{file: file}
+}
+
+// ReplayCassette loads the cassette recorded in file, and serves the records of it
+func ReplayCassette(file string) (*Cassette, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	c := &Cassette{file: file, replay: true}
+	if err = json.Unmarshal(raw, &c.records); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	// the arguments are indented when saving, and they're
+	// compacted to be compared with the ones of call.
+	for _, rec := range c.records {
+		buf := &bytes.Buffer{}
+		if err = json.Compact(buf, rec.Args); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		rec.Args = buf.Bytes()
+	}
+	c.used = make([]bool, len(c.records))
+	return c, nil
+}
+
+// Save writes the records into file in record mode, and it's noop in replay mode.
+func (c *Cassette) Save() error {
+	if c.replay {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	raw, err := json.MarshalIndent(c.records, "", "  ")
+	if err != nil {
+		return err
+	}
+	return util.WriteFile(c.file, raw)
+}
+
+// Unused returns the records not served in replay mode, which usually
+// means the script doesn't do what it did when recording.
+func (c *Cassette) Unused() []*CassetteRecord {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	unused := []*CassetteRecord{}
+	for i, used := range c.used {
+		if !used {
+			unused = append(unused, c.records[i])
+		}
+	}
+	return unused
+}
+
+var cassette atomic.Pointer[Cassette]
+
+// UseCassette intercepts the entry points with c, and nil stops intercepting.
+func UseCassette(c *Cassette) {
+	cassette.Store(c)
+}
+
+// playback calls fn and records result of it when cassette is recording.
+// When it's replaying, fn is never called and result is set by the record
+// matched instead. The result must be pointer, or nil for the call
+// only returning error. fn shouldn't call other entry points, but the
+// unexported implementations of them, so that only the outermost call is
+// recorded and served.
+func playback(call string, args []any, result any, fn func() error) error {
+	c := cassette.Load()
+	if c == nil {
+		return fn()
+	}
+	key, err := json.Marshal(args)
+	if err != nil {
+		return fn()
+	}
+	if c.replay {
+		return c.serve(call, key, result)
+	}
+	err = fn()
+	c.record(call, key, result, err)
+	return err
+}
+
+func (c *Cassette) record(call string, key []byte, result any, err error) {
+	rec := &CassetteRecord{Call: call, Args: key}
+	if result != nil {
+		rec.Result, _ = json.Marshal(result)
+	}
+	if err != nil {
+		rec.Err = err.Error()
+	}
+	c.mu.Lock()
+	c.records = append(c.records, rec)
+	c.mu.Unlock()
+}
+
+func (c *Cassette) serve(call string, key []byte, result any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, rec := range c.records {
+		if c.used[i] || rec.Call != call || !bytes.Equal(rec.Args, key) {
+			continue
+		}
+		c.used[i] = true
+		if result != nil && len(rec.Result) > 0 {
+			if err := json.Unmarshal(rec.Result, result); err != nil {
+				return fmt.Errorf("%s: %w", c.file, err)
+			}
+		}
+		if len(rec.Err) > 0 {
+			return errors.New(rec.Err)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: %s %s", util.ErrNoRecord, call, key)
+}
+
+// serviceRecord keeps ServiceInfo in cassette
+type serviceRecord struct {
+	ID     int32  `json:"pid"`
+	Unit   string `json:"name"`
+	Active string `json:"status"`
+}
+
+func (r serviceRecord) PID() int32 {
+	return r.ID
+}
+
+func (r serviceRecord) Name() string {
+	return r.Unit
+}
+
+func (r serviceRecord) Status() string {
+	return r.Active
+}
+
+// ruleRecord keeps FireWareRule in cassette
+type ruleRecord struct {
+	Rule     string `json:"name"`
+	Protocol string `json:"proto"`
+	Source   string `json:"src"`
+	Dest     string `json:"dst"`
+	Act      string `json:"action"`
+}
+
+func (r ruleRecord) Name() string {
+	return r.Rule
+}
+
+func (r ruleRecord) Proto() string {
+	return r.Protocol
+}
+
+func (r ruleRecord) Src() string {
+	return r.Source
+}
+
+func (r ruleRecord) Dst() string {
+	return r.Dest
+}
+
+func (r ruleRecord) Action() string {
+	return r.Act
+}
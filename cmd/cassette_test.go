@@ -0,0 +1,81 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yockc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ansurfen/yock/util"
+	"github.com/ansurfen/yock/util/test"
+)
+
+func TestCassette(t *testing.T) {
+	if util.CurPlatform.OS == "windows" {
+		t.Skip("posix shell is required")
+	}
+	dir := t.TempDir()
+	file := filepath.Join(dir, "cassette.json")
+	touched := filepath.Join(dir, "touched")
+	hits := 0
+	binary := []byte{0xff, 0xfe, 0x00, 0x80}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/binary" {
+			w.Write(binary)
+			return
+		}
+		hits++
+		fmt.Fprintf(w, "hit %d", hits)
+	}))
+
+	c := RecordCassette(file)
+	UseCassette(c)
+	out, err := Exec(ExecOpt{Quiet: true}, "touch "+touched+" && echo done")
+	test.Assert(err == nil && out == "done\n")
+	body, err := Curl(CurlOpt{}, []string{server.URL})
+	test.Assert(err == nil && string(body) == "hit 1\n\n")
+	body, err = Curl(CurlOpt{}, []string{server.URL})
+	test.Assert(err == nil && string(body) == "hit 2\n\n")
+	body, err = Curl(CurlOpt{}, []string{server.URL + "/binary"})
+	test.Assert(err == nil && bytes.HasPrefix(body, binary))
+	recorded := body
+	err = Rm(RmOpt{Safe: true}, filepath.Join(dir, "not exist"))
+	test.Assert(err != nil)
+	started := SystemCtlStart("yock-cassette")
+	UseCassette(nil)
+	test.Assert(c.Save() == nil)
+	server.Close()
+	os.Remove(touched)
+
+	c, err = ReplayCassette(file)
+	test.Assert(err == nil)
+	UseCassette(c)
+	defer UseCassette(nil)
+	out, err = Exec(ExecOpt{Quiet: true}, "touch "+touched+" && echo done")
+	test.Assert(err == nil && out == "done\n")
+	_, err = os.Stat(touched)
+	test.Assert(err != nil)
+	body, err = Curl(CurlOpt{}, []string{server.URL})
+	test.Assert(err == nil && string(body) == "hit 1\n\n")
+	body, err = Curl(CurlOpt{}, []string{server.URL})
+	test.Assert(err == nil && string(body) == "hit 2\n\n")
+	body, err = Curl(CurlOpt{}, []string{server.URL + "/binary"})
+	test.Assert(err == nil && bytes.Equal(body, recorded))
+	_, err = Curl(CurlOpt{}, []string{server.URL})
+	test.Assert(errors.Is(err, util.ErrNoRecord))
+	test.Assert(len(c.Unused()) == 2)
+	err = Rm(RmOpt{Safe: true}, filepath.Join(dir, "not exist"))
+	test.Assert(err != nil && !errors.Is(err, util.ErrNoRecord))
+	// the command executed by SystemCtlStart isn't recorded alone
+	err = SystemCtlStart("yock-cassette")
+	test.Assert(fmt.Sprint(err) == fmt.Sprint(started))
+	test.Assert(len(c.Unused()) == 0)
+}
@@ -47,6 +47,18 @@ type CurlOpt struct {
 
 // Curl is similar with curl, which is used to send Curl request according to opt and urls.
 func Curl(opt CurlOpt, urls []string) ([]byte, error) {
+	// the body is kept as base64 in cassette, since it might not be utf-8
+	var out []byte
+	args := []any{opt.Method, opt.Header, opt.Data, opt.Cookie, opt.Save, opt.Dir, urls}
+	err := playback("Curl", args, &out, func() error {
+		var err error
+		out, err = curl(opt, urls)
+		return err
+	})
+	return out, err
+}
+
+func curl(opt CurlOpt, urls []string) ([]byte, error) {
 	ret := []byte{}
 	for _, url := range urls {
 		if !util.IsURL(url) {
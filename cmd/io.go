@@ -22,6 +22,12 @@ type RmOpt struct {
 }
 
 func Rm(opt RmOpt, target string) error {
+	return playback("Rm", []any{opt, target}, nil, func() error {
+		return rm(opt, target)
+	})
+}
+
+func rm(opt RmOpt, target string) error {
 	if opt.Safe {
 		opt.Recurse = opt.Safe
 	}
@@ -61,6 +67,12 @@ type CpOpt struct {
 }
 
 func Cp(opt CpOpt, src, dst string) error {
+	return playback("Cp", []any{opt, src, dst}, nil, func() error {
+		return cp(opt, src, dst)
+	})
+}
+
+func cp(opt CpOpt, src, dst string) error {
 	var term *Terminal
 	switch util.CurPlatform.OS {
 	case "windows":
@@ -33,6 +33,29 @@ type IPTablesListOpt struct {
 }
 
 func IPTablesList(opt IPTablesListOpt) (rules []FireWareRule, err error) {
+	records := []ruleRecord{}
+	err = playback("IPTablesList", []any{opt}, &records, func() error {
+		rules, err = ipTablesList(opt)
+		for _, rule := range rules {
+			records = append(records, ruleRecord{
+				Rule:     rule.Name(),
+				Protocol: rule.Proto(),
+				Source:   rule.Src(),
+				Dest:     rule.Dst(),
+				Act:      rule.Action(),
+			})
+		}
+		return err
+	})
+	if rules == nil {
+		for _, record := range records {
+			rules = append(rules, record)
+		}
+	}
+	return rules, err
+}
+
+func ipTablesList(opt IPTablesListOpt) (rules []FireWareRule, err error) {
 	switch util.CurPlatform.OS {
 	case "windows":
 		cmd := "netsh advfirewall firewall show rule name=%s"
@@ -41,7 +64,7 @@ func IPTablesList(opt IPTablesListOpt) (rules []FireWareRule, err error) {
 		} else {
 			cmd = fmt.Sprintf(cmd, fmt.Sprintf(`"%s"`, opt.Name))
 		}
-		str, err := Exec(ExecOpt{Quiet: true, Terminal: TermCmd}, cmd)
+		str, err := execute(ExecOpt{Quiet: true, Terminal: TermCmd}, cmd)
 		if err != nil {
 			return nil, fmt.Errorf("%s%s", str, err)
 		}
@@ -60,7 +83,7 @@ func IPTablesList(opt IPTablesListOpt) (rules []FireWareRule, err error) {
 			args = NewArgsBuilder("iptables -L")
 		}
 		args.AddString("%s", opt.Chain)
-		str, err := Exec(ExecOpt{Quiet: true}, args.Build())
+		str, err := execute(ExecOpt{Quiet: true}, args.Build())
 		if err != nil {
 			return nil, err
 		}
@@ -73,7 +96,7 @@ func IPTablesList(opt IPTablesListOpt) (rules []FireWareRule, err error) {
 		}
 	case "darwin":
 		cmd := "pfctl -sr"
-		str, err := Exec(ExecOpt{Quiet: true}, cmd)
+		str, err := execute(ExecOpt{Quiet: true}, cmd)
 		if err != nil {
 			return nil, err
 		}
@@ -362,6 +385,12 @@ func (opt IPTablesOpOpt) ToLinux() string {
 }
 
 func IPTablesOp(opt IPTablesOpOpt) error {
+	return playback("IPTablesOp", []any{opt}, nil, func() error {
+		return ipTablesOp(opt)
+	})
+}
+
+func ipTablesOp(opt IPTablesOpOpt) error {
 	var term *Terminal
 	switch util.CurPlatform.OS {
 	case "windows":
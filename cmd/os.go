@@ -4,7 +4,11 @@
 
 package yockc
 
-import "github.com/ansurfen/yock/util"
+import (
+	"io"
+
+	"github.com/ansurfen/yock/util"
+)
 
 // ExecOpt indicates configuration of exec
 type ExecOpt struct {
@@ -15,9 +19,23 @@ type ExecOpt struct {
 	Sandbox bool
 
 	Terminal uint8
+
+	// Output receives stdout and stderr of command as they're written,
+	// when it isn't redirected.
+	Output io.Writer `json:"-"`
 }
 
+// Exec runs cmd in the terminal of platform, or the one specified by opt
 func Exec(opt ExecOpt, cmd string) (string, error) {
+	var out string
+	err := playback("Exec", []any{opt, cmd}, &out, func() (err error) {
+		out, err = execute(opt, cmd)
+		return err
+	})
+	return out, err
+}
+
+func execute(opt ExecOpt, cmd string) (string, error) {
 	cmd = aliasMap(cmd)
 	var term *Terminal
 	switch opt.Terminal {
@@ -71,6 +71,8 @@ type SSHOpt struct {
 // SSHClient packs the SSH connection
 type SSHClient struct {
 	*ssh.Client
+	// addr tells the records of clients apart in cassette
+	addr string
 }
 
 func newSSHClient(opt SSHOpt) (*SSHClient, error) {
@@ -81,17 +83,25 @@ func newSSHClient(opt SSHOpt) (*SSHClient, error) {
 		},
 		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
 	}
-	conn, err := ssh.Dial(opt.Network, fmt.Sprintf("%s:%d", opt.IP, opt.Port), conf)
+	addr := fmt.Sprintf("%s:%d", opt.IP, opt.Port)
+	conn, err := ssh.Dial(opt.Network, addr, conf)
 	if err != nil {
 		return nil, err
 	}
 	return &SSHClient{
 		Client: conn,
+		addr:   addr,
 	}, nil
 }
 
 // Put uploads local files to a remote server
 func (cli *SSHClient) Put(src, dst string) error {
+	return playback("SSHClient.Put", []any{cli.addr, src, dst}, nil, func() error {
+		return cli.put(src, dst)
+	})
+}
+
+func (cli *SSHClient) put(src, dst string) error {
 	sftpClient, err := sftp.NewClient(cli.Client)
 	if err != nil {
 		return err
@@ -115,6 +125,12 @@ func (cli *SSHClient) Put(src, dst string) error {
 
 // Get download remote file to localhost from remote server
 func (cli *SSHClient) Get(src, dst string) error {
+	return playback("SSHClient.Get", []any{cli.addr, src, dst}, nil, func() error {
+		return cli.get(src, dst)
+	})
+}
+
+func (cli *SSHClient) get(src, dst string) error {
 	sftpClient, err := sftp.NewClient(cli.Client)
 	if err != nil {
 		return err
@@ -139,6 +155,15 @@ func (cli *SSHClient) Get(src, dst string) error {
 
 // Exec creates a temporary session to execute commands
 func (cli *SSHClient) Exec(cmd string) (string, error) {
+	var out string
+	err := playback("SSHClient.Exec", []any{cli.addr, cmd}, &out, func() (err error) {
+		out, err = cli.exec(cmd)
+		return err
+	})
+	return out, err
+}
+
+func (cli *SSHClient) exec(cmd string) (string, error) {
 	session, err := cli.NewSession()
 	if err != nil {
 		return "", fmt.Errorf("%s: %s", util.ErrCreateSession.Error(), err.Error())
@@ -269,8 +294,18 @@ type SSHCenter struct {
 	clients []*SSHClient
 }
 
+// NewSSHClient connects the server of opt. The client replayed by cassette
+// never connects, and only the methods recorded can be called.
 func NewSSHClient(opt SSHOpt) (*SSHClient, error) {
-	cli, err := newSSHClient(opt)
+	var cli *SSHClient
+	args := []any{opt.User, opt.IP, opt.Port, opt.Network}
+	err := playback("NewSSHClient", args, nil, func() (err error) {
+		cli, err = newSSHClient(opt)
+		return err
+	})
+	if err == nil && cli == nil {
+		cli = &SSHClient{addr: fmt.Sprintf("%s:%d", opt.IP, opt.Port)}
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -16,6 +16,12 @@ import (
 )
 
 func SystemCtlCreate(name string, opt SCCreateOpt) error {
+	return playback("SystemCtlCreate", []any{name, opt}, nil, func() error {
+		return systemCtlCreate(name, opt)
+	})
+}
+
+func systemCtlCreate(name string, opt SCCreateOpt) error {
 	switch util.CurPlatform.OS {
 	case "windows":
 		return systemCtlCreateWindows(name, opt)
@@ -171,11 +177,28 @@ var (
 )
 
 func SystemCtlStatus(opt SystemCtlStatusOpt) (infos []ServiceInfo, err error) {
+	records := []serviceRecord{}
+	err = playback("SystemCtlStatus", []any{opt}, &records, func() error {
+		infos, err = systemCtlStatus(opt)
+		for _, info := range infos {
+			records = append(records, serviceRecord{ID: info.PID(), Unit: info.Name(), Active: info.Status()})
+		}
+		return err
+	})
+	if infos == nil {
+		for _, record := range records {
+			infos = append(infos, record)
+		}
+	}
+	return infos, err
+}
+
+func systemCtlStatus(opt SystemCtlStatusOpt) (infos []ServiceInfo, err error) {
 	switch util.CurPlatform.OS {
 	case "windows":
 		args := NewArgsBuilder("sc queryex").AddString("%s", opt.Name).
 			AddString("type=%s", opt.Type).AddString("state=%s", opt.Status)
-		str, err := Exec(ExecOpt{Terminal: TermCmd, Quiet: true}, args.Build())
+		str, err := execute(ExecOpt{Terminal: TermCmd, Quiet: true}, args.Build())
 		if err != nil {
 			return nil, fmt.Errorf("%s%s", str, err)
 		}
@@ -193,7 +216,7 @@ func SystemCtlStatus(opt SystemCtlStatusOpt) (infos []ServiceInfo, err error) {
 		}
 		args := NewArgsBuilder("systemctl list-units").
 			AddString("--type=%s", opt.Type).AddString(status, opt.Status)
-		str, err := Exec(ExecOpt{Terminal: TermCmd, Quiet: true}, args.Build())
+		str, err := execute(ExecOpt{Terminal: TermCmd, Quiet: true}, args.Build())
 		if err != nil {
 			return nil, fmt.Errorf("%s%s", str, err)
 		}
@@ -206,7 +229,7 @@ func SystemCtlStatus(opt SystemCtlStatusOpt) (infos []ServiceInfo, err error) {
 		}
 	case "darwin":
 		args := NewArgsBuilder("launchctl list")
-		str, err := Exec(ExecOpt{Quiet: true}, args.Build())
+		str, err := execute(ExecOpt{Quiet: true}, args.Build())
 		if err != nil {
 			return nil, fmt.Errorf("%s%s", str, err)
 		}
@@ -447,11 +470,20 @@ func systemCtlStatusDarwin(str string) (infos []darwinServiceInfo, err error) {
 }
 
 func SystemCtlIsEnable(name string) bool {
+	enabled := false
+	playback("SystemCtlIsEnable", []any{name}, &enabled, func() error {
+		enabled = systemCtlIsEnable(name)
+		return nil
+	})
+	return enabled
+}
+
+func systemCtlIsEnable(name string) bool {
 	switch util.CurPlatform.OS {
 	case "windows":
 	case "linux":
 		args := NewArgsBuilder("systemctl is-enabled").AddString("%s", name)
-		str, err := Exec(ExecOpt{}, args.Build())
+		str, err := execute(ExecOpt{}, args.Build())
 		if err != nil {
 			return false
 		}
@@ -464,6 +496,12 @@ func SystemCtlIsEnable(name string) bool {
 }
 
 func SystemCtlStart(server string) error {
+	return playback("SystemCtlStart", []any{server}, nil, func() error {
+		return systemCtlStart(server)
+	})
+}
+
+func systemCtlStart(server string) error {
 	cmd := ""
 	switch util.CurPlatform.OS {
 	case "windows":
@@ -479,11 +517,17 @@ func SystemCtlStart(server string) error {
 	default:
 		panic("no support")
 	}
-	_, err := Exec(ExecOpt{Quiet: true}, fmt.Sprintf(cmd, server))
+	_, err := execute(ExecOpt{Quiet: true}, fmt.Sprintf(cmd, server))
 	return err
 }
 
 func SystemCtlStop(server string) error {
+	return playback("SystemCtlStop", []any{server}, nil, func() error {
+		return systemCtlStop(server)
+	})
+}
+
+func systemCtlStop(server string) error {
 	cmd := ""
 	switch util.CurPlatform.OS {
 	case "windows":
@@ -495,11 +539,17 @@ func SystemCtlStop(server string) error {
 	default:
 		panic("no support")
 	}
-	_, err := Exec(ExecOpt{Quiet: true}, fmt.Sprintf(cmd, server))
+	_, err := execute(ExecOpt{Quiet: true}, fmt.Sprintf(cmd, server))
 	return err
 }
 
 func SystemCtlDisable(server string) error {
+	return playback("SystemCtlDisable", []any{server}, nil, func() error {
+		return systemCtlDisable(server)
+	})
+}
+
+func systemCtlDisable(server string) error {
 	cmd := ""
 	switch util.CurPlatform.OS {
 	case "windows":
@@ -511,24 +561,30 @@ func SystemCtlDisable(server string) error {
 	default:
 		panic("no support")
 	}
-	_, err := Exec(ExecOpt{Quiet: true}, fmt.Sprintf(cmd, server))
+	_, err := execute(ExecOpt{Quiet: true}, fmt.Sprintf(cmd, server))
 	return err
 }
 
 func SystemCtlDelete(server string) error {
+	return playback("SystemCtlDelete", []any{server}, nil, func() error {
+		return systemCtlDelete(server)
+	})
+}
+
+func systemCtlDelete(server string) error {
 	switch util.CurPlatform.OS {
 	case "windows":
-		str, err := Exec(ExecOpt{Quiet: true, Terminal: TermCmd}, fmt.Sprintf("sc delete %s", server))
+		str, err := execute(ExecOpt{Quiet: true, Terminal: TermCmd}, fmt.Sprintf("sc delete %s", server))
 		if err != nil {
 			return fmt.Errorf("%s%s", str, err)
 		}
 		return nil
 	case "linux":
-		err := SystemCtlStop(server)
+		err := systemCtlStop(server)
 		if err != nil {
 			return err
 		}
-		err = SystemCtlDisable(server)
+		err = systemCtlDisable(server)
 		if err != nil {
 			return err
 		}
@@ -555,6 +611,12 @@ func SystemCtlDelete(server string) error {
 }
 
 func SystemCtlRelaod(server string) error {
+	return playback("SystemCtlRelaod", []any{server}, nil, func() error {
+		return systemCtlRelaod(server)
+	})
+}
+
+func systemCtlRelaod(server string) error {
 	cmd := ""
 	switch util.CurPlatform.OS {
 	case "windows":
@@ -566,6 +628,6 @@ func SystemCtlRelaod(server string) error {
 	default:
 		panic("no support")
 	}
-	_, err := Exec(ExecOpt{Quiet: true}, cmd)
+	_, err := execute(ExecOpt{Quiet: true}, cmd)
 	return err
 }
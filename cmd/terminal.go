@@ -64,7 +64,19 @@ func (term *Terminal) Exec(opt *ExecOpt) ([]byte, error) {
 		return out.Bytes(), err
 	}
 
-	out, err := cmd.CombinedOutput()
+	var (
+		out []byte
+		err error
+	)
+	if opt.Output != nil {
+		var buf bytes.Buffer
+		w := io.MultiWriter(&buf, opt.Output)
+		cmd.Stdout, cmd.Stderr = w, w
+		err = cmd.Run()
+		out = buf.Bytes()
+	} else {
+		out, err = cmd.CombinedOutput()
+	}
 
 	switch util.CurPlatform.Lang {
 	case "zh":
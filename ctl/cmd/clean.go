@@ -10,20 +10,33 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var cleanCmd = &cobra.Command{
-	Use:   "clean [pattern]",
-	Short: `Clean deletes Yock's cache`,
-	Long: `Clean deletes Yock's cache. You can append the pattern parameter
-to delete the specified file by matching the regular expression.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		pattern := ".*"
-		if len(args) > 0 {
-			pattern = args[0]
-		}
-		yockc.Rm(yockc.RmOpt{Pattern: pattern, Safe: false}, util.Pathf("@/tmp"))
-	},
+type cleanCmdParameter struct {
+	fingerprint bool
 }
 
+var (
+	cleanParameter cleanCmdParameter
+	cleanCmd       = &cobra.Command{
+		Use:   "clean [pattern]",
+		Short: `Clean deletes Yock's cache`,
+		Long: `Clean deletes Yock's cache. You can append the pattern parameter
+to delete the specified file by matching the regular expression.
+With --fingerprint, the fingerprints of incremental jobs are deleted as well,
+so that every job runs again on next time.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			pattern := ".*"
+			if len(args) > 0 {
+				pattern = args[0]
+			}
+			yockc.Rm(yockc.RmOpt{Pattern: pattern, Safe: false}, util.Pathf("@/tmp"))
+			if cleanParameter.fingerprint {
+				yockc.Rm(yockc.RmOpt{Pattern: pattern, Safe: false}, util.Pathf("@/cache/fingerprint"))
+			}
+		},
+	}
+)
+
 func init() {
 	yockCmd.AddCommand(cleanCmd)
+	cleanCmd.PersistentFlags().BoolVarP(&cleanParameter.fingerprint, "fingerprint", "f", false, "delete fingerprints of incremental jobs")
 }
@@ -0,0 +1,85 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	yockpack "github.com/ansurfen/yock/pack"
+	"github.com/ansurfen/yock/util"
+	"github.com/ansurfen/yock/ycho"
+	"github.com/spf13/cobra"
+)
+
+type fmtCmdParameter struct {
+	check bool
+}
+
+var (
+	fmtParameter fmtCmdParameter
+	fmtCmd       = &cobra.Command{
+		Use:   "fmt [files...]",
+		Short: `Fmt formats the scripts in place`,
+		Long: `Fmt formats the scripts in place, and the lua files of directory are formatted
+recursively. Comments and single blank lines between statements are kept, so that
+formatting twice makes no difference. With --check, fmt prints the files not formatted
+instead of rewriting them, and exits with 1 when there is any.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) == 0 {
+				ycho.Fatal(util.ErrFileNotExist)
+			}
+			files := []string{}
+			for _, arg := range args {
+				err := filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+					if err != nil {
+						return err
+					}
+					// the file given explicitly is formatted whatever its extension is
+					if !d.IsDir() && (path == arg || strings.HasSuffix(path, ".lua")) {
+						files = append(files, path)
+					}
+					return nil
+				})
+				if err != nil {
+					ycho.Fatal(err)
+				}
+			}
+			yockp := yockpack.New()
+			unformatted := false
+			for _, file := range files {
+				raw, err := os.ReadFile(file)
+				if err != nil {
+					ycho.Fatal(err)
+				}
+				out, err := yockp.Format(string(raw))
+				if err != nil {
+					ycho.Fatalf("%s: %s", file, err)
+				}
+				if out == string(raw) {
+					continue
+				}
+				if fmtParameter.check {
+					unformatted = true
+					ycho.Println(file)
+					continue
+				}
+				if err = util.WriteFile(file, []byte(out)); err != nil {
+					ycho.Fatal(err)
+				}
+			}
+			if unformatted {
+				os.Exit(1)
+			}
+		},
+	}
+)
+
+func init() {
+	yockCmd.AddCommand(fmtCmd)
+	fmtCmd.PersistentFlags().BoolVar(&fmtParameter.check, "check", false, "list the files not formatted and exit with 1 instead of rewriting them")
+}
@@ -0,0 +1,69 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"os"
+
+	yockpack "github.com/ansurfen/yock/pack"
+	"github.com/ansurfen/yock/util"
+	"github.com/ansurfen/yock/ycho"
+	"github.com/spf13/cobra"
+)
+
+type lintCmdParameter struct {
+	format  string
+	include []string
+}
+
+var (
+	lintParameter lintCmdParameter
+	lintCmd       = &cobra.Command{
+		Use:   "lint [files...]",
+		Short: `Lint checks the yock-specific mistakes of scripts`,
+		Long: `Lint checks the yock-specific mistakes of scripts, such as duplicate job names,
+jobs referencing undefined jobs, wait on signals never notified, unused option.flags
+entries and shadowed stdlib globals. The issues can be printed in text, json or sarif,
+and lint exits with 1 when any error is found.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) == 0 {
+				ycho.Fatal(util.ErrFileNotExist)
+			}
+			include := lintParameter.include
+			if len(include) == 0 {
+				include = []string{util.Pathf("~/lib/include")}
+			}
+			ann := yockpack.NewAnnotations()
+			for _, dir := range include {
+				if err := ann.LoadDir(dir); err != nil {
+					ycho.Fatal(err)
+				}
+			}
+			linter := yockpack.NewLinter(ann)
+			issues := []yockpack.LintIssue{}
+			for _, file := range args {
+				res, err := linter.LintFile(file)
+				if err != nil {
+					ycho.Fatal(err)
+				}
+				issues = append(issues, res...)
+			}
+			if err := yockpack.WriteLint(os.Stdout, lintParameter.format, issues); err != nil {
+				ycho.Fatal(err)
+			}
+			for _, issue := range issues {
+				if issue.Severity == "error" {
+					os.Exit(1)
+				}
+			}
+		},
+	}
+)
+
+func init() {
+	yockCmd.AddCommand(lintCmd)
+	lintCmd.PersistentFlags().StringVarP(&lintParameter.format, "format", "f", "text", "output format, text, json or sarif")
+	lintCmd.PersistentFlags().StringSliceVarP(&lintParameter.include, "include", "i", nil, "directories of annotations, ~/lib/include by default")
+}
@@ -0,0 +1,49 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"os"
+
+	yockpack "github.com/ansurfen/yock/pack"
+	"github.com/ansurfen/yock/util"
+	"github.com/ansurfen/yock/ycho"
+	"github.com/spf13/cobra"
+)
+
+type lspCmdParameter struct {
+	include []string
+}
+
+var (
+	lspParameter lspCmdParameter
+	lspCmd       = &cobra.Command{
+		Use:   "lsp",
+		Short: `Lsp starts the language server of yock script over stdio`,
+		Long: `Lsp starts the language server of yock script over stdio, which provides
+completion, hover, go-to-definition and diagnostics of undefined globals for editors.
+The yock stdlib is learned from the annotations in lib/include.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			include := lspParameter.include
+			if len(include) == 0 {
+				include = []string{util.Pathf("~/lib/include")}
+			}
+			ann := yockpack.NewAnnotations()
+			for _, dir := range include {
+				if err := ann.LoadDir(dir); err != nil {
+					ycho.Fatal(err)
+				}
+			}
+			if err := yockpack.NewLanguageServer(ann).Serve(os.Stdin, os.Stdout); err != nil {
+				ycho.Fatal(err)
+			}
+		},
+	}
+)
+
+func init() {
+	yockCmd.AddCommand(lspCmd)
+	lspCmd.PersistentFlags().StringSliceVarP(&lspParameter.include, "include", "i", nil, "directories of annotations, ~/lib/include by default")
+}
@@ -7,8 +7,12 @@ package cmd
 import (
 	"os"
 	"path/filepath"
-	"sync"
+	"strings"
+	"time"
 
+	yockc "github.com/ansurfen/yock/cmd"
+	"github.com/ansurfen/yock/ctl/conf"
+	yocke "github.com/ansurfen/yock/env"
 	yockpack "github.com/ansurfen/yock/pack"
 	yockr "github.com/ansurfen/yock/runtime"
 	yocks "github.com/ansurfen/yock/scheduler"
@@ -24,6 +28,16 @@ type runCmdParameter struct {
 	enableAnalyse bool
 	debug         bool
 	cooperate     bool
+	plan          bool
+	dap           string
+	noCache       bool
+	sandbox       string
+	record        string
+	replay        string
+	runID         string
+	signalTTL     time.Duration
+	timeout       time.Duration
+	maxMemory     uint64
 }
 
 var (
@@ -46,21 +60,93 @@ var (
 				}
 				skip++
 			}
-			for _, arg := range os.Args[skip:] {
+			for i := skip; i < len(os.Args); i++ {
+				arg := os.Args[i]
 				if arg == "--" {
 					break
 				}
-				if arg == "-c" || arg == "-p" || arg == "-a" || arg == "-d" {
+				if arg == "-c" || arg == "-p" || arg == "-a" || arg == "-d" || arg == "--plan" || arg == "--no-cache" || strings.HasPrefix(arg, "--dap=") || strings.HasPrefix(arg, "--sandbox=") ||
+					strings.HasPrefix(arg, "--record=") || strings.HasPrefix(arg, "--replay=") ||
+					strings.HasPrefix(arg, "--run-id=") || strings.HasPrefix(arg, "--signal-ttl=") ||
+					strings.HasPrefix(arg, "--timeout=") || strings.HasPrefix(arg, "--max-memory=") {
+					continue
+				}
+				if arg == "--dap" || arg == "--sandbox" || arg == "--record" || arg == "--replay" ||
+					arg == "--run-id" || arg == "--signal-ttl" || arg == "--timeout" || arg == "--max-memory" {
+					i++
 					continue
 				}
 				runParameter.modes = append(runParameter.modes, arg)
 			}
 
-			opts := []yocks.YockSchedulerOption{
-				yocks.OptionLibPath(util.Pathf("~/lib/yock")),
+			var cassette *yockc.Cassette
+			if len(runParameter.record) > 0 {
+				cassette = yockc.RecordCassette(runParameter.record)
+			} else if len(runParameter.replay) > 0 {
+				var err error
+				if cassette, err = yockc.ReplayCassette(runParameter.replay); err != nil {
+					ycho.Fatal(err)
+				}
+			}
+			yockc.UseCassette(cassette)
+			// saveCassette is called before exiting, so that the calls
+			// before failure are recorded as well.
+			saveCassette := func() {
+				if cassette == nil {
+					return
+				}
+				if err := cassette.Save(); err != nil {
+					ycho.Warn(err)
+				}
+				for _, record := range cassette.Unused() {
+					ycho.Warnf("%s %s isn't replayed", record.Call, record.Args)
+				}
+			}
+
+			goroutine := yocke.GetEnv[*conf.YockConf]().Conf().Yocks.Goroutine
+			// the limits of flags take precedence over the ones of configuration
+			limit := yocke.GetEnv[*conf.YockConf]().Conf().Yocks.Limit
+			if runParameter.timeout > 0 {
+				limit.Timeout = runParameter.timeout
+			}
+			if runParameter.maxMemory > 0 {
+				limit.MaxMemory = runParameter.maxMemory
+			}
+			runtime := []yockr.YockrOption{}
+			if limit.CallStackSize > 0 {
+				runtime = append(runtime, yockr.OptionCallStackSize(limit.CallStackSize))
+			}
+			if limit.RegistrySize > 0 || limit.RegistryMaxSize > 0 {
+				runtime = append(runtime, yockr.OptionRegistrySize(limit.RegistrySize, limit.RegistryMaxSize))
+			}
+			if limit.MaxMemory > 0 {
+				runtime = append(runtime, yockr.OptionMaxMemory(limit.MaxMemory))
+			}
+			if limit.Timeout > 0 {
+				runtime = append(runtime, yockr.OptionTimeout(limit.Timeout))
+			}
+			opts := []yocks.YockSchedulerOption{}
+			if len(runtime) > 0 {
+				// the runtime is created first, since others change its state
+				opts = append(opts, yocks.OptionRuntime(runtime...))
 			}
+			opts = append(opts,
+				yocks.OptionLibPath(util.Pathf("~/lib/yock")),
+				yocks.OptionMaxGoroutine(int(goroutine.MaxGoroutine)))
 			if runParameter.cooperate {
-				opts = append(opts, yocks.OptionUpgradeSingalStream())
+				opts = append(opts, yocks.OptionUpgradeSingalStream(runParameter.runID, runParameter.signalTTL))
+			}
+			var cache *yockr.BytecodeCache
+			if !runParameter.noCache {
+				cache = yockr.NewBytecodeCache(util.Pathf("~/cache/bytecode"))
+				opts = append(opts, yocks.OptionBytecodeCache(cache))
+			}
+			if len(runParameter.sandbox) > 0 {
+				sb, err := yockr.LoadSandbox(runParameter.sandbox)
+				if err != nil {
+					ycho.Fatal(err)
+				}
+				opts = append(opts, yocks.OptionSandbox(sb))
 			}
 			// if runParameter.enableAnalyse {
 			// 	opts = append(opts, yocks.OptionEnableYockDriverMode())
@@ -70,28 +156,68 @@ var (
 			go yocks.EventLoop()
 			go ycho.Eventloop()
 
-			yockp := yockpack.New()
-			fn := yockp.Compile(yockpack.CompileOpt{
+			compileOpt := yockpack.CompileOpt{
 				DisableAnalyse: runParameter.enableAnalyse,
 				VM:             yocks.YockRuntime,
-			}, runParameter.file)
+				Cache:          cache,
+			}
+			var dap *yockr.DAPServer
+			if len(runParameter.dap) > 0 && !runParameter.plan {
+				dap = yockr.NewDAPServer()
+				dap.Debugger().Attach(yocks.State())
+				dap.Debugger().SetThreadNamer(yocks.TaskOf)
+				compileOpt.DebugHook = yockr.DebugHookName
+				ycho.Infof("waiting for debug adapter client on %s", runParameter.dap)
+				if err := dap.ListenAndServe(runParameter.dap); err != nil {
+					ycho.Fatal(err)
+				}
+				dap.WaitConfigured()
+			} else if runParameter.debug && !runParameter.plan {
+				dbg := yockr.NewDebugger(os.Stdin, os.Stdout)
+				dbg.Attach(yocks.State())
+				dbg.SetThreadNamer(yocks.TaskOf)
+				compileOpt.DebugHook = yockr.DebugHookName
+			}
+			yockp := yockpack.New()
+			fn := yockp.Compile(compileOpt, runParameter.file)
 
 			if err := yockr.LuaDoFunc(yocks.State().LState(), fn); err != nil {
+				if dap != nil {
+					dap.Terminate(1)
+				}
+				saveCassette()
 				ycho.Fatal(err)
 			}
 
-			var tasks sync.WaitGroup
-			for _, mode := range runParameter.modes {
-				tasks.Add(1)
-				if runParameter.debug {
+			if runParameter.plan {
+				yocks.Plan(runParameter.modes...).Print()
+				return
+			}
+
+			if runParameter.debug {
+				for _, mode := range runParameter.modes {
 					ycho.Infof("%s start to run", mode)
 				}
-				go func(mode string) {
-					yocks.LaunchTask(mode)
-					tasks.Done()
-				}(mode)
 			}
-			tasks.Wait()
+			// the needs shared by modes only run once
+			report := yocks.LaunchTasks(runParameter.modes...)
+			if !yocks.Shutdown(goroutine.DrainTimeout) {
+				ycho.Warnf("goroutines aren't finished after %s", goroutine.DrainTimeout)
+			}
+			saveCassette()
+			if len(report.Results) > 0 && (runParameter.debug || report.Failed()) {
+				report.Print()
+			}
+			if dap != nil {
+				code := 0
+				if report.Failed() {
+					code = 1
+				}
+				dap.Terminate(code)
+			}
+			if report.Failed() {
+				os.Exit(1)
+			}
 		},
 	}
 )
@@ -100,6 +226,16 @@ func init() {
 	yockCmd.AddCommand(runCmd)
 	runCmd.PersistentFlags().BoolVarP(&runParameter.protect, "protect", "p", false, "")
 	runCmd.PersistentFlags().BoolVarP(&runParameter.enableAnalyse, "analyze", "a", false, "enable dependency analyse mode")
-	runCmd.PersistentFlags().BoolVarP(&runParameter.debug, "debug", "d", false, "print the information of launch")
+	runCmd.PersistentFlags().BoolVarP(&runParameter.debug, "debug", "d", false, "debug script step by step, and print the information of launch")
 	runCmd.PersistentFlags().BoolVarP(&runParameter.cooperate, "cooperate", "c", false, "enable daemon to meet distributed system")
+	runCmd.PersistentFlags().StringVar(&runParameter.runID, "run-id", "", "namespace of signals on daemon (e.g. the id of pipeline), which keeps concurrent runs from colliding")
+	runCmd.PersistentFlags().DurationVar(&runParameter.signalTTL, "signal-ttl", 0, "how long the signals on daemon live (e.g. 24h), forever by default")
+	runCmd.PersistentFlags().BoolVar(&runParameter.plan, "plan", false, "print the execution plan without running any job")
+	runCmd.PersistentFlags().StringVar(&runParameter.dap, "dap", "", "serve debug adapter protocol on address (e.g. 127.0.0.1:4711) and wait for editor to attach")
+	runCmd.PersistentFlags().BoolVar(&runParameter.noCache, "no-cache", false, "parse and compile the script and libraries without bytecode cache")
+	runCmd.PersistentFlags().StringVar(&runParameter.record, "record", "", "record the side effects of commands into cassette file")
+	runCmd.PersistentFlags().StringVar(&runParameter.replay, "replay", "", "replay the side effects of commands from cassette file without touching system")
+	runCmd.PersistentFlags().StringVar(&runParameter.sandbox, "sandbox", "", "run the script with the capabilities granted by profile (e.g. profile.yaml)")
+	runCmd.PersistentFlags().DurationVar(&runParameter.timeout, "timeout", 0, "the budget of wall clock for each state of script (e.g. 10m), yocks.limit.timeout of configuration by default")
+	runCmd.PersistentFlags().Uint64Var(&runParameter.maxMemory, "max-memory", 0, "cancel the script once the heap exceeds the bytes, yocks.limit.maxMemory of configuration by default")
 }
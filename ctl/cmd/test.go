@@ -0,0 +1,84 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"io"
+	"os"
+	"time"
+
+	yockpack "github.com/ansurfen/yock/pack"
+	yockr "github.com/ansurfen/yock/runtime"
+	"github.com/ansurfen/yock/util"
+	"github.com/ansurfen/yock/ycho"
+	"github.com/spf13/cobra"
+)
+
+type testCmdParameter struct {
+	format   string
+	output   string
+	parallel int
+	timeout  time.Duration
+	noCache  bool
+}
+
+var (
+	testParameter testCmdParameter
+	testCmd       = &cobra.Command{
+		Use:   "test [patterns...]",
+		Short: `Test runs the cases of *_test.lua`,
+		Long: `Test discovers the *_test.lua files under the directories or matched by the glob
+patterns (working directory by default), and runs each case declared by test("name", fn)
+in isolated state along with setup and teardown. The results can be reported in text,
+tap or junit, and test exits with 1 when any case fails.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			files, err := yockpack.DiscoverTests(args...)
+			if err != nil {
+				ycho.Fatal(err)
+			}
+			if len(files) == 0 {
+				ycho.Fatal(util.ErrFileNotExist)
+			}
+			opt := yockpack.TestOpt{
+				LibPath:  util.Pathf("~/lib/yock"),
+				Parallel: testParameter.parallel,
+				Timeout:  testParameter.timeout,
+			}
+			if !testParameter.noCache {
+				opt.Cache = yockr.NewBytecodeCache(util.Pathf("~/cache/bytecode"))
+			}
+			go ycho.Eventloop()
+			results := yockpack.RunTests(opt, files)
+
+			var w io.Writer = os.Stdout
+			if len(testParameter.output) > 0 {
+				fp, err := os.Create(testParameter.output)
+				if err != nil {
+					ycho.Fatal(err)
+				}
+				defer fp.Close()
+				w = fp
+			}
+			if err = yockpack.WriteTestReport(w, testParameter.format, results); err != nil {
+				ycho.Fatal(err)
+			}
+			if yockpack.TestsFailed(results) {
+				if fp, ok := w.(*os.File); ok {
+					fp.Close()
+				}
+				os.Exit(1)
+			}
+		},
+	}
+)
+
+func init() {
+	yockCmd.AddCommand(testCmd)
+	testCmd.PersistentFlags().StringVarP(&testParameter.format, "format", "f", "text", "report format, text, tap or junit")
+	testCmd.PersistentFlags().StringVarP(&testParameter.output, "output", "o", "", "write report into file instead of stdout")
+	testCmd.PersistentFlags().IntVarP(&testParameter.parallel, "parallel", "p", 1, "the number of cases run at the same time")
+	testCmd.PersistentFlags().DurationVar(&testParameter.timeout, "timeout", 0, "the budget of wall clock for each case (e.g. 30s), unlimited by default")
+	testCmd.PersistentFlags().BoolVar(&testParameter.noCache, "no-cache", false, "parse and compile the test files and libraries without bytecode cache")
+}
@@ -4,14 +4,41 @@
 
 package conf
 
+import "time"
+
 type yockScheduler struct {
 	Goroutine  yockGoroutine `yaml:"goroutine"`
+	Limit      yockLimit     `yaml:"limit"`
 	InterpPool bool          `yaml:"interPool"`
 	MaxInterp  int           `yaml:"maxInterp"`
 }
 
+// yockLimit bounds the states running scripts, and it's unlimited when 0.
+type yockLimit struct {
+	// CallStackSize limits the depth of calls in state
+	CallStackSize int `yaml:"callStackSize"`
+	// RegistrySize is the initial size of data stack in state,
+	// and it grows up to RegistryMaxSize.
+	RegistrySize    int `yaml:"registrySize"`
+	RegistryMaxSize int `yaml:"registryMaxSize"`
+	// MaxMemory cancels all states once the heap of process exceeds the bytes
+	MaxMemory uint64 `yaml:"maxMemory"`
+	// Timeout is the budget of wall clock for each state, including
+	// the ones of tasks and goroutines.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
 type yockGoroutine struct {
+	// MaxGoroutine limits the number of running tasks and goroutines respectively,
+	// and it's unlimited when MaxGoroutine <= 0.
+	//
+	// NOTE: the goroutines blocked by wait also hold the quota, so too small
+	// limit may cause deadlock when goroutines wait for what they spawn.
+	// Tasks have their own quota, and they're free to wait for goroutines.
 	MaxGoroutine int64 `yaml:"maxGoroutine"`
 	MaxWaitRound int64 `yaml:"maxWaitRound"`
 	RoundStep    int   `yaml:"roundStep"`
+	// DrainTimeout is the maximum time to wait for goroutines to finish
+	// when script exits, and it waits until all finish when DrainTimeout <= 0.
+	DrainTimeout time.Duration `yaml:"drainTimeout"`
 }
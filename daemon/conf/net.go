@@ -4,9 +4,12 @@
 
 package conf
 
+import "time"
+
 type YockdConfNet struct {
-	Proxy map[string]yockdConfNetProxy `yaml:"proxy"`
-	Stun  YockConfNetStun              `yaml:"stun"`
+	Proxy  map[string]yockdConfNetProxy `yaml:"proxy"`
+	Stun   YockConfNetStun              `yaml:"stun"`
+	Gossip YockdConfNetGossip           `yaml:"gossip"`
 }
 
 type yockdConfNetProxy struct {
@@ -17,3 +20,23 @@ type yockdConfNetProxy struct {
 type YockConfNetStun struct {
 	RetryCnt int `yaml:"retryCnt"`
 }
+
+// YockdConfNetGossip indicates how yockd joins the cluster and detects the failure of members
+type YockdConfNetGossip struct {
+	// Advertise is the address dialed by peers, and the address of grpc is used by default.
+	Advertise string `yaml:"advertise"`
+	// Seeds are the addresses of members to join the cluster at first
+	Seeds []string `yaml:"seeds"`
+	// Labels describe what the node is able to do, e.g. gpu, docker
+	Labels []string `yaml:"labels"`
+	// Fanout is the number of peers gossiped in each round, 3 by default.
+	Fanout int `yaml:"fanout"`
+	// Interval is the period of round, 1s by default.
+	Interval time.Duration `yaml:"interval"`
+	// SuspectTimeout is how long the member is suspected since its heartbeat last increased, 5s by default.
+	SuspectTimeout time.Duration `yaml:"suspectTimeout"`
+	// DeadTimeout is how long the member is declared dead since its heartbeat last increased, 30s by default.
+	DeadTimeout time.Duration `yaml:"deadTimeout"`
+	// EvictTimeout is how long the member is removed since its heartbeat last increased, 5m by default.
+	EvictTimeout time.Duration `yaml:"evictTimeout"`
+}
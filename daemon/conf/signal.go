@@ -0,0 +1,15 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package conf
+
+import "time"
+
+// YockdConfSignal indicates where signals are persisted and how they expire
+type YockdConfSignal struct {
+	// Store is the file of database keeping signals, and it's @/signal.db by default.
+	Store string `yaml:"store"`
+	// SweepInterval is the period of removing expired signals, 1m by default.
+	SweepInterval time.Duration `yaml:"sweepInterval"`
+}
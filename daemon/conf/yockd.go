@@ -16,6 +16,7 @@ type YockdConf struct {
 	Grpc    yockdConfGrpc    `yaml:"grpc"`
 	Gateway yockdConfGateway `yaml:"gateway"`
 	Net     YockdConfNet     `yaml:"net"`
+	Signal  YockdConfSignal  `yaml:"signal"`
 	Ycho    ycho.YchoOpt     `yaml:"ycho"`
 }
 
@@ -5,6 +5,7 @@
 package kernel
 
 import (
+	"github.com/ansurfen/yock/daemon/conf"
 	"github.com/ansurfen/yock/daemon/fs"
 	"github.com/ansurfen/yock/daemon/mem"
 	"github.com/ansurfen/yock/daemon/net"
@@ -22,16 +23,21 @@ type YockKernel struct {
 	*SignalStream
 }
 
-func NewKernel() *YockKernel {
+func NewKernel(opt *conf.YockdConf) *YockKernel {
 	ycho.Info("kernel init")
+	signals, err := newSingalStream(opt.Signal)
+	if err != nil {
+		ycho.Fatalf("fail to open signal store, %s", err)
+	}
 	return &YockKernel{
 		FileSystem:     fs.NewFileSystem(),
-		SignalStream:   newSingalStream(),
-		NetworkManager: net.NewNetworkManager(),
+		SignalStream:   signals,
+		NetworkManager: net.NewNetworkManager(opt),
 		Scheduler:      process.NewScheduler(),
 	}
 }
 
 func (k *YockKernel) Init() {
+	go k.SignalStream.Sweeping()
 	k.Scheduler.Run()
 }
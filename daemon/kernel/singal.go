@@ -5,66 +5,243 @@
 package kernel
 
 import (
-	"sync"
+	"encoding/json"
+	"sort"
+	"time"
 
+	"github.com/ansurfen/yock/daemon/conf"
 	du "github.com/ansurfen/yock/daemon/util"
 	yocki "github.com/ansurfen/yock/interface"
 	"github.com/ansurfen/yock/util"
+	"github.com/ansurfen/yock/ycho"
+	bolt "go.etcd.io/bbolt"
 )
 
+// defaultSignalNamespace is the namespace of global signals
+const defaultSignalNamespace = "default"
+
+// SignalStream keeps the signals of users in an on-disk database, so that
+// they survive the restart of daemon. Signals are grouped by namespace, which
+// is a bucket of database, and the expired signals are treated as absent
+// until they're swept.
 type SignalStream struct {
-	userSignals *util.SafeMap[bool]
-	mut         *sync.Mutex
+	db    *bolt.DB
+	sweep time.Duration
+	now   func() time.Time
 
 	sysSignals *du.Promise
 	event      chan yocki.PromiseEvent
 }
 
-func newSingalStream() *SignalStream {
+// signalRecord is the value of signal in database
+type signalRecord struct {
+	Status bool `json:"status"`
+	// Expire is the unix nano time when signal expires, and it never expires when 0.
+	Expire int64 `json:"expire,omitempty"`
+}
+
+func (r signalRecord) expired(now time.Time) bool {
+	return r.Expire > 0 && now.UnixNano() >= r.Expire
+}
+
+func newSingalStream(opt conf.YockdConfSignal) (*SignalStream, error) {
+	if len(opt.Store) == 0 {
+		opt.Store = "@/signal.db"
+	}
+	if opt.SweepInterval <= 0 {
+		opt.SweepInterval = time.Minute
+	}
+	db, err := bolt.Open(util.Pathf(opt.Store), 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
 	return &SignalStream{
-		userSignals: util.NewSafeMap[bool](),
-		mut:         &sync.Mutex{},
-		event:       make(chan yocki.PromiseEvent),
-		sysSignals:  du.NewPromise(),
+		db:         db,
+		sweep:      opt.SweepInterval,
+		now:        time.Now,
+		event:      make(chan yocki.PromiseEvent),
+		sysSignals: du.NewPromise(),
+	}, nil
+}
+
+func signalBucket(ns string) []byte {
+	if len(ns) == 0 {
+		ns = defaultSignalNamespace
 	}
+	return []byte(ns)
 }
 
-func (stream *SignalStream) Wait(sig string) bool {
-	v, ok := stream.userSignals.Get(sig)
-	if !ok {
-		stream.userSignals.SafeSet(sig, false)
+func (stream *SignalStream) expire(ttl time.Duration) int64 {
+	if ttl <= 0 {
+		return 0
 	}
-	return ok && v
+	return stream.now().Add(ttl).UnixNano()
 }
 
-func (stream *SignalStream) Notify(sig string) {
-	stream.userSignals.SafeSet(sig, true)
+// get returns the record of sig in bucket, and the expired is absent.
+func (stream *SignalStream) get(b *bolt.Bucket, sig string) (signalRecord, bool) {
+	r := signalRecord{}
+	if b == nil {
+		return r, false
+	}
+	raw := b.Get([]byte(sig))
+	if raw == nil || json.Unmarshal(raw, &r) != nil || r.expired(stream.now()) {
+		return r, false
+	}
+	return r, true
 }
 
-func (stream *SignalStream) Info(sig string) (bool, bool) {
-	return stream.userSignals.Get(sig)
+func put(b *bolt.Bucket, sig string, r signalRecord) error {
+	raw, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return b.Put([]byte(sig), raw)
 }
 
-func (stream *SignalStream) Clear(sigs ...string) {
-	stream.mut.Lock()
-	defer stream.mut.Unlock()
-	for _, sig := range sigs {
-		stream.userSignals.Delete(sig)
+// Wait returns the status of sig in namespace ns. The signal absent is
+// registered with false, which lives ttl.
+func (stream *SignalStream) Wait(ns, sig string, ttl time.Duration) (ok bool, err error) {
+	err = stream.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(signalBucket(ns))
+		if err != nil {
+			return err
+		}
+		if r, exist := stream.get(b, sig); exist {
+			ok = r.Status
+			return nil
+		}
+		return put(b, sig, signalRecord{Expire: stream.expire(ttl)})
+	})
+	return
+}
+
+// Notify sets sig in namespace ns true, and it lives ttl from now on.
+func (stream *SignalStream) Notify(ns, sig string, ttl time.Duration) error {
+	return stream.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(signalBucket(ns))
+		if err != nil {
+			return err
+		}
+		return put(b, sig, signalRecord{Status: true, Expire: stream.expire(ttl)})
+	})
+}
+
+// Info returns the status of sig in namespace ns and whether it exists
+func (stream *SignalStream) Info(ns, sig string) (status bool, exist bool, err error) {
+	err = stream.db.View(func(tx *bolt.Tx) error {
+		var r signalRecord
+		r, exist = stream.get(tx.Bucket(signalBucket(ns)), sig)
+		status = r.Status
+		return nil
+	})
+	return
+}
+
+// Clear removes sigs in namespace ns, and the whole namespace is removed when sigs are absent.
+func (stream *SignalStream) Clear(ns string, sigs ...string) error {
+	return stream.db.Update(func(tx *bolt.Tx) error {
+		if len(sigs) == 0 {
+			if err := tx.DeleteBucket(signalBucket(ns)); err != nil && err != bolt.ErrBucketNotFound {
+				return err
+			}
+			return nil
+		}
+		b := tx.Bucket(signalBucket(ns))
+		if b == nil {
+			return nil
+		}
+		for _, sig := range sigs {
+			if err := b.Delete([]byte(sig)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// List returns the signals in namespace ns, which aren't expired
+func (stream *SignalStream) List(ns string) ([]string, error) {
+	sigs := []string{}
+	err := stream.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(signalBucket(ns))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, _ []byte) error {
+			if _, ok := stream.get(b, string(k)); ok {
+				sigs = append(sigs, string(k))
+			}
+			return nil
+		})
+	})
+	sort.Strings(sigs)
+	return sigs, err
+}
+
+// Sweep removes the expired signals and the namespaces left empty,
+// and returns the number of signals removed.
+func (stream *SignalStream) Sweep() (cnt int, err error) {
+	err = stream.db.Update(func(tx *bolt.Tx) error {
+		empty := [][]byte{}
+		err := tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			expired, total := [][]byte{}, 0
+			err := b.ForEach(func(k, _ []byte) error {
+				total++
+				if _, ok := stream.get(b, string(k)); !ok {
+					expired = append(expired, k)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			for _, k := range expired {
+				if err = b.Delete(k); err != nil {
+					return err
+				}
+			}
+			cnt += len(expired)
+			if len(expired) == total {
+				empty = append(empty, name)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, name := range empty {
+			if err = tx.DeleteBucket(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return
+}
+
+// Sweeping sweeps the expired signals periodically, and it never returns.
+func (stream *SignalStream) Sweeping() {
+	ticker := time.NewTicker(stream.sweep)
+	defer ticker.Stop()
+	for range ticker.C {
+		if cnt, err := stream.Sweep(); err != nil {
+			ycho.Error(err)
+		} else if cnt > 0 {
+			ycho.Infof("sweep %d expired signals", cnt)
+		}
 	}
 }
 
-func (stream *SignalStream) List() []string {
-	return stream.userSignals.Keys()
+// Close closes the database of signals
+func (stream *SignalStream) Close() error {
+	return stream.db.Close()
 }
 
 func (stream *SignalStream) System() yocki.Promise {
 	return stream.sysSignals
 }
 
-func (stream *SignalStream) User() *util.SafeMap[bool] {
-	return stream.userSignals
-}
-
 func (stream *SignalStream) SystemEvent() chan yocki.PromiseEvent {
 	return stream.event
 }
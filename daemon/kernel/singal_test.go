@@ -0,0 +1,75 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package kernel
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ansurfen/yock/daemon/conf"
+)
+
+func TestSignalStream(t *testing.T) {
+	store := filepath.Join(t.TempDir(), "signal.db")
+	stream, err := newSingalStream(conf.YockdConfSignal{Store: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	stream.now = func() time.Time { return now }
+
+	// pipelines on the same daemon don't stomp on each other
+	if ok, err := stream.Wait("run-1", "build_done", 0); err != nil || ok {
+		t.Fatal("signal shouldn't be notified", ok, err)
+	}
+	if err = stream.Notify("run-2", "build_done", 0); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := stream.Wait("run-1", "build_done", 0); ok {
+		t.Fatal("signal of run-2 leaks into run-1")
+	}
+	if ok, _ := stream.Wait("run-2", "build_done", 0); !ok {
+		t.Fatal("signal of run-2 is lost")
+	}
+	stream.Notify("", "deploy_done", time.Minute)
+	if status, exist, _ := stream.Info("", "deploy_done"); !status || !exist {
+		t.Fatal("global signal is lost", status, exist)
+	}
+
+	// signals survive the restart of daemon
+	stream.Close()
+	if stream, err = newSingalStream(conf.YockdConfSignal{Store: store}); err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+	stream.now = func() time.Time { return now }
+	if sigs, _ := stream.List("run-1"); strings.Join(sigs, ",") != "build_done" {
+		t.Fatal("unexpected signals", sigs)
+	}
+	if _, exist, _ := stream.Info("default", "deploy_done"); !exist {
+		t.Fatal("signal isn't persisted")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, exist, _ := stream.Info("", "deploy_done"); exist {
+		t.Fatal("signal should expire")
+	}
+	if cnt, err := stream.Sweep(); err != nil || cnt != 1 {
+		t.Fatal("expired signal isn't swept", cnt, err)
+	}
+
+	if err = stream.Clear("run-2"); err != nil {
+		t.Fatal(err)
+	}
+	if _, exist, _ := stream.Info("run-2", "build_done"); exist {
+		t.Fatal("namespace isn't cleared")
+	}
+	stream.Clear("run-1", "build_done")
+	if sigs, _ := stream.List("run-1"); len(sigs) != 0 {
+		t.Fatal("signal isn't cleared", sigs)
+	}
+}
@@ -10,8 +10,10 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	pb "github.com/ansurfen/yock/daemon/proto"
@@ -86,23 +88,23 @@ func (c *ProxyYockdClient) MakeTunnel(name string, ctx context.Context, p yocki.
 	return nil
 }
 
-func (c *ProxyYockdClient) SignalNotify(sig string) error {
+func (c *ProxyYockdClient) SignalNotify(ns, sig string, ttl time.Duration) error {
 	return nil
 }
 
-func (c *ProxyYockdClient) SignalWait(sig string) (bool, error) {
+func (c *ProxyYockdClient) SignalWait(ns, sig string, ttl time.Duration) (bool, error) {
 	return false, nil
 }
 
-func (c *ProxyYockdClient) SignalInfo(sig string) (bool, bool, error) {
+func (c *ProxyYockdClient) SignalInfo(ns, sig string) (bool, bool, error) {
 	return false, false, nil
 }
 
-func (c *ProxyYockdClient) SignalList() ([]string, error) {
+func (c *ProxyYockdClient) SignalList(ns string) ([]string, error) {
 	return nil, nil
 }
 
-func (c *ProxyYockdClient) SignalClear(sigs ...string) error {
+func (c *ProxyYockdClient) SignalClear(ns string, sigs ...string) error {
 	return nil
 }
 
@@ -122,6 +124,18 @@ func (c *ProxyYockdClient) ProcessSpawn(t pb.ProcessSpawnType, spec, cmd string)
 	return 0, nil
 }
 
+func (c *ProxyYockdClient) ProcessAttach(pid int64, output func([]byte), state func(*pb.ProcessStateChange)) error {
+	return util.ErrNoSupportAttach
+}
+
+func (c *ProxyYockdClient) Dispatch(req *pb.DispatchRequest, log func(string)) (*pb.DispatchResult, error) {
+	return nil, util.ErrNoSupportDispatch
+}
+
+func (c *ProxyYockdClient) ClusterMembers() ([]*pb.Member, error) {
+	return nil, nil
+}
+
 func (c *ProxyYockdClient) Status() {}
 
 func (c *ProxyYockdClient) Call(node, method string, args ...string) (string, error) {
@@ -224,7 +238,7 @@ func (c *DeliveryClient) ProcessSpawn(t pb.ProcessSpawnType, spec, cmd string) (
 	return v.(int64), nil
 }
 
-func (c *DeliveryClient) SignalNotify(sig string) error {
+func (c *DeliveryClient) SignalNotify(ns, sig string, ttl time.Duration) error {
 	_, ok := c.invoke("notify", 5*time.Second)
 	if !ok {
 		return fmt.Errorf("context deadline exceeded")
@@ -232,7 +246,7 @@ func (c *DeliveryClient) SignalNotify(sig string) error {
 	return nil
 }
 
-func (c *DeliveryClient) SignalWait(sig string) (bool, error) {
+func (c *DeliveryClient) SignalWait(ns, sig string, ttl time.Duration) (bool, error) {
 	v, ok := c.invoke("wait", 5*time.Second)
 	if !ok {
 		return false, fmt.Errorf("context deadline exceeded")
@@ -240,7 +254,7 @@ func (c *DeliveryClient) SignalWait(sig string) (bool, error) {
 	return v.(bool), nil
 }
 
-func (c *DeliveryClient) SignalInfo(sig string) (bool, bool, error) {
+func (c *DeliveryClient) SignalInfo(ns, sig string) (bool, bool, error) {
 	v, ok := c.invoke("signalinfo", 5*time.Second)
 	if !ok {
 		return false, false, fmt.Errorf("context deadline exceeded")
@@ -250,14 +264,26 @@ func (c *DeliveryClient) SignalInfo(sig string) (bool, bool, error) {
 	return res[0], res[1], nil
 }
 
-func (c *DeliveryClient) SignalList() ([]string, error) {
+func (c *DeliveryClient) SignalList(ns string) ([]string, error) {
 	return nil, nil
 }
 
-func (c *DeliveryClient) SignalClear(sigs ...string) error {
+func (c *DeliveryClient) SignalClear(ns string, sigs ...string) error {
 	return nil
 }
 
+func (c *DeliveryClient) ProcessAttach(pid int64, output func([]byte), state func(*pb.ProcessStateChange)) error {
+	return util.ErrNoSupportAttach
+}
+
+func (c *DeliveryClient) Dispatch(req *pb.DispatchRequest, log func(string)) (*pb.DispatchResult, error) {
+	return nil, util.ErrNoSupportDispatch
+}
+
+func (c *DeliveryClient) ClusterMembers() ([]*pb.Member, error) {
+	return nil, nil
+}
+
 func (c *DeliveryClient) Status() {}
 
 func (c *DeliveryClient) Call(node, method string, args ...string) (string, error) {
@@ -305,6 +331,19 @@ func NewDirect(opt *YockdClientOption) yocki.YockdClient {
 	}
 }
 
+// NewDirectAddr returns the client of daemon dialed at addr, which is in the form of ip:port.
+func NewDirectAddr(addr string) (yocki.YockdClient, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", util.ErrInvalidPort, addr)
+	}
+	return NewDirect(&YockdClientOption{IP: host, Port: p}), nil
+}
+
 func (c *DirectClient) ProcessList() ([]*pb.Process, error) {
 	res, err := c.cli.ProcessList(context.Background(), &pb.ProcessListRequest{})
 	return res.GetRes(), err
@@ -337,6 +376,55 @@ func (c *DirectClient) ProcessSpawn(t pb.ProcessSpawnType, spec, cmd string) (in
 	return res.GetPid(), err
 }
 
+// ProcessAttach tails the process until it's stopped. The stream has no deadline
+// as the process may run for a long time.
+func (c *DirectClient) ProcessAttach(pid int64, output func([]byte), state func(*pb.ProcessStateChange)) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := c.cli.ProcessAttach(ctx, &pb.ProcessAttachRequest{Pid: pid})
+	if err != nil {
+		return err
+	}
+	for {
+		res, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if res.GetState() != nil {
+			state(res.GetState())
+		} else {
+			output(res.GetOutput())
+		}
+	}
+}
+
+// Dispatch runs the job on the node, and blocks until the result is sent back.
+// The timeout of job is taken by the node, so that the stream has no deadline.
+func (c *DirectClient) Dispatch(req *pb.DispatchRequest, log func(string)) (*pb.DispatchResult, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := c.cli.Dispatch(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		res, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				err = fmt.Errorf("%s ends without result", req.GetJob())
+			}
+			return nil, err
+		}
+		if res.GetResult() != nil {
+			return res.GetResult(), nil
+		}
+		log(res.GetLog())
+	}
+}
+
 func (client *DirectClient) Name() string {
 	return client.name
 }
@@ -349,6 +437,11 @@ func (client *DirectClient) Close() {
 	client.conn.Close()
 }
 
+// Addr returns the address of daemon dialed by client, which is in the form of ip:port.
+func (client *DirectClient) Addr() string {
+	return net.JoinHostPort(client.opt.IP, strconv.Itoa(client.opt.Port))
+}
+
 func (c *DirectClient) Mark(name, addr string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -373,10 +466,10 @@ func (c *DirectClient) Ping() error {
 }
 
 // Wait is used to request signal from the daemon
-func (c *DirectClient) SignalWait(sig string) (bool, error) {
+func (c *DirectClient) SignalWait(ns, sig string, ttl time.Duration) (bool, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	res, err := c.cli.SignalWait(ctx, &pb.WaitRequest{Sig: sig})
+	res, err := c.cli.SignalWait(ctx, &pb.WaitRequest{Sig: sig, Namespace: ns, Ttl: int64(ttl)})
 	if err != nil {
 		ycho.Error(err)
 	}
@@ -384,31 +477,31 @@ func (c *DirectClient) SignalWait(sig string) (bool, error) {
 }
 
 // Notify pushes signal to Daemon
-func (c *DirectClient) SignalNotify(sig string) error {
+func (c *DirectClient) SignalNotify(ns, sig string, ttl time.Duration) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	_, err := c.cli.SignalNotify(ctx, &pb.NotifyRequest{Sig: sig})
+	_, err := c.cli.SignalNotify(ctx, &pb.NotifyRequest{Sig: sig, Namespace: ns, Ttl: int64(ttl)})
 	return err
 }
 
-func (c *DirectClient) SignalInfo(sig string) (bool, bool, error) {
+func (c *DirectClient) SignalInfo(ns, sig string) (bool, bool, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	res, err := c.cli.SignalInfo(ctx, &pb.SignalInfoRequest{Sig: sig})
+	res, err := c.cli.SignalInfo(ctx, &pb.SignalInfoRequest{Sig: sig, Namespace: ns})
 	return res.GetStatus(), res.GetExist(), err
 }
 
-func (c *DirectClient) SignalList() ([]string, error) {
+func (c *DirectClient) SignalList(ns string) ([]string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	res, err := c.cli.SignalList(ctx, &pb.SignalListRequest{})
+	res, err := c.cli.SignalList(ctx, &pb.SignalListRequest{Namespace: ns})
 	return res.GetSigs(), err
 }
 
-func (c *DirectClient) SignalClear(sigs ...string) error {
+func (c *DirectClient) SignalClear(ns string, sigs ...string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	_, err := c.cli.SignalClear(ctx, &pb.SignalClearRequest{Sigs: sigs})
+	_, err := c.cli.SignalClear(ctx, &pb.SignalClearRequest{Sigs: sigs, Namespace: ns})
 	return err
 }
 
@@ -481,6 +574,22 @@ func (c *DirectClient) Unregister(addrs ...string) error {
 	return err
 }
 
+// Gossip sends the members known by node to the daemon, and returns the members known by it.
+func (c *DirectClient) Gossip(members []*pb.Member) ([]*pb.Member, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	res, err := c.cli.Gossip(ctx, &pb.GossipRequest{Members: members})
+	return res.GetMembers(), err
+}
+
+// ClusterMembers returns the members of cluster known by the daemon
+func (c *DirectClient) ClusterMembers() ([]*pb.Member, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	res, err := c.cli.ClusterMembers(ctx, &pb.ClusterMembersRequest{})
+	return res.GetMembers(), err
+}
+
 // Info can obtain the meta information of the target node,
 // including CPU, DISK, MEM and so on.
 // You can specify it by InfoRequest, and by default only basic parameters
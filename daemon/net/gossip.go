@@ -0,0 +1,301 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package net
+
+import (
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ansurfen/yock/daemon/conf"
+	pb "github.com/ansurfen/yock/daemon/proto"
+	"github.com/ansurfen/yock/util"
+	"github.com/ansurfen/yock/ycho"
+	"google.golang.org/protobuf/proto"
+)
+
+// Membership is the view of cluster on the node, which is gossiped with peers
+// in rounds. In each round, the node increases its own heartbeat and exchanges
+// the members it knows with a few peers, and the newer of them are merged.
+// The member whose heartbeat doesn't increase is suspected, declared dead,
+// and removed at last, judged by the local clock of node.
+type Membership struct {
+	mu      sync.Mutex
+	self    *pb.Member
+	members map[string]*member
+	seeds   map[string]bool
+	clients map[string]*DirectClient
+	opt     conf.YockdConfNetGossip
+
+	now func() time.Time
+	// exchange sends members to the peer at addr, and returns the members known by it
+	exchange func(addr string, members []*pb.Member) ([]*pb.Member, error)
+}
+
+type member struct {
+	*pb.Member
+	// updated is the local time when the heartbeat of member increased last
+	updated time.Time
+}
+
+// NewMembership returns the membership of node named name, which is dialed at addr by peers
+func NewMembership(name, addr string, opt conf.YockdConfNetGossip) *Membership {
+	if opt.Fanout <= 0 {
+		opt.Fanout = 3
+	}
+	if opt.Interval <= 0 {
+		opt.Interval = time.Second
+	}
+	if opt.SuspectTimeout <= 0 {
+		opt.SuspectTimeout = 5 * time.Second
+	}
+	if opt.DeadTimeout <= 0 {
+		opt.DeadTimeout = 30 * time.Second
+	}
+	if opt.EvictTimeout <= 0 {
+		opt.EvictTimeout = 5 * time.Minute
+	}
+	ms := &Membership{
+		self: &pb.Member{
+			Name:        name,
+			Addr:        addr,
+			Incarnation: time.Now().UnixNano(),
+			Os:          runtime.GOOS,
+			Arch:        runtime.GOARCH,
+			Labels:      opt.Labels,
+		},
+		members: make(map[string]*member),
+		seeds:   make(map[string]bool),
+		clients: make(map[string]*DirectClient),
+		opt:     opt,
+		now:     time.Now,
+	}
+	ms.exchange = ms.gossip
+	ms.Register(opt.Seeds...)
+	return ms
+}
+
+// Self returns the member of node itself
+func (ms *Membership) Self() *pb.Member {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	return proto.Clone(ms.self).(*pb.Member)
+}
+
+// Members returns the members known by node, including itself, ordered by name.
+func (ms *Membership) Members() []*pb.Member {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	return ms.snapshot()
+}
+
+func (ms *Membership) snapshot() []*pb.Member {
+	res := []*pb.Member{proto.Clone(ms.self).(*pb.Member)}
+	for _, m := range ms.members {
+		res = append(res, proto.Clone(m.Member).(*pb.Member))
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].GetName() < res[j].GetName()
+	})
+	return res
+}
+
+// Merge takes the members gossiped by peer. The member is updated when it
+// restarts or its heartbeat increases, and it's alive again after that.
+// The member unknown yet is ignored if the peer has declared it dead,
+// so that the evicted members aren't brought back.
+func (ms *Membership) Merge(members []*pb.Member) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	now := ms.now()
+	for _, m := range members {
+		if m.GetName() == ms.self.GetName() || len(m.GetName()) == 0 {
+			continue
+		}
+		known, ok := ms.members[m.GetName()]
+		if !ok && m.GetState() == pb.MemberState_Dead {
+			continue
+		}
+		if ok && !newerMember(m, known.Member) {
+			continue
+		}
+		m = proto.Clone(m).(*pb.Member)
+		m.State = pb.MemberState_Alive
+		if !ok {
+			ycho.Infof("member join: %s -> %s", m.GetName(), m.GetAddr())
+		} else if known.GetState() != pb.MemberState_Alive {
+			ycho.Infof("member alive: %s", m.GetName())
+		}
+		ms.members[m.GetName()] = &member{Member: m, updated: now}
+	}
+}
+
+// newerMember reports whether a is newer than b, and the restart of member
+// overrides the heartbeat of the last run.
+func newerMember(a, b *pb.Member) bool {
+	if a.GetIncarnation() != b.GetIncarnation() {
+		return a.GetIncarnation() > b.GetIncarnation()
+	}
+	return a.GetHeartbeat() > b.GetHeartbeat()
+}
+
+// Register adds the addresses of seeds, which are gossiped when no peer is available
+func (ms *Membership) Register(addrs ...string) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	for _, addr := range addrs {
+		if addr != ms.self.GetAddr() {
+			ms.seeds[addr] = true
+		}
+	}
+}
+
+// Unregister removes the seeds and the members according to addrs
+func (ms *Membership) Unregister(addrs ...string) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	for _, addr := range addrs {
+		delete(ms.seeds, addr)
+		for name, m := range ms.members {
+			if m.GetAddr() == addr {
+				delete(ms.members, name)
+			}
+		}
+	}
+}
+
+// Seeds returns the addresses of seeds
+func (ms *Membership) Seeds() []string {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	res := []string{}
+	for addr := range ms.seeds {
+		res = append(res, addr)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// detect judges the state of members by how long their heartbeats haven't increased
+func (ms *Membership) detect(now time.Time) {
+	for name, m := range ms.members {
+		elapsed := now.Sub(m.updated)
+		switch {
+		case elapsed > ms.opt.EvictTimeout:
+			ycho.Warnf("member evict: %s", name)
+			delete(ms.members, name)
+			ms.closeClient(m.GetAddr())
+		case elapsed > ms.opt.DeadTimeout:
+			if m.State != pb.MemberState_Dead {
+				ycho.Warnf("member dead: %s", name)
+				m.State = pb.MemberState_Dead
+			}
+		case elapsed > ms.opt.SuspectTimeout:
+			if m.State == pb.MemberState_Alive {
+				ycho.Warnf("member suspect: %s", name)
+				m.State = pb.MemberState_Suspect
+			}
+		}
+	}
+}
+
+// targets picks the peers gossiped in this round, and they're the seeds
+// when none of members is alive or suspected.
+func (ms *Membership) targets() []string {
+	addrs := []string{}
+	for _, m := range ms.members {
+		if m.State != pb.MemberState_Dead && len(m.GetAddr()) > 0 {
+			addrs = append(addrs, m.GetAddr())
+		}
+	}
+	if len(addrs) == 0 {
+		for addr := range ms.seeds {
+			addrs = append(addrs, addr)
+		}
+	}
+	rand.Shuffle(len(addrs), func(i, j int) {
+		addrs[i], addrs[j] = addrs[j], addrs[i]
+	})
+	if len(addrs) > ms.opt.Fanout {
+		addrs = addrs[:ms.opt.Fanout]
+	}
+	return addrs
+}
+
+// Round increases the heartbeat of node, updates the state of members
+// and gossips with peers once.
+func (ms *Membership) Round() {
+	cpu, mem := load()
+	ms.mu.Lock()
+	ms.self.Heartbeat++
+	ms.self.Cpu, ms.self.Mem = cpu, mem
+	ms.detect(ms.now())
+	addrs := ms.targets()
+	members := ms.snapshot()
+	ms.mu.Unlock()
+	wg := sync.WaitGroup{}
+	for _, addr := range addrs {
+		wg.Add(1)
+		go func(addr string) {
+			defer wg.Done()
+			res, err := ms.exchange(addr, members)
+			if err != nil {
+				// the peer is suspected when it can't be reached in rounds
+				ycho.Debugf("fail to gossip with %s, %s", addr, err)
+				return
+			}
+			ms.Merge(res)
+		}(addr)
+	}
+	wg.Wait()
+}
+
+// Run gossips in rounds, and it never returns.
+func (ms *Membership) Run() {
+	ticker := time.NewTicker(ms.opt.Interval)
+	defer ticker.Stop()
+	for {
+		ms.Round()
+		<-ticker.C
+	}
+}
+
+func (ms *Membership) gossip(addr string, members []*pb.Member) ([]*pb.Member, error) {
+	ms.mu.Lock()
+	cli, ok := ms.clients[addr]
+	if !ok {
+		c, err := NewDirectAddr(addr)
+		if err != nil {
+			ms.mu.Unlock()
+			return nil, err
+		}
+		cli = c.(*DirectClient)
+		ms.clients[addr] = cli
+	}
+	ms.mu.Unlock()
+	return cli.Gossip(members)
+}
+
+// closeClient closes the client dialed at addr, and it's dialed again when the addr is gossiped.
+func (ms *Membership) closeClient(addr string) {
+	if cli, ok := ms.clients[addr]; ok {
+		cli.Close()
+		delete(ms.clients, addr)
+	}
+}
+
+// load returns the usage percent of cpu and memory
+func load() (float64, float64) {
+	var cpu, mem float64
+	if percent, err := util.CPU().Percent(0, false); err == nil && len(percent) > 0 {
+		cpu = percent[0]
+	}
+	if stat, err := util.Mem().VirtualMemory(); err == nil {
+		mem = stat.UsedPercent
+	}
+	return cpu, mem
+}
@@ -0,0 +1,101 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package net
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ansurfen/yock/daemon/conf"
+	pb "github.com/ansurfen/yock/daemon/proto"
+)
+
+func memberStates(ms *Membership) map[string]pb.MemberState {
+	res := map[string]pb.MemberState{}
+	for _, m := range ms.Members() {
+		res[m.GetName()] = m.GetState()
+	}
+	return res
+}
+
+func TestMembership(t *testing.T) {
+	now := time.Now()
+	cluster := map[string]*Membership{}
+	join := func(name string, seeds ...string) *Membership {
+		ms := NewMembership(name, name+":9090", conf.YockdConfNetGossip{
+			Seeds:  seeds,
+			Labels: []string{"docker"},
+		})
+		ms.now = func() time.Time { return now }
+		ms.exchange = func(addr string, members []*pb.Member) ([]*pb.Member, error) {
+			peer, ok := cluster[addr]
+			if !ok {
+				return nil, fmt.Errorf("%s unreachable", addr)
+			}
+			peer.Merge(members)
+			return peer.Members(), nil
+		}
+		cluster[name+":9090"] = ms
+		return ms
+	}
+	a, b := join("a", "b:9090"), join("b")
+	c := join("c", "b:9090", "d:9090")
+	for i := 0; i < 2; i++ {
+		a.Round()
+		c.Round()
+		b.Round()
+	}
+	for _, ms := range []*Membership{a, b, c} {
+		states := memberStates(ms)
+		if len(states) != 3 || states["a"] != pb.MemberState_Alive || states["c"] != pb.MemberState_Alive {
+			t.Fatal("members don't converge", ms.Self().GetName(), states)
+		}
+	}
+	if m := a.Members()[2]; m.GetName() != "c" || m.GetLabels()[0] != "docker" || m.GetHeartbeat() == 0 {
+		t.Fatal("unexpected member", m)
+	}
+
+	// c stops, and a and b keep gossiping
+	delete(cluster, "c:9090")
+	step := func(d time.Duration) {
+		now = now.Add(d)
+		a.Round()
+		b.Round()
+	}
+	step(6 * time.Second)
+	if states := memberStates(a); states["c"] != pb.MemberState_Suspect || states["b"] != pb.MemberState_Alive {
+		t.Fatal("c should be suspected", states)
+	}
+	step(25 * time.Second)
+	if states := memberStates(a); states["c"] != pb.MemberState_Dead {
+		t.Fatal("c should be dead", states)
+	}
+	cli, err := NewDirectAddr("c:9090")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.clients["c:9090"] = cli.(*DirectClient)
+	step(5 * time.Minute)
+	if states := memberStates(a); len(states) != 2 {
+		t.Fatal("c should be evicted", states)
+	}
+	if _, ok := a.clients["c:9090"]; ok {
+		t.Fatal("client of evicted member isn't closed")
+	}
+	// the dead member gossiped by peer isn't brought back
+	a.Merge([]*pb.Member{{Name: "c", Incarnation: 1, Heartbeat: 100, State: pb.MemberState_Dead}})
+	if states := memberStates(a); len(states) != 2 {
+		t.Fatal("dead member is brought back", states)
+	}
+
+	// c restarts with a new incarnation
+	c = join("c", "b:9090")
+	c.Round()
+	a.Round()
+	if states := memberStates(a); states["c"] != pb.MemberState_Alive {
+		t.Fatal("c should rejoin", states)
+	}
+}
@@ -4,21 +4,45 @@
 
 package net
 
-import yocki "github.com/ansurfen/yock/interface"
+import (
+	"fmt"
+
+	"github.com/ansurfen/yock/daemon/conf"
+	du "github.com/ansurfen/yock/daemon/util"
+	yocki "github.com/ansurfen/yock/interface"
+)
 
 type Node yocki.YockdClient
 
 type NetworkManager struct {
-	nodes map[string]Node
+	nodes   map[string]Node
+	cluster *Membership
 }
 
-func NewNetworkManager() *NetworkManager {
+func NewNetworkManager(opt *conf.YockdConf) *NetworkManager {
+	name := opt.Name
+	if len(name) == 0 {
+		name = du.ID
+	}
+	addr := opt.Net.Gossip.Advertise
+	if len(addr) == 0 && len(opt.Grpc.Addr.IP) > 0 {
+		addr = fmt.Sprintf("%s:%d", opt.Grpc.Addr.IP, opt.Grpc.Addr.Port)
+	}
 	return &NetworkManager{
-		nodes: make(map[string]Node),
+		nodes:   make(map[string]Node),
+		cluster: NewMembership(name, addr, opt.Net.Gossip),
 	}
 }
 
-func (m *NetworkManager) MakeBridge() {}
+// MakeBridge gossips with the members of cluster, and it never returns.
+func (m *NetworkManager) MakeBridge() {
+	m.cluster.Run()
+}
+
+// Cluster returns the membership of cluster where node is
+func (m *NetworkManager) Cluster() *Membership {
+	return m.cluster
+}
 
 func (m *NetworkManager) Node(name string) Node {
 	if n := m.nodes[name]; n != nil {
@@ -5,13 +5,17 @@
 package process
 
 import (
+	"errors"
+	"os/exec"
+	"sync"
+
 	yockc "github.com/ansurfen/yock/cmd"
 	"github.com/ansurfen/yock/ycho"
 	"github.com/bwmarrin/snowflake"
 )
 
 const (
-	P_NEW = iota
+	P_NEW pstate = iota
 	P_READY
 	P_SUSPEND
 	P_RUNNING
@@ -23,22 +27,58 @@ type pstate uint8
 
 func (s pstate) String() string {
 	switch s {
+	case P_NEW:
+		return "new"
+	case P_READY:
+		return "ready"
+	case P_SUSPEND:
+		return "suspend"
+	case P_RUNNING:
+		return "running"
+	case P_WAIT:
+		return "wait"
+	case P_STOPPED:
+		return "stopped"
 	default:
-		return "unknwon state"
+		return "unknown state"
 	}
 }
 
+const (
+	// outputSize is the size of recent output kept for each process
+	outputSize = 64 * 1024
+	// eventBuffer is the number of events buffered for each subscriber,
+	// and the subscriber falling behind it is detached.
+	eventBuffer = 256
+)
+
+// Event is published to subscribers of process when it writes output
+// or its state changes. Only one of Output and State is meaningful,
+// and State is P_NEW for the output event.
+type Event struct {
+	Output   []byte
+	State    pstate
+	ExitCode int
+}
+
 type Process struct {
 	pid   int64
 	state pstate
 	spec  string
 	cmd   string
 	run   func(*Process)
+
+	mu       sync.Mutex
+	output   *ring
+	exitCode int
+	subs     map[chan Event]struct{}
 }
 
 func New() *Process {
 	return &Process{
-		state: P_NEW,
+		state:  P_NEW,
+		output: newRing(outputSize),
+		subs:   make(map[chan Event]struct{}),
 	}
 }
 
@@ -55,9 +95,86 @@ func (p *Process) Spec() string {
 }
 
 func (p *Process) State() pstate {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	return p.state
 }
 
+// ExitCode returns the exit code of the last run of process
+func (p *Process) ExitCode() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.exitCode
+}
+
+// Write keeps the output of process and publishes it to subscribers
+func (p *Process) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.output.Write(b)
+	p.publish(Event{Output: append([]byte{}, b...)})
+	return len(b), nil
+}
+
+// setState changes the state of process and publishes it to subscribers,
+// who are detached when process is stopped.
+func (p *Process) setState(state pstate) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.state == state {
+		return
+	}
+	p.state = state
+	p.publish(Event{State: state, ExitCode: p.exitCode})
+	if state == P_STOPPED {
+		for ch := range p.subs {
+			close(ch)
+			delete(p.subs, ch)
+		}
+	}
+}
+
+func (p *Process) setExitCode(code int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.exitCode = code
+}
+
+// publish must be called with the lock held
+func (p *Process) publish(e Event) {
+	for ch := range p.subs {
+		select {
+		case ch <- e:
+		default:
+			close(ch)
+			delete(p.subs, ch)
+		}
+	}
+}
+
+// Attach returns the recent output of process and subscribes to its events,
+// the first of which is the current state. The events are closed when process
+// is stopped, the subscriber falls behind, or detach is called.
+func (p *Process) Attach() (recent []byte, events <-chan Event, detach func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ch := make(chan Event, eventBuffer)
+	ch <- Event{State: p.state, ExitCode: p.exitCode}
+	if p.state == P_STOPPED {
+		close(ch)
+	} else {
+		p.subs[ch] = struct{}{}
+	}
+	return p.output.Bytes(), ch, func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if _, ok := p.subs[ch]; ok {
+			close(ch)
+			delete(p.subs, ch)
+		}
+	}
+}
+
 func (p *Process) Run() {
 	p.run(p)
 }
@@ -112,20 +229,24 @@ func (m *ProcessManager) NextPID() int64 {
 }
 
 func (m *ProcessManager) CreateProcess(spec, cmd string) *Process {
-	p := &Process{
-		spec:  spec,
-		cmd:   cmd,
-		pid:   m.NextPID(),
-		state: P_NEW,
-		run: func(p *Process) {
-			// ycho.Info()
-			res, err := yockc.Exec(yockc.ExecOpt{}, cmd)
-			if err != nil {
-				p.state = P_SUSPEND
-				ycho.Errorf("[%d] process abort, err: %s", p.pid, res)
-				return
-			}
-		},
+	p := New()
+	p.spec, p.cmd, p.pid = spec, cmd, m.NextPID()
+	p.run = func(p *Process) {
+		res, err := yockc.Exec(yockc.ExecOpt{Output: p}, cmd)
+		var exitErr *exec.ExitError
+		switch {
+		case err == nil:
+			p.setExitCode(0)
+		case errors.As(err, &exitErr):
+			p.setExitCode(exitErr.ExitCode())
+		default:
+			p.setExitCode(-1)
+		}
+		if err != nil {
+			p.setState(P_SUSPEND)
+			ycho.Errorf("[%d] process abort, err: %s", p.pid, res)
+			return
+		}
 	}
 	if _, ok := m.process[p.pid]; ok {
 		panic("internal system error")
@@ -144,6 +265,6 @@ func (m *ProcessManager) mapping(pid int64, cid TID) {
 func (m *ProcessManager) kill(pid int64) {
 	m.remove <- pid
 	if proc, ok := m.process[pid]; ok {
-		proc.state = P_STOPPED
+		proc.setState(P_STOPPED)
 	}
 }
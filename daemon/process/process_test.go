@@ -0,0 +1,75 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package process
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRing(t *testing.T) {
+	r := newRing(8)
+	r.Write([]byte("hello"))
+	if got := string(r.Bytes()); got != "hello" {
+		t.Fatal("unexpected output", got)
+	}
+	r.Write([]byte(" world"))
+	if got := string(r.Bytes()); got != "lo world" {
+		t.Fatal("oldest output should be dropped", got)
+	}
+	r.Write([]byte("0123456789"))
+	if got := string(r.Bytes()); got != "23456789" {
+		t.Fatal("unexpected output", got)
+	}
+}
+
+func TestProcessAttach(t *testing.T) {
+	if P_RUNNING.String() != "running" || P_STOPPED.String() != "stopped" {
+		t.Fatal("unexpected name of state")
+	}
+	s := NewScheduler()
+	pid, err := s.CreateScriptTask("echo hello && exit 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := s.FindByPID(pid)
+	recent, events, detach := p.Attach()
+	defer detach()
+
+	out, states := string(recent), []string{}
+	timeout := time.After(10 * time.Second)
+	for done := false; !done; {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				done = true
+				break
+			}
+			if len(e.Output) > 0 {
+				out += string(e.Output)
+			} else {
+				states = append(states, e.State.String())
+			}
+		case <-timeout:
+			t.Fatal("process isn't stopped")
+		}
+	}
+	if !strings.Contains(out, "hello") {
+		t.Fatal("output is lost", out)
+	}
+	if states[len(states)-1] != "stopped" || p.ExitCode() != 3 {
+		t.Fatal("unexpected exit", states, p.ExitCode())
+	}
+
+	// the process stopped is replayed with its recent output and final state
+	recent, events, _ = p.Attach()
+	if e := <-events; !strings.Contains(string(recent), "hello") || e.State != P_STOPPED || e.ExitCode != 3 {
+		t.Fatal("unexpected replay", string(recent), e)
+	}
+	if _, ok := <-events; ok {
+		t.Fatal("events should be closed")
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package process
+
+// ring is a bounded buffer keeping the last bytes written,
+// and the oldest are dropped when it's full.
+type ring struct {
+	buf  []byte
+	head int
+	full bool
+}
+
+func newRing(size int) *ring {
+	return &ring{buf: make([]byte, size)}
+}
+
+func (r *ring) Write(p []byte) (int, error) {
+	n := len(p)
+	if n >= len(r.buf) {
+		copy(r.buf, p[n-len(r.buf):])
+		r.head, r.full = 0, true
+		return n, nil
+	}
+	m := copy(r.buf[r.head:], p)
+	if m < n {
+		copy(r.buf, p[m:])
+		r.full = true
+	}
+	if r.head+n >= len(r.buf) {
+		r.full = true
+	}
+	r.head = (r.head + n) % len(r.buf)
+	return n, nil
+}
+
+// Bytes returns a copy of the bytes kept in order
+func (r *ring) Bytes() []byte {
+	if !r.full {
+		return append([]byte{}, r.buf[:r.head]...)
+	}
+	return append(append([]byte{}, r.buf[r.head:]...), r.buf[:r.head]...)
+}
@@ -84,12 +84,12 @@ func (s *Scheduler) CreateCronTask(cron, cmd string) (pid int64, err error) {
 	}()
 	p := s.prom.CreateProcess(cron, cmd)
 	id, err := s.timingwheel.AddFunc(cron, func() {
-		if p.state == P_STOPPED {
+		if p.State() == P_STOPPED {
 			return
 		}
-		p.state = P_RUNNING
+		p.setState(P_RUNNING)
 		p.run(p)
-		p.state = P_READY
+		p.setState(P_READY)
 	})
 	if err != nil {
 		panic(err)
@@ -114,10 +114,10 @@ func (s *Scheduler) CreateImmediateCronTask(cron, cmd string) (pid int64, err er
 	}()
 	p := s.prom.CreateProcess(cron, cmd)
 	id, err := s.timingwheel.AddFunc(cron, func() {
-		if p.state == P_STOPPED {
+		if p.State() == P_STOPPED {
 			return
 		}
-		p.state = P_RUNNING
+		p.setState(P_RUNNING)
 		p.run(p)
 		s.prom.kill(p.pid)
 	})
@@ -129,17 +129,29 @@ func (s *Scheduler) CreateImmediateCronTask(cron, cmd string) (pid int64, err er
 	return
 }
 
+// CreateScriptTask runs cmd once in background, and the process is stopped
+// when it exits.
+func (s *Scheduler) CreateScriptTask(cmd string) (int64, error) {
+	p := s.prom.CreateProcess("", cmd)
+	go func() {
+		p.setState(P_RUNNING)
+		p.run(p)
+		p.setState(P_STOPPED)
+	}()
+	return p.pid, nil
+}
+
 func (s *Scheduler) CreateTimingImmediateCronTask(cron, cmd string) {}
 
 func (s *Scheduler) CreateFSListenTask(paths []string, cmd string) (int64, error) {
 	p := s.prom.CreateProcess(strings.Join(paths, ";"), cmd)
 	id := s.oschan.AddFunc(paths, func() {
-		if p.state == P_STOPPED {
+		if p.State() == P_STOPPED {
 			return
 		}
-		p.state = P_RUNNING
+		p.setState(P_RUNNING)
 		p.run(p)
-		p.state = P_WAIT
+		p.setState(P_WAIT)
 	})
 	s.prom.mapping(p.pid, OSTID(id))
 	return p.pid, nil
@@ -50,6 +50,16 @@ type YockDaemonClient interface {
 	ProcessFind(ctx context.Context, in *ProcessFindRequest, opts ...grpc.CallOption) (*ProcessFindResponse, error)
 	ProcessList(ctx context.Context, in *ProcessListRequest, opts ...grpc.CallOption) (*ProcessListResponse, error)
 	ProcessKill(ctx context.Context, in *ProcessKillRequest, opts ...grpc.CallOption) (*ProcessKillResponse, error)
+	// Dispatch runs the job on the node under a scheduler,
+	// and streams the output of job and its result back.
+	Dispatch(ctx context.Context, in *DispatchRequest, opts ...grpc.CallOption) (YockDaemon_DispatchClient, error)
+	// Gossip exchanges the members known by nodes, which is also the heartbeat of them.
+	Gossip(ctx context.Context, in *GossipRequest, opts ...grpc.CallOption) (*GossipResponse, error)
+	// ClusterMembers returns the members of cluster known by the node.
+	ClusterMembers(ctx context.Context, in *ClusterMembersRequest, opts ...grpc.CallOption) (*ClusterMembersResponse, error)
+	// ProcessAttach streams the recent output of process kept by daemon and then
+	// tails it, along with the changes of state, until the process is stopped.
+	ProcessAttach(ctx context.Context, in *ProcessAttachRequest, opts ...grpc.CallOption) (YockDaemon_ProcessAttachClient, error)
 }
 
 type yockDaemonClient struct {
@@ -293,6 +303,88 @@ func (c *yockDaemonClient) ProcessKill(ctx context.Context, in *ProcessKillReque
 	return out, nil
 }
 
+func (c *yockDaemonClient) Dispatch(ctx context.Context, in *DispatchRequest, opts ...grpc.CallOption) (YockDaemon_DispatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_YockDaemon_serviceDesc.Streams[2], "/Yockd.YockDaemon/Dispatch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &yockDaemonDispatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type YockDaemon_DispatchClient interface {
+	Recv() (*DispatchResponse, error)
+	grpc.ClientStream
+}
+
+type yockDaemonDispatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *yockDaemonDispatchClient) Recv() (*DispatchResponse, error) {
+	m := new(DispatchResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *yockDaemonClient) Gossip(ctx context.Context, in *GossipRequest, opts ...grpc.CallOption) (*GossipResponse, error) {
+	out := new(GossipResponse)
+	err := c.cc.Invoke(ctx, "/Yockd.YockDaemon/Gossip", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *yockDaemonClient) ClusterMembers(ctx context.Context, in *ClusterMembersRequest, opts ...grpc.CallOption) (*ClusterMembersResponse, error) {
+	out := new(ClusterMembersResponse)
+	err := c.cc.Invoke(ctx, "/Yockd.YockDaemon/ClusterMembers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *yockDaemonClient) ProcessAttach(ctx context.Context, in *ProcessAttachRequest, opts ...grpc.CallOption) (YockDaemon_ProcessAttachClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_YockDaemon_serviceDesc.Streams[3], "/Yockd.YockDaemon/ProcessAttach", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &yockDaemonProcessAttachClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type YockDaemon_ProcessAttachClient interface {
+	Recv() (*ProcessAttachResponse, error)
+	grpc.ClientStream
+}
+
+type yockDaemonProcessAttachClient struct {
+	grpc.ClientStream
+}
+
+func (x *yockDaemonProcessAttachClient) Recv() (*ProcessAttachResponse, error) {
+	m := new(ProcessAttachResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // YockDaemonServer is the server API for YockDaemon service.
 // All implementations must embed UnimplementedYockDaemonServer
 // for forward compatibility
@@ -330,6 +422,16 @@ type YockDaemonServer interface {
 	ProcessFind(context.Context, *ProcessFindRequest) (*ProcessFindResponse, error)
 	ProcessList(context.Context, *ProcessListRequest) (*ProcessListResponse, error)
 	ProcessKill(context.Context, *ProcessKillRequest) (*ProcessKillResponse, error)
+	// Dispatch runs the job on the node under a scheduler,
+	// and streams the output of job and its result back.
+	Dispatch(*DispatchRequest, YockDaemon_DispatchServer) error
+	// Gossip exchanges the members known by nodes, which is also the heartbeat of them.
+	Gossip(context.Context, *GossipRequest) (*GossipResponse, error)
+	// ClusterMembers returns the members of cluster known by the node.
+	ClusterMembers(context.Context, *ClusterMembersRequest) (*ClusterMembersResponse, error)
+	// ProcessAttach streams the recent output of process kept by daemon and then
+	// tails it, along with the changes of state, until the process is stopped.
+	ProcessAttach(*ProcessAttachRequest, YockDaemon_ProcessAttachServer) error
 	mustEmbedUnimplementedYockDaemonServer()
 }
 
@@ -400,6 +502,18 @@ func (*UnimplementedYockDaemonServer) ProcessList(context.Context, *ProcessListR
 func (*UnimplementedYockDaemonServer) ProcessKill(context.Context, *ProcessKillRequest) (*ProcessKillResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ProcessKill not implemented")
 }
+func (*UnimplementedYockDaemonServer) Dispatch(*DispatchRequest, YockDaemon_DispatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Dispatch not implemented")
+}
+func (*UnimplementedYockDaemonServer) Gossip(context.Context, *GossipRequest) (*GossipResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Gossip not implemented")
+}
+func (*UnimplementedYockDaemonServer) ClusterMembers(context.Context, *ClusterMembersRequest) (*ClusterMembersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClusterMembers not implemented")
+}
+func (*UnimplementedYockDaemonServer) ProcessAttach(*ProcessAttachRequest, YockDaemon_ProcessAttachServer) error {
+	return status.Errorf(codes.Unimplemented, "method ProcessAttach not implemented")
+}
 func (*UnimplementedYockDaemonServer) mustEmbedUnimplementedYockDaemonServer() {}
 
 func RegisterYockDaemonServer(s *grpc.Server, srv YockDaemonServer) {
@@ -800,6 +914,84 @@ func _YockDaemon_ProcessKill_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _YockDaemon_Dispatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DispatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(YockDaemonServer).Dispatch(m, &yockDaemonDispatchServer{stream})
+}
+
+type YockDaemon_DispatchServer interface {
+	Send(*DispatchResponse) error
+	grpc.ServerStream
+}
+
+type yockDaemonDispatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *yockDaemonDispatchServer) Send(m *DispatchResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _YockDaemon_Gossip_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GossipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(YockDaemonServer).Gossip(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Yockd.YockDaemon/Gossip",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(YockDaemonServer).Gossip(ctx, req.(*GossipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _YockDaemon_ClusterMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClusterMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(YockDaemonServer).ClusterMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Yockd.YockDaemon/ClusterMembers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(YockDaemonServer).ClusterMembers(ctx, req.(*ClusterMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _YockDaemon_ProcessAttach_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ProcessAttachRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(YockDaemonServer).ProcessAttach(m, &yockDaemonProcessAttachServer{stream})
+}
+
+type YockDaemon_ProcessAttachServer interface {
+	Send(*ProcessAttachResponse) error
+	grpc.ServerStream
+}
+
+type yockDaemonProcessAttachServer struct {
+	grpc.ServerStream
+}
+
+func (x *yockDaemonProcessAttachServer) Send(m *ProcessAttachResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _YockDaemon_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "Yockd.YockDaemon",
 	HandlerType: (*YockDaemonServer)(nil),
@@ -880,6 +1072,14 @@ var _YockDaemon_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ProcessKill",
 			Handler:    _YockDaemon_ProcessKill_Handler,
 		},
+		{
+			MethodName: "Gossip",
+			Handler:    _YockDaemon_Gossip_Handler,
+		},
+		{
+			MethodName: "ClusterMembers",
+			Handler:    _YockDaemon_ClusterMembers_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -894,6 +1094,16 @@ var _YockDaemon_serviceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "Dispatch",
+			Handler:       _YockDaemon_Dispatch_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ProcessAttach",
+			Handler:       _YockDaemon_ProcessAttach_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "yockd.proto",
 }
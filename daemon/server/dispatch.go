@@ -0,0 +1,45 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	pb "github.com/ansurfen/yock/daemon/proto"
+	yocks "github.com/ansurfen/yock/scheduler"
+	"github.com/ansurfen/yock/util"
+	"github.com/ansurfen/yock/ycho"
+)
+
+// dispatchDrainTimeout is how long the goroutines spawned by dispatched job are waited
+const dispatchDrainTimeout = 5 * time.Second
+
+// Dispatch runs the job on the node under a scheduler,
+// and streams the output of job and its result back.
+func (yockd *YockDaemon) Dispatch(req *pb.DispatchRequest, stream pb.YockDaemon_DispatchServer) error {
+	libPath := util.Pathf("~/lib/yock")
+	// scheduler exits when fails to load libraries, which mustn't stop daemon
+	if _, err := os.Stat(libPath); err != nil {
+		return err
+	}
+	ycho.Infof("run %s:%s dispatched", req.GetTask(), req.GetJob())
+	ys := yocks.Default(yocks.OptionLibPath(libPath))
+	go ys.EventLoop()
+	defer ys.Shutdown(dispatchDrainTimeout)
+	// the goroutines of job print at the same time
+	mu := sync.Mutex{}
+	res := ys.RunDispatch(req, func(line string) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err := stream.Send(&pb.DispatchResponse{Log: line}); err != nil {
+			ycho.Warn(err)
+		}
+	})
+	mu.Lock()
+	defer mu.Unlock()
+	return stream.Send(&pb.DispatchResponse{Result: res})
+}
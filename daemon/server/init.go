@@ -33,10 +33,11 @@ type YockDaemon struct {
 }
 
 func New() *YockDaemon {
+	cfg := yocke.GetEnv[*conf.YockdConf]().Conf()
 	yockd := &YockDaemon{
 		gate:        gateway.New(),
-		conf:        yocke.GetEnv[*conf.YockdConf]().Conf(),
-		YockKernel:  kernel.NewKernel(),
+		conf:        cfg,
+		YockKernel:  kernel.NewKernel(cfg),
 		gouroutines: make(chan func(context.Context)),
 	}
 	for name, opt := range yockd.conf.Gateway.Agent {
@@ -156,7 +156,7 @@ func (yockd *YockDaemon) Call(ctx context.Context, req *proto.CallRequest) (*pro
 		ret, g_err = peer.Info()
 	case "signalwait":
 		if len(args) > 0 {
-			ok, err := peer.SignalWait(args[0])
+			ok, err := peer.SignalWait("", args[0], 0)
 			ret = strconv.FormatBool(ok)
 			g_err = err
 		} else {
@@ -164,7 +164,7 @@ func (yockd *YockDaemon) Call(ctx context.Context, req *proto.CallRequest) (*pro
 		}
 	case "signalnotify":
 		if len(args) > 0 {
-			g_err = peer.SignalNotify(args[0])
+			g_err = peer.SignalNotify("", args[0], 0)
 		}
 	case "signallist":
 		// res, err := peer.SignalList()
@@ -177,3 +177,27 @@ func (yockd *YockDaemon) Call(ctx context.Context, req *proto.CallRequest) (*pro
 		Ret: ret,
 	}, g_err
 }
+
+// Register adds the addresses of peers to the seeds of gossip,
+// and returns all the seeds known by daemon.
+func (yockd *YockDaemon) Register(ctx context.Context, req *proto.RegisterRequest) (*proto.RegisterResponse, error) {
+	yockd.Cluster().Register(req.GetAddrs()...)
+	return &proto.RegisterResponse{Addrs: yockd.Cluster().Seeds()}, nil
+}
+
+// Unregister removes the peers from the seeds and members according to addrs
+func (yockd *YockDaemon) Unregister(ctx context.Context, req *proto.UnregisterRequest) (*proto.UnregisterResponse, error) {
+	yockd.Cluster().Unregister(req.GetAddrs()...)
+	return &proto.UnregisterResponse{}, nil
+}
+
+// Gossip merges the members known by peer, and returns the members known by daemon.
+func (yockd *YockDaemon) Gossip(ctx context.Context, req *proto.GossipRequest) (*proto.GossipResponse, error) {
+	yockd.Cluster().Merge(req.GetMembers())
+	return &proto.GossipResponse{Members: yockd.Cluster().Members()}, nil
+}
+
+// ClusterMembers returns the members of cluster known by daemon, including itself.
+func (yockd *YockDaemon) ClusterMembers(ctx context.Context, req *proto.ClusterMembersRequest) (*proto.ClusterMembersResponse, error) {
+	return &proto.ClusterMembersResponse{Members: yockd.Cluster().Members()}, nil
+}
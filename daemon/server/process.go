@@ -6,8 +6,10 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
+	"github.com/ansurfen/yock/daemon/process"
 	pb "github.com/ansurfen/yock/daemon/proto"
 )
 
@@ -69,7 +71,7 @@ func (daemon *YockDaemon) ProcessSpawn(ctx context.Context, req *pb.ProcessSpawn
 	case pb.ProcessSpawnType_FS:
 		pid, err = daemon.Scheduler.CreateFSListenTask(strings.Split(req.GetSpec(), ";"), req.GetCmd())
 	case pb.ProcessSpawnType_Script:
-
+		pid, err = daemon.Scheduler.CreateScriptTask(req.GetCmd())
 	}
 	if err != nil {
 		return &pb.ProcessSpawnResponse{
@@ -80,3 +82,43 @@ func (daemon *YockDaemon) ProcessSpawn(ctx context.Context, req *pb.ProcessSpawn
 		Pid: pid,
 	}, nil
 }
+
+// ProcessAttach sends the recent output of process at first, and then tails
+// the output and changes of state until the process is stopped.
+func (daemon *YockDaemon) ProcessAttach(req *pb.ProcessAttachRequest, stream pb.YockDaemon_ProcessAttachServer) error {
+	p := daemon.Scheduler.FindByPID(req.GetPid())
+	if p == nil {
+		return fmt.Errorf("process %d not found", req.GetPid())
+	}
+	recent, events, detach := p.Attach()
+	defer detach()
+	if len(recent) > 0 {
+		if err := stream.Send(&pb.ProcessAttachResponse{Output: recent}); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case e, ok := <-events:
+			if !ok {
+				if p.State() != process.P_STOPPED {
+					return fmt.Errorf("process %d is detached for falling behind", p.Pid())
+				}
+				return nil
+			}
+			res := &pb.ProcessAttachResponse{Output: e.Output}
+			if len(e.Output) == 0 {
+				res = &pb.ProcessAttachResponse{State: &pb.ProcessStateChange{
+					State:    int32(e.State),
+					Name:     e.State.String(),
+					ExitCode: int32(e.ExitCode),
+				}}
+			}
+			if err := stream.Send(res); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -6,6 +6,7 @@ package api
 
 import (
 	"context"
+	"time"
 
 	pb "github.com/ansurfen/yock/daemon/proto"
 	"github.com/ansurfen/yock/ycho"
@@ -13,32 +14,46 @@ import (
 
 // Wait is used to request signal from the daemon
 func (yockd *YockDaemon) SignalWait(ctx context.Context, req *pb.WaitRequest) (*pb.WaitResponse, error) {
-	ok := yockd.SignalStream.Wait(req.GetSig())
-	ycho.Infof("waiting for %s, status: %v", req.GetSig(), ok)
+	ok, err := yockd.SignalStream.Wait(req.GetNamespace(), req.GetSig(), time.Duration(req.GetTtl()))
+	if err != nil {
+		return nil, err
+	}
+	ycho.Infof("waiting for %s/%s, status: %v", req.GetNamespace(), req.GetSig(), ok)
 	return &pb.WaitResponse{Ok: ok}, nil
 }
 
 // Notify pushes signal to Daemon
 func (yockd *YockDaemon) SignalNotify(ctx context.Context, req *pb.NotifyRequest) (*pb.NotifyResponse, error) {
-	yockd.SignalStream.Notify(req.GetSig())
-	ycho.Infof("notify %s signal", req.GetSig())
+	if err := yockd.SignalStream.Notify(req.GetNamespace(), req.GetSig(), time.Duration(req.GetTtl())); err != nil {
+		return nil, err
+	}
+	ycho.Infof("notify %s/%s signal", req.GetNamespace(), req.GetSig())
 	return &pb.NotifyResponse{}, nil
 }
 
-func (yockd *YockDaemon) SignalList(context.Context, *pb.SignalListRequest) (*pb.SignalListResponse, error) {
+func (yockd *YockDaemon) SignalList(ctx context.Context, req *pb.SignalListRequest) (*pb.SignalListResponse, error) {
+	sigs, err := yockd.SignalStream.List(req.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
 	return &pb.SignalListResponse{
-		Sigs: yockd.SignalStream.List(),
+		Sigs: sigs,
 	}, nil
 }
 
-func (yockd *YockDaemon) SingalClear(ctx context.Context, req *pb.SignalClearRequest) (*pb.SignalClearResponse, error) {
-	ycho.Infof("clear signals: %v", req.GetSigs())
-	yockd.SignalStream.Clear(req.GetSigs()...)
+func (yockd *YockDaemon) SignalClear(ctx context.Context, req *pb.SignalClearRequest) (*pb.SignalClearResponse, error) {
+	ycho.Infof("clear signals of %s: %v", req.GetNamespace(), req.GetSigs())
+	if err := yockd.SignalStream.Clear(req.GetNamespace(), req.GetSigs()...); err != nil {
+		return nil, err
+	}
 	return &pb.SignalClearResponse{}, nil
 }
 
-func (yockd *YockDaemon) SingalInfo(ctx context.Context, req *pb.SignalInfoRequest) (*pb.SignalInfoResponse, error) {
-	status, exist := yockd.SignalStream.Info(req.GetSig())
+func (yockd *YockDaemon) SignalInfo(ctx context.Context, req *pb.SignalInfoRequest) (*pb.SignalInfoResponse, error) {
+	status, exist, err := yockd.SignalStream.Info(req.GetNamespace(), req.GetSig())
+	if err != nil {
+		return nil, err
+	}
 	return &pb.SignalInfoResponse{
 		Status: status,
 		Exist:  exist,
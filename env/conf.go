@@ -134,6 +134,12 @@ func GetEnv[T any]() *Env[T] {
 	return env.(*Env[T])
 }
 
+// LookupEnv is same as GetEnv, and reports whether the env is initialized with T.
+func LookupEnv[T any]() (*Env[T], bool) {
+	e, ok := env.(*Env[T])
+	return e, ok
+}
+
 func FreeEnv[T any]() {
 	e := env.(*Env[T])
 	if err := os.RemoveAll(e.opt.Workdir); err != nil {
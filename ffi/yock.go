@@ -95,6 +95,7 @@ func pathf(path string) string {
 
 func ffiLibrary(l *lua.LState) int {
 	name := l.CheckString(1)
+	yockr.Guard(l, yockr.CapFFI, name)
 	lib := yockf.Open(name)
 
 	l.CheckTable(2).ForEach(func(fn, declare lua.LValue) {
@@ -19,6 +19,7 @@ require (
 	github.com/swaggo/swag v1.16.1
 	github.com/yuin/gluamapper v0.0.0-20150323120927-d836955830e7
 	github.com/yuin/gopher-lua v1.1.0
+	go.etcd.io/bbolt v1.3.7
 	go.uber.org/zap v1.21.0
 	google.golang.org/grpc v1.55.0
 	layeh.com/gopher-luar v1.0.10
@@ -4,7 +4,15 @@
 
 package yocki
 
+import "time"
+
 type GoPool interface {
 	Go(func())
+	// GoWith pushes function into the queue of group,
+	// and the function with higher priority runs first in the group.
+	GoWith(group string, priority int, f func())
 	Run()
+	// Shutdown waits for queued and running functions to finish,
+	// and reports whether they're drained before timeout.
+	Shutdown(timeout time.Duration) bool
 }
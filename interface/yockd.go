@@ -36,6 +36,7 @@ type YockdClient interface {
 	YockdClientMeta
 	YockdClientNet
 	YockdClientProcess
+	YockdClientJob
 }
 
 type YockdClientFS interface {
@@ -48,10 +49,18 @@ type YockdClientProcess interface {
 	ProcessKill(pid int64) error
 	ProcessFind(pid int64, cmd string) ([]*pb.Process, error)
 	ProcessSpawn(t pb.ProcessSpawnType, spec, cmd string) (int64, error)
+	// ProcessAttach calls output with the recent and following output of process,
+	// and state with each change of its state, until the process is stopped.
+	ProcessAttach(pid int64, output func([]byte), state func(*pb.ProcessStateChange)) error
 }
 
 type YockdClientGateway interface{}
 
+type YockdClientJob interface {
+	// Dispatch runs the job on the node, and calls log with each line printed by job
+	Dispatch(req *pb.DispatchRequest, log func(string)) (*pb.DispatchResult, error)
+}
+
 type YockdClientMeta interface {
 	IsPublic() bool
 	Name() string
@@ -66,12 +75,19 @@ type YockdClientNet interface {
 	Dial(form, to *pb.NodeInfo) error
 	Call(node, method string, args ...string) (string, error)
 	MakeTunnel(name string, ctx context.Context, p Promise, event chan PromiseEvent) error
+	// ClusterMembers returns the members of cluster known by the node
+	ClusterMembers() ([]*pb.Member, error)
 }
 
+// YockdClientSignal operates the signals of daemon in namespace ns, which
+// isolates the signals of runs, and the signals are global when ns is empty.
 type YockdClientSignal interface {
-	SignalNotify(sig string) error
-	SignalWait(sig string) (bool, error)
-	SignalInfo(sig string) (bool, bool, error)
-	SignalList() ([]string, error)
-	SignalClear(sigs ...string) error
+	// SignalNotify sets sig true, and it expires after ttl unless ttl is 0
+	SignalNotify(ns, sig string, ttl time.Duration) error
+	// SignalWait returns the status of sig, and registers it with ttl when it's absent
+	SignalWait(ns, sig string, ttl time.Duration) (bool, error)
+	SignalInfo(ns, sig string) (bool, bool, error)
+	SignalList(ns string) ([]string, error)
+	// SignalClear removes sigs, and all signals of ns are removed when sigs are absent
+	SignalClear(ns string, sigs ...string) error
 }
@@ -26,6 +26,8 @@ type YockScheduler interface {
 
 	// yocks goroutines
 	Do(f func())
+	// Spawn runs fn with a new state derived from parent in goroutines pool
+	Spawn(parent YockState, priority int, fn func(YockState))
 
 	GetTask(name string) bool
 	AppendTask(name string, job YockJob)
@@ -84,6 +86,9 @@ type SignalStream interface {
 	Load(sig string) (any, bool)
 	// Store settings specify the value of the singal, similar to map's kv storage.
 	Store(sig string, v bool)
+	// Subscribe returns a channel which is closed once the signal is stored with true,
+	// and the function to cancel subscription.
+	Subscribe(sig string) (<-chan struct{}, func())
 }
 
 type YockLoader interface {
@@ -0,0 +1,296 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package liby
+
+import (
+	"reflect"
+	"sync"
+	"time"
+
+	yocki "github.com/ansurfen/yock/interface"
+	lua "github.com/yuin/gopher-lua"
+)
+
+const channelTypeName = "yock_chan"
+
+// channel carries lua values between goroutines, and each value
+// is deep-copied on sending, so that the receiver owns what it gets
+// rather than racing with the sender on the same table.
+type channel struct {
+	ch chan lua.LValue
+	// typ is the lua type of values, and empty means any type.
+	typ    string
+	mut    sync.Mutex
+	closed bool
+}
+
+func LoadChannel(yocks yocki.YockScheduler) {
+	l := yocks.State().LState()
+	mt := l.NewTypeMetatable(channelTypeName)
+	l.SetField(mt, "__index", l.SetFuncs(l.NewTable(), map[string]lua.LGFunction{
+		"send":  channelSend,
+		"recv":  channelRecv,
+		"close": channelClose,
+		"len":   channelLen,
+		"cap":   channelCap,
+	}))
+	// select of lua is still available when the first argument isn't a table
+	baseSelect, _ := l.GetGlobal("select").(*lua.LFunction)
+	yocks.RegYocksFn(yocki.YocksFuncs{
+		"chan": channelNew,
+		"send": func(yocks yocki.YockScheduler, state yocki.YockState) int {
+			return channelSend(state.LState())
+		},
+		"recv": func(yocks yocki.YockScheduler, state yocki.YockState) int {
+			return channelRecv(state.LState())
+		},
+		"select": func(yocks yocki.YockScheduler, state yocki.YockState) int {
+			if state.IsTable(1) || baseSelect == nil {
+				return channelSelect(state.LState())
+			}
+			return baseSelect.GFunction(state.LState())
+		},
+	})
+}
+
+// channelNew creates a channel with buffer size n,
+// and values are checked when typ is given.
+//
+// @param n? number
+//
+// @param typ? string
+//
+// @return userdata
+func channelNew(yocks yocki.YockScheduler, state yocki.YockState) int {
+	l := state.LState()
+	n := l.OptInt(1, 0)
+	if n < 0 {
+		l.ArgError(1, "negative buffer size")
+	}
+	ud := l.NewUserData()
+	ud.Value = &channel{
+		ch:  make(chan lua.LValue, n),
+		typ: l.OptString(2, ""),
+	}
+	l.SetMetatable(ud, l.GetTypeMetatable(channelTypeName))
+	l.Push(ud)
+	return 1
+}
+
+func checkChannel(l *lua.LState, n int) *channel {
+	ud := l.CheckUserData(n)
+	if c, ok := ud.Value.(*channel); ok {
+		return c
+	}
+	l.ArgError(n, "chan expected")
+	return nil
+}
+
+// checkValue returns the copy of the n-th argument which is going to be sent
+func (c *channel) checkValue(l *lua.LState, n int) lua.LValue {
+	v := l.Get(n)
+	if len(c.typ) > 0 && v.Type().String() != c.typ {
+		l.ArgError(n, c.typ+" expected, got "+v.Type().String())
+	}
+	return copyValue(v, make(map[*lua.LTable]*lua.LTable))
+}
+
+// channelSend sends value into channel, and blocks until the value is
+// taken by others or buffered, or timeout is exceeded when it's given.
+//
+// @param ch userdata
+//
+// @param v any
+//
+// @param timeout? number
+//
+// @return boolean
+func channelSend(l *lua.LState) int {
+	c := checkChannel(l, 1)
+	v := c.checkValue(l, 2)
+	idx, _, _ := selectCases(l, []reflect.SelectCase{{
+		Dir:  reflect.SelectSend,
+		Chan: reflect.ValueOf(c.ch),
+		Send: reflect.ValueOf(v),
+	}}, time.Duration(l.OptNumber(3, -1)))
+	l.Push(lua.LBool(idx == 0))
+	return 1
+}
+
+// channelRecv receives value from channel, and blocks until a value arrives,
+// channel is closed or timeout is exceeded when it's given.
+// The second returned value is false when nothing is received.
+//
+// @param ch userdata
+//
+// @param timeout? number
+//
+// @return any, boolean
+func channelRecv(l *lua.LState) int {
+	c := checkChannel(l, 1)
+	idx, v, ok := selectCases(l, []reflect.SelectCase{{
+		Dir:  reflect.SelectRecv,
+		Chan: reflect.ValueOf(c.ch),
+	}}, time.Duration(l.OptNumber(2, -1)))
+	if idx != 0 || !ok {
+		l.Push(lua.LNil)
+		l.Push(lua.LFalse)
+		return 2
+	}
+	l.Push(v)
+	l.Push(lua.LTrue)
+	return 2
+}
+
+// channelSelect waits on multiple channels and performs the first ready case.
+// The channel in cases means receiving from it, and {ch, v} means sending v into ch.
+// It returns the index of case and what's received, and the index is nil when
+// timeout is exceeded.
+//
+// @param cases table
+//
+// @param timeout? number
+//
+// @return number|nil, any, boolean
+func channelSelect(l *lua.LState) int {
+	tbl := l.CheckTable(1)
+	cases := []reflect.SelectCase{}
+	for i := 1; i <= tbl.Len(); i++ {
+		switch v := tbl.RawGetInt(i).(type) {
+		case *lua.LUserData:
+			c, ok := v.Value.(*channel)
+			if !ok {
+				l.ArgError(1, "chan expected in cases")
+			}
+			cases = append(cases, reflect.SelectCase{
+				Dir:  reflect.SelectRecv,
+				Chan: reflect.ValueOf(c.ch),
+			})
+		case *lua.LTable:
+			ud, ok := v.RawGetInt(1).(*lua.LUserData)
+			if !ok {
+				l.ArgError(1, "chan expected in cases")
+			}
+			c, ok := ud.Value.(*channel)
+			if !ok {
+				l.ArgError(1, "chan expected in cases")
+			}
+			val := v.RawGetInt(2)
+			if len(c.typ) > 0 && val.Type().String() != c.typ {
+				l.ArgError(1, c.typ+" expected, got "+val.Type().String())
+			}
+			cases = append(cases, reflect.SelectCase{
+				Dir:  reflect.SelectSend,
+				Chan: reflect.ValueOf(c.ch),
+				Send: reflect.ValueOf(copyValue(val, make(map[*lua.LTable]*lua.LTable))),
+			})
+		default:
+			l.ArgError(1, "chan or {chan, value} expected in cases")
+		}
+	}
+	idx, v, ok := selectCases(l, cases, time.Duration(l.OptNumber(2, -1)))
+	if idx < 0 {
+		l.Push(lua.LNil)
+		l.Push(lua.LNil)
+		l.Push(lua.LFalse)
+		return 3
+	}
+	l.Push(lua.LNumber(idx + 1))
+	l.Push(v)
+	l.Push(lua.LBool(ok))
+	return 3
+}
+
+// channelClose closes channel, and the blocked receivers get nil and false.
+//
+// @param ch userdata
+func channelClose(l *lua.LState) int {
+	c := checkChannel(l, 1)
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	if !c.closed {
+		c.closed = true
+		close(c.ch)
+	}
+	return 0
+}
+
+// @param ch userdata
+//
+// @return number
+func channelLen(l *lua.LState) int {
+	l.Push(lua.LNumber(len(checkChannel(l, 1).ch)))
+	return 1
+}
+
+// @param ch userdata
+//
+// @return number
+func channelCap(l *lua.LState) int {
+	l.Push(lua.LNumber(cap(checkChannel(l, 1).ch)))
+	return 1
+}
+
+// selectCases blocks until one of cases is ready, timeout is exceeded
+// when timeout >= 0, or the state is canceled. 0 timeout means polling. It returns -1 on timeout,
+// and raises error when the state is canceled or sends on closed channel.
+func selectCases(l *lua.LState, cases []reflect.SelectCase, timeout time.Duration) (idx int, v lua.LValue, ok bool) {
+	n := len(cases)
+	switch {
+	case timeout == 0:
+		// polls without blocking, otherwise the expired timer competes with ready cases
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectDefault})
+	case timeout > 0:
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(timer.C)})
+	}
+	if ctx := l.Context(); ctx != nil {
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())})
+	}
+	var (
+		recv reflect.Value
+		err  any
+	)
+	func() {
+		defer func() { err = recover() }()
+		idx, recv, ok = reflect.Select(cases)
+	}()
+	if err != nil {
+		l.RaiseError("%v", err)
+	}
+	switch {
+	case idx < n:
+		v = lua.LNil
+		if ok {
+			v = recv.Interface().(lua.LValue)
+		}
+		return idx, v, ok
+	case timeout >= 0 && idx == n:
+		return -1, lua.LNil, false
+	}
+	l.RaiseError("canceled while waiting for chan")
+	return -1, lua.LNil, false
+}
+
+// copyValue returns the deep copy of v. Tables are copied with their keys
+// and values, and references between them are kept, while others are
+// immutable or shared by nature (functions and userdata), which are
+// returned as they are.
+func copyValue(v lua.LValue, seen map[*lua.LTable]*lua.LTable) lua.LValue {
+	src, ok := v.(*lua.LTable)
+	if !ok {
+		return v
+	}
+	if dst, ok := seen[src]; ok {
+		return dst
+	}
+	dst := &lua.LTable{Metatable: src.Metatable}
+	seen[src] = dst
+	src.ForEach(func(key, value lua.LValue) {
+		dst.RawSet(copyValue(key, seen), copyValue(value, seen))
+	})
+	return dst
+}
@@ -0,0 +1,125 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package liby_test
+
+import (
+	"testing"
+	"time"
+
+	yocks "github.com/ansurfen/yock/scheduler"
+)
+
+func evalChannel(t *testing.T, script string) {
+	t.Helper()
+	ys := yocks.Default(yocks.OptionLibPath("../../yock"))
+	go ys.EventLoop()
+	defer ys.Shutdown(time.Second)
+	if err := ys.Eval(script); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChannelSendRecv(t *testing.T) {
+	evalChannel(t, `
+local ch = chan(2)
+assert(send(ch, 1) and ch:send("a"), "buffered send shouldn't block")
+assert(ch:len() == 2 and ch:cap() == 2)
+assert(not send(ch, 3, 0), "send on full channel should time out")
+local v, ok = recv(ch)
+assert(v == 1 and ok)
+v, ok = ch:recv()
+assert(v == "a" and ok)
+
+local unbuffered, done = chan(), chan()
+go(function()
+    unbuffered:send({ n = 1 })
+    done:send(true)
+end)
+v, ok = recv(unbuffered, 1000000000)
+assert(ok and v.n == 1, "unbuffered recv should get what's sent")
+assert(recv(done, 1000000000))
+
+local start = time.Now()
+v, ok = recv(chan(), 50000000)
+assert(v == nil and not ok, "recv should time out")
+assert(time.Since(start) >= 50000000)
+
+local typed = chan(1, "number")
+assert(not pcall(send, typed, "1"), "value of wrong type is sent")
+`)
+}
+
+func TestChannelClose(t *testing.T) {
+	evalChannel(t, `
+local ch = chan(1)
+send(ch, 1)
+ch:close()
+ch:close()
+local v, ok = recv(ch)
+assert(v == 1 and ok, "buffered value should be received after close")
+v, ok = recv(ch)
+assert(v == nil and not ok, "recv on closed channel shouldn't block")
+assert(not pcall(send, ch, 2), "send on closed channel should raise error")
+
+local blocked, done = chan(), chan(1)
+go(function()
+    local v, ok = recv(blocked)
+    send(done, v == nil and not ok)
+end)
+time.Sleep(20 * time.Millisecond)
+blocked:close()
+v, ok = recv(done, 1000000000)
+assert(ok and v, "blocked recv should be woken by close")
+`)
+}
+
+func TestChannelSelect(t *testing.T) {
+	evalChannel(t, `
+local a, b = chan(1), chan(1)
+local idx, v, ok = select({ a, b }, 0)
+assert(idx == nil and v == nil and not ok, "select should poll without ready case")
+
+send(b, "b")
+idx, v, ok = select({ a, b })
+assert(idx == 2 and v == "b" and ok, "select should recv from ready case")
+
+idx, v, ok = select({ { a, "x" }, b })
+assert(idx == 1 and a:len() == 1, "select should send to ready case")
+idx = select({ { a, "y" } }, 20000000)
+assert(idx == nil, "send case on full channel should time out")
+v = recv(a)
+assert(v == "x")
+
+b:close()
+idx, v, ok = select({ b })
+assert(idx == 1 and v == nil and not ok, "select should recv from closed channel")
+
+assert(not pcall(select, { "a" }), "invalid case is accepted")
+
+-- select of lua is still available
+assert(select("#", 1, 2, 3) == 3)
+assert(select(2, "a", "b", "c") == "b")
+`)
+}
+
+func TestChannelCopy(t *testing.T) {
+	evalChannel(t, `
+local ch = chan(1)
+local src = { name = "a", nested = { list = { 1, 2 } } }
+src.self = src
+src.nested.parent = src
+local mt = {}
+setmetatable(src, mt)
+send(ch, src)
+src.name = "b"
+src.nested.list[1] = 3
+
+local dst = recv(ch)
+assert(dst ~= src and dst.nested ~= src.nested, "table should be copied")
+assert(dst.name == "a" and dst.nested.list[1] == 1, "copy is changed by sender")
+assert(dst.self == dst and dst.nested.parent == dst, "references should be kept")
+assert(getmetatable(dst) == mt, "metatable should be kept")
+`)
+}
@@ -15,6 +15,7 @@ import (
 
 	yockc "github.com/ansurfen/yock/cmd"
 	yocki "github.com/ansurfen/yock/interface"
+	yockr "github.com/ansurfen/yock/runtime"
 	"github.com/ansurfen/yock/util"
 	"github.com/ansurfen/yock/ycho"
 	lua "github.com/yuin/gopher-lua"
@@ -76,6 +77,7 @@ func LoadGNU(yocks yocki.YockScheduler) {
 }
 
 func gnuLsof(s yocki.YockState) int {
+	yockr.Guard(s.LState(), yockr.CapExec, "lsof")
 	infos, err := yockc.Lsof()
 	if err != nil {
 		s.PushNilTable()
@@ -121,6 +123,7 @@ func gnuSSH(yocks yocki.YockScheduler, state yocki.YockState) int {
 	opt := yockc.SSHOpt{}
 	if state.IsTable(1) {
 		state.CheckTable(1).Bind(&opt)
+		yockr.Guard(state.LState(), yockr.CapNet, opt.IP)
 		cli, err := yockc.NewSSHClient(opt)
 		if err != nil {
 			state.PushNil().Throw(err)
@@ -141,6 +144,7 @@ func gnuSSH(yocks yocki.YockScheduler, state yocki.YockState) int {
 }
 
 func gnuIPTablesList(s yocki.YockState) int {
+	yockr.Guard(s.LState(), yockr.CapExec, "iptables")
 	opt := yockc.IPTablesListOpt{}
 	if err := s.CheckTable(1).Bind(&opt); err != nil {
 		s.PushNilTable().Throw(err)
@@ -175,6 +179,7 @@ func gnuIPTablesList(s yocki.YockState) int {
 }
 
 func gnuIPTablesAdd(s yocki.YockState) int {
+	yockr.Guard(s.LState(), yockr.CapExec, "iptables")
 	opt := yockc.IPTablesOpOpt{Op: yockc.IPTablesAdd}
 	if err := s.CheckTable(1).Bind(&opt); err != nil {
 		ychoLogger(err, "%siptables add", s.Stacktrace())
@@ -192,6 +197,7 @@ func gnuIPTablesAdd(s yocki.YockState) int {
 }
 
 func gnuIPTablesDel(s yocki.YockState) int {
+	yockr.Guard(s.LState(), yockr.CapExec, "iptables")
 	opt := yockc.IPTablesOpOpt{Op: yockc.IPTablesDel}
 	if err := s.CheckTable(1).Bind(&opt); err != nil {
 		ychoLogger(err, "%siptables delete", s.Stacktrace())
@@ -209,6 +215,7 @@ func gnuIPTablesDel(s yocki.YockState) int {
 }
 
 func gnuSystemCtlCreate(s yocki.YockState) int {
+	yockr.Guard(s.LState(), yockr.CapExec, "systemctl")
 	opt := yockc.SCCreateOpt{}
 	name := s.CheckString(1)
 	if err := s.CheckTable(2).Bind(&opt); err != nil {
@@ -221,6 +228,7 @@ func gnuSystemCtlCreate(s yocki.YockState) int {
 }
 
 func gnuSystemCtlList(s yocki.YockState) int {
+	yockr.Guard(s.LState(), yockr.CapExec, "systemctl")
 	var (
 		optType   string
 		optStatus string
@@ -250,6 +258,7 @@ func gnuSystemCtlList(s yocki.YockState) int {
 }
 
 func gnuSystemCtlStatus(s yocki.YockState) int {
+	yockr.Guard(s.LState(), yockr.CapExec, "systemctl")
 	infos, err := yockc.SystemCtlStatus(yockc.SystemCtlStatusOpt{
 		Name: s.CheckString(1),
 	})
@@ -266,31 +275,37 @@ func gnuSystemCtlStatus(s yocki.YockState) int {
 }
 
 func gnuSystemCtlStop(s yocki.YockState) int {
+	yockr.Guard(s.LState(), yockr.CapExec, "systemctl")
 	s.PushError(yockc.SystemCtlStop(s.CheckString(1)))
 	return 1
 }
 
 func gnuSystemCtlDelete(s yocki.YockState) int {
+	yockr.Guard(s.LState(), yockr.CapExec, "systemctl")
 	s.PushError(yockc.SystemCtlDelete(s.CheckString(1)))
 	return 1
 }
 
 func gnuSystemCtlStart(s yocki.YockState) int {
+	yockr.Guard(s.LState(), yockr.CapExec, "systemctl")
 	s.PushError(yockc.SystemCtlStart(s.CheckString(1)))
 	return 1
 }
 
 func gnuSystemCtlEnable(s yocki.YockState) int {
+	yockr.Guard(s.LState(), yockr.CapExec, "systemctl")
 	s.PushBool(yockc.SystemCtlIsEnable(s.CheckString(1)))
 	return 1
 }
 
 func gnuSystemCtlDisable(s yocki.YockState) int {
+	yockr.Guard(s.LState(), yockr.CapExec, "systemctl")
 	s.PushError(yockc.SystemCtlDisable(s.CheckString(1)))
 	return 1
 }
 
 func gnuIfconfig(s yocki.YockState) int {
+	yockr.Guard(s.LState(), yockr.CapNet)
 	stats, err := util.Net().Interfaces()
 	if err != nil {
 		s.PushNilTable().Throw(err)
@@ -305,6 +320,7 @@ func gnuIfconfig(s yocki.YockState) int {
 }
 
 func gnuPGrep(s yocki.YockState) int {
+	yockr.Guard(s.LState(), yockr.CapExec, "pgrep")
 	process := yockc.PGrep(s.CheckString(1))
 	tbl := &lua.LTable{}
 	for i := 0; i < len(process); i++ {
@@ -318,6 +334,7 @@ func gnuPGrep(s yocki.YockState) int {
 }
 
 func gnuKill(s yocki.YockState) int {
+	yockr.Guard(s.LState(), yockr.CapExec, "kill")
 	if s.IsString(1) {
 		name := s.CheckString(1)
 		err := yockc.KillByName(name)
@@ -340,6 +357,7 @@ func gnuUnset(s yocki.YockState) int {
 		opt.Expand = true
 		key, value, _ = strings.Cut(key, ":")
 	}
+	yockr.Guard(s.LState(), yockr.CapEnv, key)
 	err := yockc.Unset(opt, key, value)
 	ychoLogger(err, "%sunset %s", s.Stacktrace(), key)
 	s.PushError(err)
@@ -350,11 +368,13 @@ func gnuExport(s yocki.YockState) int {
 	if s.Argc() > 1 {
 		k := s.CheckString(1)
 		v := s.CheckString(2)
+		yockr.Guard(s.LState(), yockr.CapEnv, k)
 		err := yockc.Export(yockc.ExportOpt{}, k, v)
 		ychoLogger(err, "%sexport %s=%s", s.Stacktrace(), k, v)
 	} else {
 		kv := strings.SplitN(s.CheckString(1), ":", 2)
 		if len(kv) == 2 {
+			yockr.Guard(s.LState(), yockr.CapEnv, kv[0])
 			err := yockc.Export(yockc.ExportOpt{Expand: true}, kv[0], kv[1])
 			ychoLogger(err, "%sexport %s=$%s:%s", s.Stacktrace(), kv[0], kv[0], kv[1])
 		} else {
@@ -371,6 +391,7 @@ func gnuExport(s yocki.YockState) int {
 func gnuExportL(s yocki.YockState) int {
 	k := s.CheckString(1)
 	v := s.CheckString(2)
+	yockr.Guard(s.LState(), yockr.CapEnv, k)
 	err := yockc.ExportL(k, v)
 	ychoLogger(err, "%sexportl %s=%s", s.Stacktrace(), k, v)
 	s.PushError(err)
@@ -385,6 +406,7 @@ func gnuEnviron(s yocki.YockState) int {
 	l := s.LState()
 	if l.GetTop() >= 1 {
 		k := l.CheckString(1)
+		yockr.Guard(l, yockr.CapEnv, k)
 		for _, e := range os.Environ() {
 			before, after, ok := strings.Cut(e, "=")
 			if ok && before == k {
@@ -398,6 +420,7 @@ func gnuEnviron(s yocki.YockState) int {
 			}
 		}
 	} else {
+		yockr.Guard(l, yockr.CapEnv)
 		for i, e := range os.Environ() {
 			before, after, ok := strings.Cut(e, "=")
 			if ok {
@@ -413,6 +436,7 @@ func gnuEnviron(s yocki.YockState) int {
 
 func gnuUnsetL(s yocki.YockState) int {
 	k := s.CheckString(1)
+	yockr.Guard(s.LState(), yockr.CapEnv, k)
 	err := yockc.UnsetL(k)
 	ychoLogger(err, "%sunsetl %s", s.Stacktrace(), k)
 	s.PushError(err)
@@ -427,6 +451,7 @@ func gnuWhereis(s yocki.YockState) int {
 }
 
 func gnuPS(s yocki.YockState) int {
+	yockr.Guard(s.LState(), yockr.CapExec, "ps")
 	opt := yockc.PSOpt{}
 	p := -1
 	cmd := ""
@@ -471,6 +496,7 @@ func gnuPS(s yocki.YockState) int {
 
 func gnuNohup(s yocki.YockState) int {
 	cmd := s.CheckString(1)
+	yockr.Guard(s.LState(), yockr.CapExec, cmd)
 	err := yockc.Nohup(cmd)
 	ychoLogger(err, "%snohup %s", s.Stacktrace(), cmd)
 	s.PushError(err)
@@ -485,6 +511,7 @@ func gnuFind(s yocki.YockState) int {
 			s.PushNil().Throw(err)
 			return 2
 		}
+		yockr.Guard(s.LState(), yockr.CapFSRead, s.CheckString(2))
 		res, err := yockc.Find(opt, s.CheckString(2))
 		if err != nil {
 			s.PushNil().Throw(err)
@@ -498,6 +525,7 @@ func gnuFind(s yocki.YockState) int {
 		return 2
 	} else {
 		opt.Search = false
+		yockr.Guard(s.LState(), yockr.CapFSRead, s.CheckString(1))
 		if _, err := yockc.Find(opt, s.CheckString(1)); err != nil {
 			s.PushBool(false)
 			return 1
@@ -513,6 +541,7 @@ func gnuSudo(s yocki.YockState) int {
 		sudo = filepath.Join(util.YockPath, "bin", "sudo.bat")
 	}
 	cmd := s.CheckString(1)
+	yockr.Guard(s.LState(), yockr.CapExec, "sudo "+cmd)
 	_, err := yockc.Exec(yockc.ExecOpt{Quiet: true, Sandbox: true}, sudo+" "+cmd)
 	ychoLogger(err, "%ssudo %s", s.Stacktrace(), cmd)
 	return 0
@@ -524,6 +553,7 @@ func gnuSudo(s yocki.YockState) int {
 func gnuAlias(s yocki.YockState) int {
 	k := s.CheckString(1)
 	v := s.CheckString(2)
+	yockr.Guard(s.LState(), yockr.CapExec, v)
 	ychoLogger(nil, "alias %s %s", k, v)
 	yockc.Alias(k, v)
 	return 0
@@ -544,6 +574,7 @@ func gnuUnalias(s yocki.YockState) int {
 // @return err error
 func gnuPwd(s yocki.YockState) int {
 	path, err := os.Getwd()
+	yockr.Guard(s.LState(), yockr.CapFSRead, path)
 	s.PushString(path).PushError(err)
 	return 2
 }
@@ -574,6 +605,24 @@ func gnuEcho(s yocki.YockState) int {
 	} else {
 		opt.Fd = []string{"stdout"}
 	}
+	for _, fd := range opt.Fd {
+		if fd != "stdout" && fd != "stderr" {
+			yockr.Guard(s.LState(), yockr.CapFSWrite, fd)
+		}
+	}
+	// the standard streams are taken over by the output of state
+	w, std := yockr.OutputOf(s.LState()), 0
+	if w != nil {
+		fds := []string{}
+		for _, fd := range opt.Fd {
+			if fd == "stdout" || fd == "stderr" {
+				std++
+			} else {
+				fds = append(fds, fd)
+			}
+		}
+		opt.Fd = fds
+	}
 	tbl := &lua.LTable{}
 	for i := start; i <= s.Argc(); i++ {
 		out, err := yockc.Echo(opt, s.CheckString(i))
@@ -582,6 +631,9 @@ func gnuEcho(s yocki.YockState) int {
 			s.Push(tbl).Throw(err)
 			return 2
 		}
+		for j := 0; j < std; j++ {
+			fmt.Fprint(w, out)
+		}
 	}
 	s.Push(tbl).PushNil()
 	return 2
@@ -594,6 +646,7 @@ func gnuLs(s yocki.YockState) int {
 	opt := yockc.LsOpt{
 		Dir: s.CheckString(1),
 	}
+	yockr.Guard(s.LState(), yockr.CapFSRead, opt.Dir)
 
 	if s.IsFunction(2) {
 		opt.SetRecurse()
@@ -643,6 +696,7 @@ func gnuChmod(s yocki.YockState) int {
 		s.Throw(err)
 		return 1
 	}
+	yockr.Guard(s.LState(), yockr.CapFSWrite, s.CheckString(1))
 	err = yockc.Chmod(s.CheckString(1), mode)
 	s.PushError(err)
 	return 1
@@ -655,6 +709,7 @@ func gnuChmod(s yocki.YockState) int {
 * @return err
  */
 func gnuChown(s yocki.YockState) int {
+	yockr.Guard(s.LState(), yockr.CapFSWrite, s.CheckString(1))
 	err := yockc.Chown(s.CheckString(1), s.CheckInt(2), s.CheckInt(3))
 	s.PushError(err)
 	return 1
@@ -665,6 +720,7 @@ func gnuChown(s yocki.YockState) int {
 // @return err
 func gnuCd(s yocki.YockState) int {
 	wd := s.CheckString(1)
+	yockr.Guard(s.LState(), yockr.CapFSRead, wd)
 	err := yockc.Cd(wd)
 	ychoLogger(err, "%scd %s", s.Stacktrace(), wd)
 	s.PushError(err)
@@ -688,6 +744,7 @@ func ychoLogger(err error, format string, a ...any) {
 // @return err
 func gnuTouch(s yocki.YockState) int {
 	file := s.CheckString(1)
+	yockr.Guard(s.LState(), yockr.CapFSWrite, file)
 	err := util.SafeWriteFile(file, nil)
 	ychoLogger(err, "%stouch %s", s.Stacktrace(), file)
 	s.PushError(err)
@@ -698,6 +755,7 @@ func gnuTouch(s yocki.YockState) int {
 //
 // @return string, err
 func gnuCat(s yocki.YockState) int {
+	yockr.Guard(s.LState(), yockr.CapFSRead, s.CheckString(1))
 	out, err := util.ReadStraemFromFile(s.CheckString(1))
 	s.PushString(string(out)).PushError(err)
 	return 2
@@ -712,6 +770,7 @@ func gnuCat(s yocki.YockState) int {
 func gnuMv(s yocki.YockState) int {
 	src := s.CheckString(1)
 	dst := s.CheckString(2)
+	yockr.Guard(s.LState(), yockr.CapFSWrite, src, dst)
 	err := yockc.Mv(yockc.MvOpt{}, src, dst)
 	ychoLogger(err, "%smv %s %s", s.Stacktrace(), src, dst)
 	s.PushError(err)
@@ -735,6 +794,8 @@ func gnuCp(s yocki.YockState) int {
 		}
 		if s.IsTable(2) {
 			s.CheckLTable(2).ForEach(func(src, dst lua.LValue) {
+				yockr.Guard(s.LState(), yockr.CapFSRead, src.String())
+				yockr.Guard(s.LState(), yockr.CapFSWrite, dst.String())
 				err := yockc.Cp(opt, src.String(), dst.String())
 				ychoLogger(err, fmt.Sprintf("%scp %s %s", s.Stacktrace(), src.String(), dst.String()))
 				if err != nil {
@@ -754,6 +815,8 @@ func gnuCp(s yocki.YockState) int {
 		}
 	}
 	if len(paths) >= 2 {
+		yockr.Guard(s.LState(), yockr.CapFSRead, paths[0])
+		yockr.Guard(s.LState(), yockr.CapFSWrite, paths[1])
 		g_err = yockc.Cp(opt, paths[0], paths[1])
 		ychoLogger(g_err, fmt.Sprintf("%scp %s %s", s.Stacktrace(), paths[0], paths[1]))
 	} else {
@@ -763,6 +826,8 @@ func gnuCp(s yocki.YockState) int {
 			}
 			kv := strings.Split(str, " ")
 			if len(kv) == 2 {
+				yockr.Guard(s.LState(), yockr.CapFSRead, kv[0])
+				yockr.Guard(s.LState(), yockr.CapFSWrite, kv[1])
 				err := yockc.Cp(opt, kv[0], kv[1])
 				ychoLogger(err, fmt.Sprintf("%scp %s %s", s.Stacktrace(), kv[0], kv[1]))
 				if err != nil {
@@ -783,6 +848,7 @@ func gnuMkdir(s yocki.YockState) int {
 	var err error
 	for i := 1; i <= s.Argc(); i++ {
 		path := s.CheckString(i)
+		yockr.Guard(s.LState(), yockr.CapFSWrite, path)
 		err = util.SafeMkdirs(path)
 		ychoLogger(err, "%smkdir %s", s.Stacktrace(), path)
 	}
@@ -811,6 +877,7 @@ func gnuRm(s yocki.YockState) int {
 			targets = append(targets, s.CheckString(i))
 		}
 	}
+	yockr.Guard(s.LState(), yockr.CapFSWrite, targets...)
 	var err error
 	for _, t := range targets {
 		err = yockc.Rm(opt, t)
@@ -24,15 +24,20 @@ func LoadGoroutine(yocks yocki.YockScheduler) {
 // goroutineGo wraps the callback function of the Lua language into a go callback
 // and passes it into the goroutines for unified scheduling.
 // @param fn function
+//
+// @param opt? table
 func goroutineGo(yocks yocki.YockScheduler, state yocki.YockState) int {
 	fn := state.CheckFunction(1)
-	yocks.Do(func() {
-		tmp, cancel := yocks.NewState()
-		if cancel != nil {
-			defer cancel()
+	priority := 0
+	if opt := state.LState().OptTable(2, nil); opt != nil {
+		if p, ok := opt.RawGetString("priority").(lua.LNumber); ok {
+			priority = int(p)
 		}
+	}
+	yocks.Spawn(state, priority, func(tmp yocki.YockState) {
 		if err := tmp.Call(yocki.YockFuncInfo{
-			Fn: fn,
+			Fn:      fn,
+			Protect: true,
 		}); err != nil {
 			ycho.Warn(err)
 		}
@@ -40,33 +45,27 @@ func goroutineGo(yocks yocki.YockScheduler, state yocki.YockState) int {
 	return 0
 }
 
+// goroutineWait blocks until the signal is notified,
+// or deadline is exceeded when it's given.
+//
 // @param sig string
+//
+// @param deadline? number
 func goroutineWait(yocks yocki.YockScheduler, state yocki.YockState) int {
 	sig := state.CheckString(1)
 	deadline := int64(state.LState().OptNumber(2, lua.LNumber(-1)))
 	if _, ok := yocks.Signal().Load(sig); !ok {
 		yocks.Signal().Store(sig, false)
 	}
-	cnt := 0
-	die := isTimeout(deadline)
-	for {
-		if die() {
-			return 0
-		}
-		if sig, ok := yocks.Signal().Load(sig); ok && sig.(bool) {
-			break
-		}
-		round := 1 + cnt>>2
-		if round > 10 {
-			round = 10
-		}
-		time.Sleep(time.Duration(round) * time.Second)
-		cnt++
-	}
+	waitSignals(yocks, state, []string{sig}, deadline)
 	return 0
 }
 
+// goroutineWaits is similar to goroutineWait, but waits for multiple signals.
+//
 // @param sig ...string
+//
+// @param deadline? number
 func goroutineWaits(yocks yocki.YockScheduler, state yocki.YockState) int {
 	sigs := []string{}
 	n := state.Argc()
@@ -78,30 +77,37 @@ func goroutineWaits(yocks yocki.YockScheduler, state yocki.YockState) int {
 	for i := 1; i <= n; i++ {
 		sigs = append(sigs, state.CheckString(i))
 	}
-	cnt := 0
-	die := isTimeout(deadline)
-	for {
-		if die() {
-			return 0
-		}
-		flag := true
-		for i := 0; i < len(sigs); i++ {
-			if sig, ok := yocks.Signal().Load(sigs[i]); !ok || (ok && !sig.(bool)) {
-				flag = false
-				break
-			}
-		}
-		if flag {
-			break
-		}
-		round := 1 + cnt>>2
-		if round > 10 {
-			round = 10
+	waitSignals(yocks, state, sigs, deadline)
+	return 0
+}
+
+// waitSignals subscribes signals and blocks until all of them arrive,
+// deadline is exceeded or the state is canceled.
+func waitSignals(yocks yocki.YockScheduler, state yocki.YockState, sigs []string, deadline int64) {
+	var timeout <-chan time.Time
+	if deadline != -1 {
+		timer := time.NewTimer(time.Duration(deadline))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	var canceled <-chan struct{}
+	if ctx := state.LState().Context(); ctx != nil {
+		canceled = ctx.Done()
+	}
+	for _, sig := range sigs {
+		ch, unsubscribe := yocks.Signal().Subscribe(sig)
+		select {
+		case <-ch:
+			unsubscribe()
+		case <-timeout:
+			unsubscribe()
+			return
+		case <-canceled:
+			unsubscribe()
+			state.LState().RaiseError("canceled while waiting for %s", sig)
+			return
 		}
-		time.Sleep(time.Duration(round) * time.Second)
-		cnt++
 	}
-	return 0
 }
 
 // @param sig string
@@ -110,13 +116,3 @@ func goroutineNotify(yocks yocki.YockScheduler, state yocki.YockState) int {
 	yocks.Signal().Store(sig, true)
 	return 0
 }
-
-func isTimeout(deadline int64) func() bool {
-	old := time.Now().Add(time.Duration(deadline))
-	return func() bool {
-		if deadline == -1 {
-			return false
-		}
-		return time.Now().Compare(old) == 1
-	}
-}
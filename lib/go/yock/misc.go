@@ -19,6 +19,7 @@ import (
 
 	yockc "github.com/ansurfen/yock/cmd"
 	yocki "github.com/ansurfen/yock/interface"
+	yockr "github.com/ansurfen/yock/runtime"
 	"github.com/ansurfen/yock/util"
 	"github.com/ansurfen/yock/ycho"
 	"github.com/spf13/cobra"
@@ -61,6 +62,14 @@ func LoadMisc(yocks yocki.YockScheduler) {
 	yocks.SetGlobalFn(map[string]lua.LGFunction{
 		"print": func(l *lua.LState) int {
 			top := l.GetTop()
+			if w := yockr.OutputOf(l); w != nil {
+				args := []string{}
+				for i := 1; i <= top; i++ {
+					args = append(args, l.ToStringMeta(l.Get(i)).String())
+				}
+				fmt.Fprintln(w, strings.Join(args, "\t"))
+				return 0
+			}
 			for i := 1; i <= top; i++ {
 				ycho.Print(l.ToStringMeta(l.Get(i)).String())
 				if i != top {
@@ -110,12 +119,29 @@ func DoProxy(w http.ResponseWriter, r *http.Request) {
 	io.Copy(w, resProxyBody)
 }
 
+// ychoOutput writes the message to the output of s with level,
+// and reports false when s prints to stdout.
+func ychoOutput(s yocki.YockState, level string) bool {
+	w := yockr.OutputOf(s.LState())
+	if w == nil {
+		return false
+	}
+	fmt.Fprintf(w, "[%s] %s%s\n", level, s.Stacktrace(), s.CheckString(1))
+	return true
+}
+
 func ychoInfo(s yocki.YockState) int {
+	if ychoOutput(s, "INFO") {
+		return 0
+	}
 	ycho.Infof("%s%s", s.Stacktrace(), s.CheckString(1))
 	return 0
 }
 
 func ychoDebug(s yocki.YockState) int {
+	if ychoOutput(s, "DEBUG") {
+		return 0
+	}
 	ycho.Debugf("%s%s", s.Stacktrace(), s.CheckString(1))
 	return 0
 }
@@ -126,11 +152,17 @@ func ychoFatal(s yocki.YockState) int {
 }
 
 func ychoError(s yocki.YockState) int {
+	if ychoOutput(s, "ERROR") {
+		return 0
+	}
 	ycho.Errorf("%s%s", s.Stacktrace(), s.CheckString(1))
 	return 0
 }
 
 func ychoWarn(s yocki.YockState) int {
+	if ychoOutput(s, "WARN") {
+		return 0
+	}
 	ycho.Warnf("%s%s", s.Stacktrace(), s.CheckString(1))
 	return 0
 }
@@ -211,7 +243,11 @@ func eval(s yocki.YockState) int {
 //
 // @return userdata, err
 func openConf(s yocki.YockState) int {
-	conf, err := util.OpenConf(s.CheckString(1))
+	path := s.CheckString(1)
+	yockr.Guard(s.LState(), yockr.CapFSRead, path)
+	// the configuration can be written to any path
+	yockr.Guard(s.LState(), yockr.CapFSWrite)
+	conf, err := util.OpenConf(path)
 	s.Pusha(conf).PushError(err)
 	return 2
 }
@@ -274,11 +310,16 @@ func osSh(s yocki.YockState) int {
 			cmds = append(cmds, s.CheckString(i))
 		}
 	}
+	if w := yockr.OutputOf(s.LState()); w != nil && opt.Redirect {
+		opt.Redirect = false
+		opt.Output = w
+	}
 	outs := &lua.LTable{}
 	var g_err error
 	for _, cmd := range cmds {
 		util.ReadLineFromString(cmd, func(str string) string {
 			if len(str) > 0 {
+				yockr.Guard(s.LState(), yockr.CapExec, str)
 				out, err := yockc.Exec(opt, str)
 				ychoLogger(err, "%ssh %s", s.Stacktrace(), str)
 				outs.Append(lua.LString(out))
@@ -352,6 +393,14 @@ func netCurl(yocks yocki.YockScheduler, s yocki.YockState) int {
 			urls = append(urls, s.CheckString(i))
 		}
 	}
+	yockr.Guard(s.LState(), yockr.CapNet, urls...)
+	if opt.Save {
+		dir := opt.Dir
+		if len(dir) == 0 {
+			dir = "."
+		}
+		yockr.Guard(s.LState(), yockr.CapFSWrite, dir)
+	}
 	str, err := yockc.Curl(opt, urls)
 	ychoLogger(err, "%scurl %s", s.Stacktrace(), strings.Join(urls, ","))
 	s.PushString(string(str)).PushError(err)
@@ -387,6 +436,7 @@ func netIsLocalhost(s yocki.YockState) int {
 * @return err
  */
 func safe_write(s yocki.YockState) int {
+	yockr.Guard(s.LState(), yockr.CapFSWrite, s.CheckString(1))
 	err := util.SafeWriteFile(s.CheckString(1), []byte(s.CheckString(2)))
 	s.PushError(err)
 	return 1
@@ -398,6 +448,7 @@ func safe_write(s yocki.YockState) int {
 * @return err
  */
 func write_file(s yocki.YockState) int {
+	yockr.Guard(s.LState(), yockr.CapFSWrite, s.CheckString(1))
 	err := util.WriteFile(s.CheckString(1), []byte(s.CheckString(2)))
 	s.PushError(err)
 	return 1
@@ -1,16 +1,311 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
 package test
 
-import yocki "github.com/ansurfen/yock/interface"
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
-type TestInterface interface {
-	Hello()
-}
+	yocki "github.com/ansurfen/yock/interface"
+	yockr "github.com/ansurfen/yock/runtime"
+	lua "github.com/yuin/gopher-lua"
+)
 
+// LoadTest registers test, setup and teardown, which declare the cases of
+// *_test.lua instead of running them. The cases are run by Suite.Run.
+//
+//	setup(function() write_file("a.txt", "") end)
+//	teardown(function() rm("a.txt") end)
+//	test("cat", function(t)
+//	    t:equal(cat("a.txt"), "")
+//	    t:same({ 1, { 2 } }, { 1, { 2 } })
+//	end)
+//	test("windows", function(t) end, { skip = "unix only" })
 func LoadTest(yocks yocki.YockScheduler) {
-	lib := yocks.CreateLib("test")
-	lib.SetField(map[string]any{
-		"TestInterface": func(t TestInterface) {
-			t.Hello()
+	yocks.RegYockFn(yocki.YockFuns{
+		"test":     testTest,
+		"setup":    testSetup,
+		"teardown": testTeardown,
+	})
+}
+
+// Case is the block declared by test
+type Case struct {
+	Name string
+	// Skip is the reason of skipping case, and it's empty when case runs.
+	Skip string
+	fn   *lua.LFunction
+}
+
+// Suite collects what the test file declares
+type Suite struct {
+	Cases     []*Case
+	setups    []*lua.LFunction
+	teardowns []*lua.LFunction
+}
+
+const suiteKey = "__yock_test_suite"
+
+// SuiteOf returns the suite declared in l, and the threads of l share it.
+func SuiteOf(l *lua.LState) *Suite {
+	if ud, ok := l.G.Registry.RawGetString(suiteKey).(*lua.LUserData); ok {
+		if suite, ok := ud.Value.(*Suite); ok {
+			return suite
+		}
+	}
+	suite := &Suite{}
+	ud := l.NewUserData()
+	ud.Value = suite
+	l.G.Registry.RawSetString(suiteKey, ud)
+	return suite
+}
+
+// @param name string
+//
+// @param fn fun(t: test)
+//
+// @param opt? table
+func testTest(s yocki.YockState) int {
+	l := s.LState()
+	c := &Case{Name: l.CheckString(1), fn: l.CheckFunction(2)}
+	if opt := l.OptTable(3, nil); opt != nil {
+		if skip := opt.RawGetString("skip"); skip != lua.LNil && skip != lua.LFalse {
+			c.Skip = "skipped"
+			if reason, ok := skip.(lua.LString); ok {
+				c.Skip = string(reason)
+			}
+		}
+	}
+	suite := SuiteOf(l)
+	for _, prev := range suite.Cases {
+		if prev.Name == c.Name {
+			l.RaiseError("duplicate test %q", c.Name)
+		}
+	}
+	suite.Cases = append(suite.Cases, c)
+	return 0
+}
+
+// @param fn function
+func testSetup(s yocki.YockState) int {
+	suite := SuiteOf(s.LState())
+	suite.setups = append(suite.setups, s.LState().CheckFunction(1))
+	return 0
+}
+
+// @param fn function
+func testTeardown(s yocki.YockState) int {
+	suite := SuiteOf(s.LState())
+	suite.teardowns = append(suite.teardowns, s.LState().CheckFunction(1))
+	return 0
+}
+
+// The status of Result
+const (
+	StatusPass = "pass"
+	StatusFail = "fail"
+	StatusSkip = "skip"
+)
+
+// Result is the outcome of running case
+type Result struct {
+	Name   string
+	Status string
+	// Message is the reason of failure or skipping
+	Message string
+	// Trace is the stack trace of failure
+	Trace    string
+	Logs     []string
+	Duration time.Duration
+}
+
+// skipSignal is raised by t:skip to stop the case
+type skipSignal struct {
+	reason string
+}
+
+// Run runs the case named name, along with setups before and teardowns
+// after it. The teardowns are always run, even if the case fails.
+func (suite *Suite) Run(l *lua.LState, name string) (res Result) {
+	res = Result{Name: name, Status: StatusPass}
+	start := time.Now()
+	defer func() {
+		res.Duration = time.Since(start)
+	}()
+	var c *Case
+	for _, cur := range suite.Cases {
+		if cur.Name == name {
+			c = cur
+		}
+	}
+	if c == nil {
+		res.Status, res.Message = StatusFail, fmt.Sprintf("test %q not found", name)
+		return
+	}
+	if len(c.Skip) > 0 {
+		res.Status, res.Message = StatusSkip, c.Skip
+		return
+	}
+	s := yockr.UpgradeLState(l)
+	call := func(fn *lua.LFunction, args ...any) error {
+		return s.Call(yocki.YockFuncInfo{Fn: fn, Protect: true}, args...)
+	}
+	var err error
+	for _, setup := range suite.setups {
+		if err = call(setup); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = call(c.fn, newT(l, &res))
+	}
+	for _, teardown := range suite.teardowns {
+		if terr := call(teardown); err == nil {
+			err = terr
+		}
+	}
+	if err != nil {
+		res.Status, res.Message, res.Trace = failure(err)
+	}
+	return
+}
+
+// failure returns the status, message and stack trace of err
+func failure(err error) (status, msg, trace string) {
+	status, msg = StatusFail, err.Error()
+	apiErr := &lua.ApiError{}
+	if errors.As(err, &apiErr) {
+		if ud, ok := apiErr.Object.(*lua.LUserData); ok {
+			if skip, ok := ud.Value.(*skipSignal); ok {
+				return StatusSkip, skip.reason, ""
+			}
+		}
+		msg, trace = apiErr.Object.String(), apiErr.StackTrace
+	}
+	limitErr := &yockr.LimitError{}
+	if errors.As(err, &limitErr) {
+		msg = fmt.Sprintf("exceed %s limit %s", limitErr.Limit, limitErr.Max)
+	}
+	return
+}
+
+// newT returns the object passed to case, which asserts and logs for it.
+// The assertion raises error at the first failure, and stops the case.
+func newT(l *lua.LState, res *Result) *lua.LTable {
+	t := l.NewTable()
+	fail := func(l *lua.LState, msgIdx int, format string, a ...any) {
+		msg := fmt.Sprintf(format, a...)
+		if prefix := l.OptString(msgIdx, ""); len(prefix) > 0 {
+			msg = prefix + ": " + msg
+		}
+		l.RaiseError("%s", msg)
+	}
+	l.SetFuncs(t, map[string]lua.LGFunction{
+		"equal": func(l *lua.LState) int {
+			got, want := l.CheckAny(2), l.CheckAny(3)
+			if !l.Equal(got, want) {
+				fail(l, 4, "got %s, want %s", inspect(got), inspect(want))
+			}
+			return 0
+		},
+		"same": func(l *lua.LState) int {
+			if diffs := Diff(l.CheckAny(2), l.CheckAny(3)); len(diffs) > 0 {
+				fail(l, 4, "not same\n\t%s", strings.Join(diffs, "\n\t"))
+			}
+			return 0
 		},
+		"ok": func(l *lua.LState) int {
+			if v := l.Get(2); v == lua.LNil || v == lua.LFalse {
+				fail(l, 3, "got %s, want truthy value", inspect(v))
+			}
+			return 0
+		},
+		"fail": func(l *lua.LState) int {
+			l.RaiseError("%s", l.OptString(2, "failed"))
+			return 0
+		},
+		"skip": func(l *lua.LState) int {
+			ud := l.NewUserData()
+			ud.Value = &skipSignal{reason: l.OptString(2, "skipped")}
+			l.Error(ud, 0)
+			return 0
+		},
+		"log": func(l *lua.LState) int {
+			args := []string{}
+			for i := 2; i <= l.GetTop(); i++ {
+				args = append(args, l.ToStringMeta(l.Get(i)).String())
+			}
+			res.Logs = append(res.Logs, strings.Join(args, " "))
+			return 0
+		},
+	})
+	return t
+}
+
+// Diff returns the differences between got and want, one line for each of
+// them with the path of field, such as got.a[1]: 1 ~= 2. The tables are
+// compared field by field, and the others are compared in raw.
+func Diff(got, want lua.LValue) []string {
+	diffs := []string{}
+	diff("got", got, want, map[[2]*lua.LTable]bool{}, &diffs)
+	sort.Strings(diffs)
+	return diffs
+}
+
+func diff(path string, got, want lua.LValue, seen map[[2]*lua.LTable]bool, diffs *[]string) {
+	gt, gok := got.(*lua.LTable)
+	wt, wok := want.(*lua.LTable)
+	if !gok || !wok {
+		if got != want {
+			*diffs = append(*diffs, fmt.Sprintf("%s: %s ~= %s", path, inspect(got), inspect(want)))
+		}
+		return
+	}
+	// the tables referring to each other are compared once
+	if seen[[2]*lua.LTable{gt, wt}] {
+		return
+	}
+	seen[[2]*lua.LTable{gt, wt}] = true
+	wt.ForEach(func(k, wv lua.LValue) {
+		gv := gt.RawGet(k)
+		if gv == lua.LNil {
+			*diffs = append(*diffs, fmt.Sprintf("%s: missing, want %s", path+fieldPath(k), inspect(wv)))
+			return
+		}
+		diff(path+fieldPath(k), gv, wv, seen, diffs)
+	})
+	gt.ForEach(func(k, gv lua.LValue) {
+		if wt.RawGet(k) == lua.LNil {
+			*diffs = append(*diffs, fmt.Sprintf("%s: unexpected %s", path+fieldPath(k), inspect(gv)))
+		}
 	})
 }
+
+func fieldPath(k lua.LValue) string {
+	if name, ok := k.(lua.LString); ok && isName(string(name)) {
+		return "." + string(name)
+	}
+	return "[" + inspect(k) + "]"
+}
+
+func isName(s string) bool {
+	for i, ch := range s {
+		if ch != '_' && !(ch >= 'a' && ch <= 'z') && !(ch >= 'A' && ch <= 'Z') && !(i > 0 && ch >= '0' && ch <= '9') {
+			return false
+		}
+	}
+	return len(s) > 0
+}
+
+func inspect(v lua.LValue) string {
+	if str, ok := v.(lua.LString); ok {
+		return strconv.Quote(string(str))
+	}
+	return v.String()
+}
@@ -24,6 +24,7 @@ conf = {}
 function conf.create(file, tmpl) end
 
 ---open must open specified file, otherwise it would panic.
+---In sandbox, fs:write is required without scope, for the conf can be saved to any path.
 ---@param file string
 ---@return conf
 function conf.open(file) end
@@ -35,10 +35,17 @@
 ---
 ---waits("x", "y")
 ---```
+---
+---The goroutines are queued in a pool limited by `yocks.goroutine.maxGoroutine`
+---of configuration, and opt.priority decides the order within the same task.
 ---view [document](https://ansurfen.github.io/YockNav/guide/concurrency.html#goroutine-coroutines-with-stack)
 ---@async
 ---@param callback fun()
-function go(callback) end
+---@param opt? goroutine_option
+function go(callback, opt) end
+
+---@class goroutine_option
+---@field priority? integer # higher runs first, 0 by default
 
 ---wait blocks self and waits for sig.
 ---If lack signal, it'll block for ever.
@@ -95,3 +102,93 @@ function waits(...) end
 ---view [document](https://ansurfen.github.io/YockNav/guide/concurrency.html#semaphores)
 ---@param sig string
 function notify(sig) end
+
+---@class chan
+local chan_obj = {}
+
+---send puts v into channel just like send(ch, v, timeout)
+---@param v any
+---@param timeout? time
+---@return boolean
+function chan_obj:send(v, timeout) end
+
+---recv takes a value from channel just like recv(ch, timeout)
+---@param timeout? time
+---@return any, boolean
+function chan_obj:recv(timeout) end
+
+---close closes channel, and recv returns nil, false after
+---the buffered values are taken.
+function chan_obj:close() end
+
+---@return integer
+function chan_obj:len() end
+
+---@return integer
+function chan_obj:cap() end
+
+---chan creates a channel to pass values between goroutines.
+---The value sent is deep-copied, so that the receiver won't
+---race with the sender on the same table. Functions and userdata
+---are still shared. When typ is given, only the value whose type()
+---equals typ can be sent.
+---
+---### Example:
+---```lua
+---local artifacts = chan(10, "table")
+---go(function()
+---    for _, url in ipairs(urls) do
+---        artifacts:send({ url = url, file = curl(url) })
+---    end
+---    artifacts:close()
+---end)
+---
+---while true do
+---    local artifact, ok = artifacts:recv()
+---    if not ok then break end
+---    -- unpack artifact.file
+---end
+---```
+---@param n? integer # size of buffer, 0 by default
+---@param typ? string
+---@return chan
+function chan(n, typ) end
+
+---send blocks until v is taken or buffered, and returns false
+---when timeout is exceeded. It raises error when channel is closed.
+---@param ch chan
+---@param v any
+---@param timeout? time
+---@return boolean
+function send(ch, v, timeout) end
+
+---recv blocks until a value arrives, and the second returned
+---value is false when channel is closed or timeout is exceeded.
+---@param ch chan
+---@param timeout? time
+---@return any, boolean
+function recv(ch, timeout) end
+
+---select performs the first ready case, the channel in cases means
+---receiving from it, and { ch, v } means sending v into ch.
+---It returns the index of case, what's received and whether the
+---channel is open, and the index is nil when timeout is exceeded.
+---0 timeout means polling without blocking.
+---
+---When the first argument isn't a table, it works as select of lua.
+---
+---### Example:
+---```lua
+---local idx, v, ok = select({ logs, { jobs, job } }, time.Second)
+---if idx == 1 then
+---    print(v)
+---elseif idx == 2 then
+---    print("job sent")
+---else
+---    print("timeout")
+---end
+---```
+---@param cases table<integer, chan|table>
+---@param timeout? time
+---@return integer|nil, any, boolean
+function select(cases, timeout) end
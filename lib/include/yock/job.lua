@@ -11,6 +11,7 @@
 ---@field args string[]
 ---@field task string
 ---@field flags? table<string, any>
+---@field matrix? table<string, any> # coordinates of the cell when job is expanded from matrix
 local context = {}
 
 ---@alias ec integer
@@ -35,6 +36,8 @@ function context.assert(ok, msg) end
 ---@param error string
 function context.throw(error) end
 
+---yield sleeps for timeout, and wakes up in advance
+---with error when the job is canceled.
 ---@param timeout? integer
 function context.yield(timeout) end
 
@@ -98,9 +101,108 @@ function context.set_os(os) end
 ---
 ---# use `yock run main.lua test` to run test task for the above code.
 ---```
+---
+---A job also can declare the tasks it needs in opt, and yock will arrange
+---them as a directed acyclic graph. The needs always run before the job,
+---independent tasks run concurrently, and the job will be skipped
+---when any of its needs fails.
+---
+---### Example:
+---```lua
+---job("fetch", function(ctx) end)
+---job("proto", function(ctx) end)
+---job("build", function(ctx) end, { needs = { "fetch", "proto" } })
+---job("deploy", function(ctx) end, { needs = "build" })
+---
+---# `yock run main.lua deploy` runs fetch and proto concurrently,
+---# and then build, deploy in turn.
+---```
+---
+---A job declared with matrix is expanded into a job per combination of the
+---matrix, which receives its coordinates in ctx.matrix. The cells run one by one,
+---a failed cell doesn't stop others but fails the task at last.
+---
+---### Example:
+---```lua
+---job("build", function(ctx)
+---    sh(string.format("go build -o yock-%s-%s", ctx.matrix.os, ctx.matrix.arch))
+---end, { matrix = { os = { "linux", "windows" }, arch = { "amd64", "arm64" } } })
+---```
 ---@param name string
 ---@param callback fun(ctx: context)
-function job(name, callback) end
+---@param opt? job_option
+function job(name, callback, opt) end
+
+---
+---When inputs or outputs is declared, the job runs incrementally. Yock will
+---record the checksum of files in cache (`yock clean --fingerprint` to reset) after the job
+---runs successfully, and skip it while both inputs and outputs are unchanged.
+---
+---### Example:
+---```lua
+---job("build", function(ctx)
+---    sh("go build -o bin/yock ./ctl")
+---end, { inputs = { "go.mod", "**/*.go" }, outputs = "bin/yock" })
+---```
+---
+---Flaky job can be limited by timeout and re-run by retry, and the delay
+---between two attempts is decided by backoff. The default policy for all
+---jobs can be set in the job field of `option`.
+---
+---### Example:
+---```lua
+---job("download", function(ctx)
+---    curl({ save = true }, "https://example.com/release.zip")
+---end, {
+---    timeout = 30 * time.Second,
+---    retry = 3,
+---    backoff = { delay = time.Second, factor = 2, max = 10 * time.Second },
+---})
+---```
+---A job declared with node runs on the yockd of that name, which is dialed by
+---`yockd.dial` or declared in the peer of yockd option. The function of job is
+---shipped along with its context and flags, and what it prints by print, echo, sh and ycho is sent back.
+---Only the upvalues and context fields that json can encode are shipped.
+---
+---### Example:
+---```lua
+---local version = "1.0.0"
+---job("deploy", function(ctx)
+---    print(sh("systemctl restart app-" .. version))
+---end, { node = "web-1" })
+---```
+---
+---The node can also be a selector, and the job runs on the least-loaded
+---member of cluster that matches it, which is measured by the usage of cpu
+---and memory gossiped by yockd. The cluster is known by the default yockd.
+---When the member can't be reached, the next is tried like loadbalance.
+---
+---### Example:
+---```lua
+---job("train", function(ctx)
+---    sh("python train.py")
+---end, { node = { os = "linux", labels = { "gpu-free", "docker" } } })
+---```
+---@class job_option
+---@field needs? string|string[] # tasks must be finished before the job runs
+---@field inputs? string|string[] # glob patterns of files read by job, ** matches any directories
+---@field outputs? string|string[] # files or directories written by job
+---@field timeout? time # cancels the job when it overruns
+---@field retry? integer # maximum times to re-run the job after it fails
+---@field backoff? time|job_backoff # delay before re-running the job
+---@field priority? integer # higher task runs first when goroutines are limited
+---@field matrix? table<string, any> # expands job into a job per combination, such as { os = { "linux", "windows" } }
+---@field node? string|job_node_selector # name of yockd where job runs instead of local, or selector of it
+
+---@class job_node_selector
+---@field os? string # such as linux, windows, darwin
+---@field arch? string # such as amd64, arm64
+---@field labels? string|string[] # labels that the member must have all
+
+---@class job_backoff
+---@field delay time
+---@field factor? number # grows delay after each failure, 1 by default
+---@field max? time
 
 ---jobs composes multiple jobs to form a task and share the namespace with the job.
 ---This means that if jobs and job have the same name, yock will also throw an error directly.
@@ -128,6 +230,44 @@ function job(name, callback) end
 ---@vararg string
 function jobs(name, ...) end
 
+---hook registers fn on the event of jobs in task, and it's global
+---when task isn't specified. The events are:
+---
+---* before: called before job runs, and job fails when the hook fails.
+---* after: called after job succeeds, and job fails when the hook fails.
+---* on_failure: called after job fails.
+---* finally: always called after job ends, even if it throws.
+---
+---The global hooks are called ahead of task's before job, and behind after job.
+---Hooks run in new states, with the copy of job's context and its result.
+---The copy only holds data, and functions of context such as throw are left out.
+---### Example:
+---```lua
+---hook("finally", "build", function(ctx, res)
+---    rm({ safe = false }, "tmp")
+---end)
+---
+---hook("on_failure", function(ctx, res)
+---    print(res.source, res.error)
+---end)
+---```
+---@param event "before"|"after"|"on_failure"|"finally"
+---@param task? string
+---@param fn fun(ctx: context, res?: job_result)
+function hook(event, task, fn) end
+
+---@class job_result
+---@field task string
+---@field source string # identity of job in form of task:job[cell]
+---@field cell string # coordinates of matrix, such as arch=amd64,os=linux
+---@field node string # name of yockd where job runs, and it's empty for local job
+---@field status "succeeded"|"failed"|"skipped"|"up-to-date"
+---@field error string
+---@field traceback string
+---@field duration time
+---@field exit_code integer
+---@field attempts integer
+
 ---@class option_ycho
 ---@field stdout? boolean # allows logger print on terminal, if true
 
@@ -185,6 +325,7 @@ function jobs(name, ...) end
 ---@field ycho? option_ycho
 ---@field yockd? option_yockd
 ---@field yockw? option_yockw
+---@field job? job_option # default timeout, retry and backoff for all jobs
 ---@field strict? boolean # automatically panic when error occurs.
 ---@field sync? boolean # synchronize to configuration (yock.yaml), and it's not recommended.
 
@@ -0,0 +1,75 @@
+-- Copyright 2023 The Yock Authors. All rights reserved.
+-- Use of this source code is governed by a MIT-style
+-- license that can be found in the LICENSE file.
+
+---@meta _
+
+---test declares the case of *_test.lua, which is run by `yock test`
+---in isolated state, along with setups before and teardowns after it.
+---The top level of file is run again for every case, so that it should
+---only declare cases and fixtures.
+---
+---### Example:
+---```lua
+---setup(function()
+---    write_file("a.txt", "yock")
+---end)
+---
+---teardown(function()
+---    rm("a.txt")
+---end)
+---
+---test("cat", function(t)
+---    t:equal(cat("a.txt"), "yock")
+---    t:same({ a = { 1, 2 } }, { a = { 1, 2 } })
+---end)
+---
+---test("launchctl", function(t) end, { skip = "darwin only" })
+---```
+---@param name string
+---@param fn fun(t: test)
+---@param opt? test_option
+function test(name, fn, opt) end
+
+---@class test_option
+---@field skip? boolean|string # the reason of skipping case
+
+---setup registers fn called before each case
+---@param fn fun()
+function setup(fn) end
+
+---teardown registers fn called after each case, even if it fails
+---@param fn fun()
+function teardown(fn) end
+
+---@class test
+local t = {}
+
+---equal fails the case when got ~= want
+---@param got any
+---@param want any
+---@param msg? string
+function t:equal(got, want, msg) end
+
+---same compares tables field by field, and fails
+---the case with the differences of them
+---@param got any
+---@param want any
+---@param msg? string
+function t:same(got, want, msg) end
+
+---ok fails the case when v is nil or false
+---@param v any
+---@param msg? string
+function t:ok(v, msg) end
+
+---@param msg? string
+function t:fail(msg) end
+
+---skip stops the case and marks it skipped
+---@param reason? string
+function t:skip(reason) end
+
+---log records the message shown in the report of case
+---@param ... any
+function t:log(...) end
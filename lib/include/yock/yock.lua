@@ -219,6 +219,8 @@ function yockd_fs.read(file) end
 ---@param name? string
 function yockd_fs.volume(name) end
 
+---yockd_signal operates the signals of yockd in the namespace of run,
+---which is set by `yock run --run-id`, and the signals are global without it.
 ---@class yockd_signal
 local yockd_signal = {}
 
@@ -228,6 +230,7 @@ function yockd_signal.list() end
 ---@return boolean exist, boolean status, err
 function yockd_signal.info(sig) end
 
+---clear removes the signals, and all signals of the run are removed when none is given.
 ---@vararg string
 function yockd_signal.clear(...) end
 
@@ -257,8 +260,37 @@ function yockd_net.call(node, method, ...) end
 ---@field signal yockd_signal
 ---@field net yockd_net
 ---@field process yockd_process
+---@field cluster yockd_cluster
 yockd = {}
 
+---@class yockd_cluster
+local yockd_cluster = {}
+
+---@class cluster_member
+---@field name string
+---@field addr string
+---@field state string|'alive'|'suspect'|'dead'
+---@field heartbeat integer
+---@field os string
+---@field arch string
+---@field labels string[]
+---@field cpu number # usage percent of cpu
+---@field mem number # usage percent of memory
+local cluster_member = {}
+
+---members returns the members of cluster known by yockd,
+---which are found and checked by gossip among yockd.
+---### Example:
+---```lua
+---local members, err = yockd.cluster.members()
+---for _, m in ipairs(members) do
+---    print(m.name, m.state, m.os, m.cpu)
+---end
+---```
+---@param node? string # name of yockd asked, and it's the default yockd when absent
+---@return cluster_member[], err
+function yockd_cluster.members(node) end
+
 ---@class yockd_process
 local yockd_process = {}
 
@@ -281,7 +313,7 @@ function yockd_process.kill(id) end
 
 ---@class process
 ---@field pid integer
----@field state string|'create'|'ready'|'suspend'|'running'|'destory'
+---@field state integer
 ---@field spec string
 ---@field cmd string
 ---@field type string
@@ -290,6 +322,29 @@ local process = {}
 ---@return process[]
 function yockd_process.list() end
 
+---@class process_state
+---@field state integer
+---@field name string|'new'|'ready'|'suspend'|'running'|'wait'|'stopped'
+---@field exit_code integer # exit code of the last run of process
+local process_state = {}
+
+---attach prints the recent output of process kept by yockd, and then tails it
+---until the process is stopped. When fn is present, it's called with each chunk
+---of output instead.
+---
+---### Example:
+---```lua
+---local pid = yockd.process.spawn("script", "", "go test ./...")
+---local state, err = yockd.process.attach(pid, function(out)
+---    io.write(out)
+---end)
+---print(state.name, state.exit_code)
+---```
+---@param id integer
+---@param fn? fun(out: string)
+---@return process_state, err
+function yockd_process.attach(id, fn) end
+
 ---@param name string
 ---@return err
 function yockd.ping(name) end
@@ -297,6 +352,7 @@ function yockd.ping(name) end
 ---@param name string
 ---@param ip string
 ---@param port integer
+---@return err
 function yockd.dial(name, ip, port) end
 
 ---@param src string
@@ -0,0 +1,379 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yockp
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yuin/gopher-lua/ast"
+)
+
+// LuaSymbolKind indicates what the symbol declared in annotations is
+type LuaSymbolKind int
+
+const (
+	// SymbolFunction is function or method, such as echo, json.encode and platform:Exf
+	SymbolFunction LuaSymbolKind = iota
+	// SymbolTable is global table, such as env and json
+	SymbolTable
+	// SymbolField is field of class, such as args of env
+	SymbolField
+)
+
+// LuaSymbol is the declaration in annotations (lib/include),
+// which is documented by comments in form of ---@param, ---@field etc.
+type LuaSymbol struct {
+	// Name is the full name of symbol, such as echo, json.encode, platform:Exf and env.args
+	Name string
+	Kind LuaSymbolKind
+	// Type is the type of table or field, such as platform for env.platform
+	Type string
+	// Signature is the declaration of function, such as function echo(opt, ...)
+	Signature string
+	// Doc is the description in markdown
+	Doc     string
+	Params  []LuaParam
+	Returns []string
+	File    string
+	Line    int
+}
+
+// LuaParam is the parameter of function or the field of class
+type LuaParam struct {
+	Name string
+	Type string
+	Doc  string
+}
+
+// Label returns the last part of name, such as encode for json.encode
+func (sym *LuaSymbol) Label() string {
+	if i := strings.LastIndexAny(sym.Name, ".:"); i >= 0 {
+		return sym.Name[i+1:]
+	}
+	return sym.Name
+}
+
+// Markdown renders the symbol into markdown for hover
+func (sym *LuaSymbol) Markdown() string {
+	sb := &strings.Builder{}
+	switch sym.Kind {
+	case SymbolFunction:
+		fmt.Fprintf(sb, "```lua\n%s\n```\n", sym.Signature)
+	case SymbolTable:
+		fmt.Fprintf(sb, "```lua\n(global) %s: %s\n```\n", sym.Name, sym.Type)
+	case SymbolField:
+		fmt.Fprintf(sb, "```lua\n(field) %s: %s\n```\n", sym.Name, sym.Type)
+	}
+	if len(sym.Doc) > 0 {
+		sb.WriteString("\n" + sym.Doc + "\n")
+	}
+	if len(sym.Params) > 0 {
+		sb.WriteString("\n")
+		for _, param := range sym.Params {
+			fmt.Fprintf(sb, "@*param* `%s` — `%s`", param.Name, param.Type)
+			if len(param.Doc) > 0 {
+				sb.WriteString(" " + param.Doc)
+			}
+			sb.WriteString("\n\n")
+		}
+	}
+	if len(sym.Returns) > 0 {
+		fmt.Fprintf(sb, "\n@*return* `%s`\n", strings.Join(sym.Returns, ", "))
+	}
+	return sb.String()
+}
+
+// luaClass is declared by ---@class, and its fields by ---@field
+type luaClass struct {
+	name   string
+	fields []*LuaSymbol
+}
+
+// Annotations indexes the symbols declared in the stubs of lib/include,
+// which is used by language server and linter to learn about yock stdlib.
+type Annotations struct {
+	yockp YockPack[NilFrame]
+	// symbols maps the full name to its declarations, and functions might be overloaded
+	symbols map[string][]*LuaSymbol
+	classes map[string]*luaClass
+}
+
+func NewAnnotations() *Annotations {
+	return &Annotations{
+		yockp:   New(),
+		symbols: make(map[string][]*LuaSymbol),
+		classes: make(map[string]*luaClass),
+	}
+}
+
+// LoadDir loads the annotations of lua files in dir recursively
+func (ann *Annotations) LoadDir(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".lua" {
+			return nil
+		}
+		return ann.LoadFile(path)
+	})
+}
+
+// LoadFile loads the annotations of lua file
+func (ann *Annotations) LoadFile(file string) error {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	return ann.Load(file, string(raw))
+}
+
+var globalTablePattern = regexp.MustCompile(`^([A-Za-z_]\w*)\s*=\s*\{`)
+
+// Load loads the annotations of source, and file is the location of symbols
+func (ann *Annotations) Load(file, source string) error {
+	stmts, err := ann.yockp.Parse(strings.NewReader(source), file)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "---@class ") {
+			ann.loadClass(file, lines, i)
+		}
+		if m := globalTablePattern.FindStringSubmatch(line); m != nil {
+			comment := parseDocComment(lines, i+1)
+			typ := comment.class
+			if len(typ) == 0 {
+				typ = m[1]
+			}
+			ann.add(&LuaSymbol{
+				Name: m[1],
+				Kind: SymbolTable,
+				Type: typ,
+				Doc:  comment.doc,
+				File: file,
+				Line: i + 1,
+			})
+		}
+	}
+	for _, stmt := range stmts {
+		def, ok := stmt.(*ast.FuncDefStmt)
+		if !ok {
+			continue
+		}
+		name := ""
+		if def.Name.Func != nil {
+			name = parseFuncExpr(def.Name.Func)
+		} else if def.Name.Receiver != nil {
+			name = fmt.Sprintf("%s:%s", parseFuncExpr(def.Name.Receiver), def.Name.Method)
+		}
+		if len(name) == 0 {
+			continue
+		}
+		comment := parseDocComment(lines, def.Line())
+		signature := strings.TrimSpace(lines[def.Line()-1])
+		signature = strings.TrimSpace(strings.TrimSuffix(signature, "end"))
+		ann.add(&LuaSymbol{
+			Name:      name,
+			Kind:      SymbolFunction,
+			Signature: signature,
+			Doc:       comment.doc,
+			Params:    comment.params,
+			Returns:   comment.returns,
+			File:      file,
+			Line:      def.Line(),
+		})
+	}
+	return nil
+}
+
+// loadClass loads the class declared at lines[idx] and the fields following it
+func (ann *Annotations) loadClass(file string, lines []string, idx int) {
+	name := strings.Fields(strings.TrimPrefix(lines[idx], "---@class "))[0]
+	class := &luaClass{name: name}
+	for i := idx + 1; i < len(lines) && strings.HasPrefix(lines[i], "---"); i++ {
+		if !strings.HasPrefix(lines[i], "---@field ") {
+			continue
+		}
+		field := parseAnnotationParam(strings.TrimPrefix(lines[i], "---@field "))
+		class.fields = append(class.fields, &LuaSymbol{
+			Name: name + "." + field.Name,
+			Kind: SymbolField,
+			Type: field.Type,
+			Doc:  field.Doc,
+			File: file,
+			Line: i + 1,
+		})
+	}
+	ann.classes[name] = class
+}
+
+func (ann *Annotations) add(sym *LuaSymbol) {
+	ann.symbols[sym.Name] = append(ann.symbols[sym.Name], sym)
+}
+
+// Lookup returns the declarations of name, such as echo and json.encode
+func (ann *Annotations) Lookup(name string) []*LuaSymbol {
+	return ann.symbols[name]
+}
+
+// Globals returns the global functions and tables in order of name
+func (ann *Annotations) Globals() []*LuaSymbol {
+	res := []*LuaSymbol{}
+	for name, syms := range ann.symbols {
+		if !strings.ContainsAny(name, ".:") {
+			res = append(res, syms[0])
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+// Members returns the fields and functions of type, and sep decides
+// the functions to be returned, . for functions and : for methods.
+func (ann *Annotations) Members(typ, sep string) []*LuaSymbol {
+	res := []*LuaSymbol{}
+	if class, ok := ann.classes[typ]; ok && sep == "." {
+		res = append(res, class.fields...)
+	}
+	for name, syms := range ann.symbols {
+		if strings.HasPrefix(name, typ+sep) && !strings.ContainsAny(name[len(typ)+1:], ".:") {
+			res = append(res, syms[0])
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+// Member returns the declarations of field or function of type
+func (ann *Annotations) Member(typ, sep, name string) []*LuaSymbol {
+	if class, ok := ann.classes[typ]; ok && sep == "." {
+		for _, field := range class.fields {
+			if field.Label() == name {
+				return []*LuaSymbol{field}
+			}
+		}
+	}
+	return ann.symbols[typ+sep+name]
+}
+
+// docComment is the comment above declaration
+type docComment struct {
+	doc     string
+	class   string
+	params  []LuaParam
+	returns []string
+}
+
+// parseDocComment parses the comment lines (starting with ---) right above line
+func parseDocComment(lines []string, line int) docComment {
+	start := line - 1
+	for start > 0 && strings.HasPrefix(lines[start-1], "---") {
+		start--
+	}
+	comment := docComment{}
+	doc := []string{}
+	for _, l := range lines[start : line-1] {
+		l = strings.TrimPrefix(l, "---")
+		if !strings.HasPrefix(l, "@") {
+			if !strings.HasPrefix(l, "|") {
+				doc = append(doc, l)
+			}
+			continue
+		}
+		tag, content, _ := strings.Cut(l[1:], " ")
+		switch tag {
+		case "class":
+			if fields := strings.Fields(content); len(fields) > 0 {
+				comment.class = fields[0]
+			}
+		case "param":
+			comment.params = append(comment.params, parseAnnotationParam(content))
+		case "vararg":
+			comment.params = append(comment.params, LuaParam{Name: "...", Type: strings.TrimSpace(content)})
+		case "return":
+			typ, _, _ := strings.Cut(content, "#")
+			comment.returns = append(comment.returns, splitAnnotationType(typ)...)
+		}
+	}
+	comment.doc = strings.TrimSpace(strings.Join(doc, "\n"))
+	return comment
+}
+
+// parseAnnotationParam parses the content of ---@param and ---@field,
+// in form of name type # description
+func parseAnnotationParam(content string) LuaParam {
+	content = strings.TrimSpace(content)
+	name, rest, _ := strings.Cut(content, " ")
+	typ, doc, _ := strings.Cut(rest, "#")
+	return LuaParam{
+		Name: strings.TrimSuffix(name, "?"),
+		Type: strings.TrimSpace(typ),
+		Doc:  strings.TrimSpace(doc),
+	}
+}
+
+// splitAnnotationType splits types joined by comma,
+// and the commas inside of brackets are ignored
+func splitAnnotationType(typ string) []string {
+	res := []string{}
+	depth, last := 0, 0
+	for i, ch := range typ {
+		switch ch {
+		case '<', '(', '{', '[':
+			depth++
+		case '>', ')', '}', ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				res = append(res, strings.TrimSpace(typ[last:i]))
+				last = i + 1
+			}
+		}
+	}
+	if t := strings.TrimSpace(typ[last:]); len(t) > 0 {
+		res = append(res, t)
+	}
+	return res
+}
+
+// baseType returns the type for resolving members, such as
+// platform for platform?, and string for string|nil
+func baseType(typ string) string {
+	typ = strings.TrimSuffix(strings.TrimSpace(typ), "?")
+	if parts := strings.Split(typ, "|"); len(parts) > 1 && !strings.HasPrefix(typ, "fun(") {
+		typ = parts[0]
+	}
+	return strings.TrimSpace(typ)
+}
+
+// funcParamTypes returns the types of parameters of function type,
+// such as [context] for fun(ctx: context)
+func funcParamTypes(typ string) []string {
+	typ = strings.TrimSpace(typ)
+	if !strings.HasPrefix(typ, "fun(") {
+		return nil
+	}
+	end := strings.LastIndex(typ, ")")
+	if end < 0 {
+		return nil
+	}
+	res := []string{}
+	for _, param := range splitAnnotationType(typ[len("fun("):end]) {
+		_, t, ok := strings.Cut(param, ":")
+		if !ok {
+			t = "any"
+		}
+		res = append(res, strings.TrimSpace(t))
+	}
+	return res
+}
@@ -7,7 +7,6 @@ package yockp
 import (
 	"bytes"
 	"fmt"
-	"reflect"
 	"strings"
 	"text/template"
 
@@ -18,139 +17,545 @@ import (
 
 // BuildScript restores a given lua statement to source code
 func (*YockPack[T]) BuildScript(stmts []ast.Stmt, filter map[int]bool) string {
-	var buf *bytes.Buffer = new(bytes.Buffer)
-	buildStmt(buf, stmts, filter)
-	return buf.String()
+	p := &luaPrinter{buf: new(bytes.Buffer), fresh: true}
+	p.stmts(stmts, filter)
+	return p.buf.String()
+}
+
+// luaPrinter prints the statements with four spaces indent. When src is set,
+// the comments, blank lines and literals of source are restored as well.
+type luaPrinter struct {
+	buf    *bytes.Buffer
+	src    *luaSource
+	indent int
+	// last is the last line of source printed
+	last int
+	// seen is the max line of source within the statement being printed
+	seen int
+	// fresh marks the beginning of block, where no blank line is needed
+	fresh bool
 }
 
-type exprFrame struct {
-	fn bool
+func (p *luaPrinter) see(line int) {
+	if line > p.seen {
+		p.seen = line
+	}
+}
+
+func (p *luaPrinter) writeIndent() {
+	p.buf.WriteString(strings.Repeat("    ", p.indent))
+}
+
+// gap keeps one blank line when there is any between the last printed and line
+func (p *luaPrinter) gap(line int) {
+	if !p.fresh && line-p.last > 1 {
+		p.buf.WriteByte('\n')
+	}
+	p.fresh = false
+}
+
+// comments prints the comments before line on their own lines
+func (p *luaPrinter) comments(line int) {
+	if p.src == nil {
+		return
+	}
+	for c := p.src.comment(); c != nil && c.line < line; c = p.src.comment() {
+		p.gap(c.line)
+		p.writeIndent()
+		p.buf.WriteString(c.text)
+		p.buf.WriteByte('\n')
+		p.last = c.lastLine
+		p.src.next++
+	}
+}
+
+// trailing appends the comments up to line to the end of current line
+func (p *luaPrinter) trailing(line int) {
+	if p.src == nil {
+		return
+	}
+	for c := p.src.comment(); c != nil && c.line <= line; c = p.src.comment() {
+		p.buf.WriteString(" " + c.text)
+		p.see(c.lastLine)
+		p.src.next++
+	}
 }
 
-func buildStmt(buf *bytes.Buffer, chunk []ast.Stmt, filter map[int]bool) {
+func (p *luaPrinter) stmts(chunk []ast.Stmt, filter map[int]bool) {
 	for idx, stmt := range chunk {
 		if filter != nil && filter[idx] {
 			continue
 		}
-		switch v := stmt.(type) {
-		case *ast.AssignStmt:
-			buildExpr(buf, v.Lhs[0], exprFrame{})
-			buf.WriteString("=")
-			buildExpr(buf, v.Rhs[0], exprFrame{})
-			buf.WriteString("\n")
-		case *ast.FuncCallStmt:
-			buildExpr(buf, v.Expr, exprFrame{})
-		case *ast.LocalAssignStmt:
-			buf.WriteString(fmt.Sprintf("local %s = \n", v.Names[0]))
-			for _, expr := range v.Exprs {
-				buildExpr(buf, expr, exprFrame{})
-			}
-		case *ast.ReturnStmt:
-			buf.WriteString("return ")
-			for idx, expr := range v.Exprs {
-				buildExpr(buf, expr, exprFrame{})
-				if idx != len(v.Exprs)-1 {
-					buf.WriteString(", ")
-				}
-			}
-			buf.WriteString("\n")
-		case *ast.NumberForStmt:
-			buf.WriteString(fmt.Sprintf(`for %s = `, v.Name))
-			buildExpr(buf, v.Init, exprFrame{})
-			buf.WriteString(", ")
-			buildExpr(buf, v.Limit, exprFrame{})
-			if v.Step != nil {
-				buf.WriteString(", ")
-				buildExpr(buf, v.Step, exprFrame{})
-			}
-			buf.WriteString(" do\n")
-			buildStmt(buf, v.Stmts, nil)
-			buf.WriteString("end\n")
-		case *ast.IfStmt:
-			buf.WriteString("if ")
-			buildExpr(buf, v.Condition, exprFrame{})
-			buf.WriteString(" then\n")
-			buildStmt(buf, v.Then, nil)
-			if len(v.Else) != 0 {
-				buf.WriteString("else ")
-				buildStmt(buf, v.Else, nil)
-			}
-			buf.WriteString("end\n")
-		default:
-			fmt.Println(v)
+		p.stmt(stmt)
+	}
+}
+
+// block prints the body opened at line with one more level of indent
+func (p *luaPrinter) block(chunk []ast.Stmt, line int) {
+	p.indent++
+	p.last, p.fresh = line, true
+	p.stmts(chunk, nil)
+}
+
+// end closes the block at line with keyword
+func (p *luaPrinter) end(line int, keyword string) {
+	p.comments(line)
+	p.indent--
+	p.fresh = false
+	p.writeIndent()
+	p.buf.WriteString(keyword)
+	p.see(line)
+}
+
+func (p *luaPrinter) stmt(stmt ast.Stmt) {
+	p.comments(stmt.Line())
+	p.gap(stmt.Line())
+	outer := p.seen
+	p.seen = stmt.Line()
+	p.writeIndent()
+	start := p.buf.Len()
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		p.exprs(s.Lhs)
+		p.buf.WriteString(" = ")
+		p.exprs(s.Rhs)
+	case *ast.LocalAssignStmt:
+		if fn, ok := localFunction(s); ok && (p.src == nil || p.src.localFuncs[luaToken{s.Line(), s.Names[0]}]) {
+			p.buf.WriteString("local function " + s.Names[0])
+			p.function(fn)
+			break
+		}
+		p.buf.WriteString("local " + strings.Join(s.Names, ", "))
+		if len(s.Exprs) > 0 {
+			p.buf.WriteString(" = ")
+			p.exprs(s.Exprs)
+		}
+	case *ast.FuncCallStmt:
+		p.expr(s.Expr)
+	case *ast.FuncDefStmt:
+		p.buf.WriteString("function ")
+		if s.Name.Func != nil {
+			p.expr(s.Name.Func)
+		} else {
+			p.expr(s.Name.Receiver)
+			p.buf.WriteString(":" + s.Name.Method)
+		}
+		p.function(s.Func)
+	case *ast.DoBlockStmt:
+		p.buf.WriteString("do")
+		p.trailing(s.Line())
+		p.buf.WriteByte('\n')
+		p.block(s.Stmts, s.Line())
+		p.end(s.LastLine(), "end")
+	case *ast.WhileStmt:
+		p.buf.WriteString("while ")
+		p.expr(s.Condition)
+		p.buf.WriteString(" do")
+		p.trailing(p.seen)
+		p.buf.WriteByte('\n')
+		p.block(s.Stmts, p.seen)
+		p.end(s.LastLine(), "end")
+	case *ast.RepeatStmt:
+		p.buf.WriteString("repeat")
+		p.trailing(s.Line())
+		p.buf.WriteByte('\n')
+		p.block(s.Stmts, s.Line())
+		p.end(s.LastLine(), "until ")
+		p.expr(s.Condition)
+	case *ast.IfStmt:
+		p.ifStmt(s)
+	case *ast.NumberForStmt:
+		p.buf.WriteString("for " + s.Name + " = ")
+		p.expr(s.Init)
+		p.buf.WriteString(", ")
+		p.expr(s.Limit)
+		if s.Step != nil {
+			p.buf.WriteString(", ")
+			p.expr(s.Step)
+		}
+		p.buf.WriteString(" do")
+		p.trailing(p.seen)
+		p.buf.WriteByte('\n')
+		p.block(s.Stmts, p.seen)
+		p.end(s.LastLine(), "end")
+	case *ast.GenericForStmt:
+		p.buf.WriteString("for " + strings.Join(s.Names, ", ") + " in ")
+		p.exprs(s.Exprs)
+		p.buf.WriteString(" do")
+		p.trailing(p.seen)
+		p.buf.WriteByte('\n')
+		p.block(s.Stmts, p.seen)
+		p.end(s.LastLine(), "end")
+	case *ast.ReturnStmt:
+		p.buf.WriteString("return")
+		if len(s.Exprs) > 0 {
+			p.buf.WriteByte(' ')
+			p.exprs(s.Exprs)
 		}
+	case *ast.BreakStmt:
+		p.buf.WriteString("break")
+	case *ast.GotoStmt:
+		p.buf.WriteString("goto " + s.Label)
+	case *ast.LabelStmt:
+		p.buf.WriteString("::" + s.Name + "::")
 	}
+	// the statement starting with parenthesis is ambiguous with a call of last one
+	if raw := p.buf.Bytes(); start < len(raw) && raw[start] == '(' {
+		tail := append([]byte{';'}, raw[start:]...)
+		p.buf.Truncate(start)
+		p.buf.Write(tail)
+	}
+	last := p.seen
+	if stmt.LastLine() > last {
+		last = stmt.LastLine()
+	}
+	p.trailing(last)
+	p.buf.WriteByte('\n')
+	p.last = last
+	p.seen = outer
+	p.see(last)
 }
 
-func buildExpr(buf *bytes.Buffer, exp ast.Expr, frame exprFrame) {
-	switch v := exp.(type) {
-	case *ast.FunctionExpr:
-		buf.WriteString("function (")
-		for idx, arg := range v.ParList.Names {
-			buf.WriteString(arg)
-			if idx != len(v.ParList.Names)-1 {
-				buf.WriteString(", ")
-			}
+func (p *luaPrinter) ifStmt(s *ast.IfStmt) {
+	last := s.LastLine()
+	p.buf.WriteString("if ")
+	for {
+		p.expr(s.Condition)
+		p.buf.WriteString(" then")
+		p.trailing(p.seen)
+		p.buf.WriteByte('\n')
+		p.block(s.Then, p.seen)
+		if len(s.Else) == 0 {
+			break
 		}
-		buf.WriteString(")\n")
-		buildStmt(buf, v.Stmts, nil)
-		buf.WriteString("end\n")
-	case *ast.FuncCallExpr:
-		buildExpr(buf, v.Func, exprFrame{})
-		buf.WriteString("(")
-		for idx, arg := range v.Args {
-			buildExpr(buf, arg, exprFrame{fn: true})
-			if idx != len(v.Args)-1 {
-				buf.WriteString(", ")
-			}
+		if next, ok := s.Else[0].(*ast.IfStmt); ok && len(s.Else) == 1 &&
+			(p.src == nil || p.src.elseifs[next.Line()]) {
+			p.end(next.Line(), "elseif ")
+			s = next
+			continue
+		}
+		line := p.last
+		if p.src != nil {
+			line = p.src.elseAfter(p.last)
+		}
+		p.end(line, "else")
+		p.trailing(line)
+		p.buf.WriteByte('\n')
+		p.block(s.Else, line)
+		break
+	}
+	p.end(last, "end")
+}
+
+// function prints the parameters and body of fn
+func (p *luaPrinter) function(fn *ast.FunctionExpr) {
+	params := append([]string{}, fn.ParList.Names...)
+	if fn.ParList.HasVargs {
+		params = append(params, "...")
+	}
+	p.buf.WriteString("(" + strings.Join(params, ", ") + ")")
+	p.see(fn.Line())
+	if len(fn.Stmts) == 0 && !p.src.commented(fn.LastLine()) {
+		p.buf.WriteString(" end")
+		p.see(fn.LastLine())
+		return
+	}
+	p.trailing(fn.Line())
+	p.buf.WriteByte('\n')
+	p.block(fn.Stmts, fn.Line())
+	p.end(fn.LastLine(), "end")
+}
+
+func (p *luaPrinter) exprs(exprs []ast.Expr) {
+	for idx, expr := range exprs {
+		if idx != 0 {
+			p.buf.WriteString(", ")
 		}
-		buf.WriteString(")\n")
+		p.expr(expr)
+	}
+}
+
+// precedences of binary operators, and the unary operators take 7
+var luaPrecedence = map[string]int{
+	"or": 1, "and": 2,
+	"<": 3, ">": 3, "<=": 3, ">=": 3, "~=": 3, "==": 3,
+	"..": 4, "+": 5, "-": 5, "*": 6, "/": 6, "%": 6, "^": 8,
+}
+
+func binaryOp(exp ast.Expr) (ast.Expr, string, ast.Expr, bool) {
+	switch v := exp.(type) {
+	case *ast.LogicalOpExpr:
+		return v.Lhs, v.Operator, v.Rhs, true
+	case *ast.RelationalOpExpr:
+		return v.Lhs, v.Operator, v.Rhs, true
+	case *ast.ArithmeticOpExpr:
+		return v.Lhs, v.Operator, v.Rhs, true
+	case *ast.StringConcatOpExpr:
+		return v.Lhs, "..", v.Rhs, true
+	}
+	return nil, "", nil, false
+}
+
+func precedenceOf(exp ast.Expr) int {
+	if _, op, _, ok := binaryOp(exp); ok {
+		return luaPrecedence[op]
+	}
+	switch exp.(type) {
+	case *ast.UnaryMinusOpExpr, *ast.UnaryNotOpExpr, *ast.UnaryLenOpExpr:
+		return 7
+	}
+	return 9
+}
+
+// operand prints exp wrapped in parenthesis when it binds looser than prec
+func (p *luaPrinter) operand(exp ast.Expr, prec int, strict bool) {
+	if sub := precedenceOf(exp); sub < prec || (strict && sub == prec) {
+		p.buf.WriteByte('(')
+		p.expr(exp)
+		p.buf.WriteByte(')')
+		return
+	}
+	p.expr(exp)
+}
+
+// prefix prints exp as the prefix of call or index
+func (p *luaPrinter) prefix(exp ast.Expr) {
+	switch exp.(type) {
+	case *ast.IdentExpr, *ast.AttrGetExpr, *ast.FuncCallExpr:
+		p.expr(exp)
+	default:
+		p.buf.WriteByte('(')
+		p.expr(exp)
+		p.buf.WriteByte(')')
+	}
+}
+
+func (p *luaPrinter) expr(exp ast.Expr) {
+	p.see(exp.Line())
+	if lhs, op, rhs, ok := binaryOp(exp); ok {
+		prec := luaPrecedence[op]
+		// .. and ^ are right associative
+		right := op == ".." || op == "^"
+		p.operand(lhs, prec, right)
+		p.buf.WriteString(" " + op + " ")
+		p.operand(rhs, prec, !right)
+		return
+	}
+	switch v := exp.(type) {
+	case *ast.NilExpr:
+		p.buf.WriteString("nil")
+	case *ast.TrueExpr:
+		p.buf.WriteString("true")
+	case *ast.FalseExpr:
+		p.buf.WriteString("false")
+	case *ast.NumberExpr:
+		p.buf.WriteString(v.Value)
 	case *ast.StringExpr:
-		if frame.fn {
-			buf.WriteString(fmt.Sprintf(`"%s"`, v.Value))
+		p.str(v)
+	case *ast.Comma3Expr:
+		if v.AdjustRet {
+			p.buf.WriteString("(...)")
 		} else {
-			buf.WriteString(v.Value)
+			p.buf.WriteString("...")
 		}
-	case *ast.NumberExpr:
-		buf.WriteString(v.Value)
 	case *ast.IdentExpr:
-		buf.WriteString(v.Value)
+		p.buf.WriteString(v.Value)
 	case *ast.AttrGetExpr:
-		buildExpr(buf, v.Object, exprFrame{})
-		buf.Write([]byte{'.'})
-		buildExpr(buf, v.Key, exprFrame{})
-	case *ast.TrueExpr:
-		buf.WriteString("true")
-	case *ast.FalseExpr:
-		buf.WriteString("false")
+		p.prefix(v.Object)
+		if key, ok := v.Key.(*ast.StringExpr); ok && isLuaName(key.Value) {
+			p.buf.WriteString("." + key.Value)
+			break
+		}
+		p.buf.WriteByte('[')
+		p.expr(v.Key)
+		p.buf.WriteByte(']')
+	case *ast.FuncCallExpr:
+		if v.AdjustRet {
+			p.buf.WriteByte('(')
+		}
+		if v.Func != nil {
+			p.prefix(v.Func)
+		} else {
+			p.prefix(v.Receiver)
+			p.buf.WriteString(":" + v.Method)
+		}
+		p.buf.WriteByte('(')
+		p.exprs(v.Args)
+		p.buf.WriteByte(')')
+		if v.AdjustRet {
+			p.buf.WriteByte(')')
+		}
+	case *ast.FunctionExpr:
+		p.buf.WriteString("function")
+		p.function(v)
 	case *ast.TableExpr:
-		buf.WriteString("{")
+		p.table(v)
+	case *ast.UnaryMinusOpExpr:
+		p.buf.WriteByte('-')
+		// avoid -- being a comment
+		if _, ok := v.Expr.(*ast.UnaryMinusOpExpr); ok {
+			p.buf.WriteByte(' ')
+		} else if num, ok := v.Expr.(*ast.NumberExpr); ok && strings.HasPrefix(num.Value, "-") {
+			p.buf.WriteByte(' ')
+		}
+		p.operand(v.Expr, 7, false)
+	case *ast.UnaryNotOpExpr:
+		p.buf.WriteString("not ")
+		p.operand(v.Expr, 7, false)
+	case *ast.UnaryLenOpExpr:
+		p.buf.WriteByte('#')
+		p.operand(v.Expr, 7, false)
+	}
+}
+
+func (p *luaPrinter) str(v *ast.StringExpr) {
+	if raw, ok := p.src.literal(v.Line(), v.Value); ok {
+		p.buf.WriteString(raw)
+		p.see(v.Line() + strings.Count(raw, "\n"))
+		return
+	}
+	p.buf.WriteString(quoteLua(v.Value))
+}
+
+func (p *luaPrinter) table(v *ast.TableExpr) {
+	if len(v.Fields) == 0 && !p.src.commented(p.src.closing(v)) {
+		p.buf.WriteString("{}")
+		p.see(p.src.closing(v))
+		return
+	}
+	closing := p.src.closing(v)
+	if closing <= v.Line() && !p.src.commented(closing) && !hasBody(v) {
+		p.buf.WriteString("{ ")
 		for idx, field := range v.Fields {
-			if field.Key != nil {
-				buildExpr(buf, field.Key, exprFrame{})
-				buf.WriteString("=")
+			if idx != 0 {
+				p.buf.WriteString(", ")
 			}
-			buildExpr(buf, field.Value, exprFrame{fn: true})
-			if idx != len(v.Fields)-1 {
-				buf.WriteString(", ")
+			p.field(field)
+		}
+		p.buf.WriteString(" }")
+		p.see(closing)
+		return
+	}
+	p.buf.WriteByte('{')
+	p.trailing(v.Line())
+	p.buf.WriteByte('\n')
+	p.indent++
+	p.last, p.fresh = v.Line(), true
+	for _, field := range v.Fields {
+		line := field.Value.Line()
+		if field.Key != nil && field.Key.Line() > 0 {
+			line = field.Key.Line()
+		}
+		p.comments(line)
+		p.gap(line)
+		outer := p.seen
+		p.seen = line
+		p.writeIndent()
+		p.field(field)
+		p.buf.WriteByte(',')
+		p.trailing(p.seen)
+		p.buf.WriteByte('\n')
+		p.last = p.seen
+		p.seen = outer
+		p.see(p.last)
+	}
+	p.end(closing, "}")
+}
+
+func (p *luaPrinter) field(field *ast.Field) {
+	if field.Key != nil {
+		if key, ok := field.Key.(*ast.StringExpr); ok && isLuaName(key.Value) {
+			p.see(key.Line())
+			p.buf.WriteString(key.Value)
+		} else {
+			p.buf.WriteByte('[')
+			p.expr(field.Key)
+			p.buf.WriteByte(']')
+		}
+		p.buf.WriteString(" = ")
+	}
+	p.expr(field.Value)
+}
+
+// hasBody reports whether any function with statements is inside exp,
+// which always takes more than one line.
+func hasBody(exp ast.Expr) bool {
+	if lhs, _, rhs, ok := binaryOp(exp); ok {
+		return hasBody(lhs) || hasBody(rhs)
+	}
+	switch v := exp.(type) {
+	case *ast.FunctionExpr:
+		return len(v.Stmts) > 0
+	case *ast.TableExpr:
+		for _, field := range v.Fields {
+			if (field.Key != nil && hasBody(field.Key)) || hasBody(field.Value) {
+				return true
+			}
+		}
+	case *ast.AttrGetExpr:
+		return hasBody(v.Object) || hasBody(v.Key)
+	case *ast.FuncCallExpr:
+		if (v.Func != nil && hasBody(v.Func)) || (v.Receiver != nil && hasBody(v.Receiver)) {
+			return true
+		}
+		for _, arg := range v.Args {
+			if hasBody(arg) {
+				return true
+			}
+		}
+	case *ast.UnaryMinusOpExpr:
+		return hasBody(v.Expr)
+	case *ast.UnaryNotOpExpr:
+		return hasBody(v.Expr)
+	case *ast.UnaryLenOpExpr:
+		return hasBody(v.Expr)
+	}
+	return false
+}
+
+func isLuaName(name string) bool {
+	if len(name) == 0 || (name[0] >= '0' && name[0] <= '9') {
+		return false
+	}
+	for _, kw := range luaKeywords {
+		if kw == name {
+			return false
+		}
+	}
+	for i := 0; i < len(name); i++ {
+		if !isIdentChar(name[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// quoteLua returns the double-quoted literal of str
+func quoteLua(str string) string {
+	buf := &strings.Builder{}
+	buf.WriteByte('"')
+	for i := 0; i < len(str); i++ {
+		switch ch := str[i]; ch {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if ch < 0x20 || ch == 0x7f {
+				fmt.Fprintf(buf, `\%03d`, ch)
+			} else {
+				buf.WriteByte(ch)
 			}
 		}
-		buf.WriteString("}")
-	case *ast.StringConcatOpExpr:
-		buildExpr(buf, v.Lhs, exprFrame{fn: true})
-		buf.WriteString(" .. ")
-		buildExpr(buf, v.Rhs, exprFrame{fn: true})
-	case *ast.LogicalOpExpr:
-		buildExpr(buf, v.Lhs, exprFrame{})
-		buf.WriteString(" " + v.Operator + " ")
-		buildExpr(buf, v.Rhs, exprFrame{})
-	case *ast.RelationalOpExpr:
-		buildExpr(buf, v.Lhs, exprFrame{})
-		buf.WriteString(" " + v.Operator + " ")
-		buildExpr(buf, v.Rhs, exprFrame{})
-	default:
-		fmt.Println(reflect.TypeOf(v))
 	}
+	buf.WriteByte('"')
+	return buf.String()
 }
 
 type buildBootOpt struct {
@@ -132,3 +132,60 @@ func TestBuildBootScript(t *testing.T) {
 		modes: []string{"host1", "host2"},
 	})
 }
+
+func TestFormat(t *testing.T) {
+	str := `-- build the project
+local function build(target,...)   -- entry
+  if target=="linux" then return sh("make") elseif target == 'windows' then
+     -- cross compile
+     return sh("make win")
+  end
+  return (...)
+end
+
+
+job("build",function(ctx) build(env.platform.OS) end)
+option({flags={
+  -- the target of build
+  target="linux",
+}})
+local n = -(-1) + (2 - 3) - (4 - 5) * 2 ^ 3 ^ 2
+print(("x"):rep(3), [[raw
+text]]) --[[ done ]]`
+	want := `-- build the project
+local function build(target, ...) -- entry
+    if target == "linux" then
+        return sh("make")
+    elseif target == 'windows' then
+        -- cross compile
+        return sh("make win")
+    end
+    return (...)
+end
+
+job("build", function(ctx)
+    build(env.platform.OS)
+end)
+option({
+    flags = {
+        -- the target of build
+        target = "linux",
+    },
+})
+local n = - -1 + (2 - 3) - (4 - 5) * 2 ^ 3 ^ 2
+print(("x"):rep(3), [[raw
+text]]) --[[ done ]]
+`
+	yockpack := YockPack[NilFrame]{}
+	got, err := yockpack.Format(str)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Fatalf("unexpected format:\n%s", got)
+	}
+	again, err := yockpack.Format(got)
+	if err != nil || again != got {
+		t.Fatalf("format isn't idempotent:\n%s", again)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yockp
+
+import "github.com/yuin/gopher-lua/ast"
+
+// Instrument inserts the call of hook before every statement in chunk,
+// including the statements of nested functions, which gives debugger
+// the control of script line by line, since gopher-lua has no line hook.
+// The inserted calls share the lines of statements, so that the line
+// reported by debug.getinfo is unchanged.
+func (*YockPack[T]) Instrument(chunk []ast.Stmt, hook string) []ast.Stmt {
+	return instrumentStmts(chunk, hook)
+}
+
+func instrumentStmts(stmts []ast.Stmt, hook string) []ast.Stmt {
+	res := make([]ast.Stmt, 0, len(stmts)*2)
+	for _, stmt := range stmts {
+		call := &ast.FuncCallExpr{Func: &ast.IdentExpr{Value: hook}}
+		call.SetLine(stmt.Line())
+		call.SetLastLine(stmt.Line())
+		call.Func.SetLine(stmt.Line())
+		call.Func.SetLastLine(stmt.Line())
+		callStmt := &ast.FuncCallStmt{Expr: call}
+		callStmt.SetLine(stmt.Line())
+		callStmt.SetLastLine(stmt.Line())
+		res = append(res, callStmt, instrumentStmt(stmt, hook))
+	}
+	return res
+}
+
+func instrumentStmt(stmt ast.Stmt, hook string) ast.Stmt {
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		instrumentExprs(s.Lhs, hook)
+		instrumentExprs(s.Rhs, hook)
+	case *ast.LocalAssignStmt:
+		instrumentExprs(s.Exprs, hook)
+	case *ast.FuncCallStmt:
+		instrumentExpr(s.Expr, hook)
+	case *ast.DoBlockStmt:
+		s.Stmts = instrumentStmts(s.Stmts, hook)
+	case *ast.WhileStmt:
+		instrumentExpr(s.Condition, hook)
+		s.Stmts = instrumentStmts(s.Stmts, hook)
+	case *ast.RepeatStmt:
+		instrumentExpr(s.Condition, hook)
+		s.Stmts = instrumentStmts(s.Stmts, hook)
+	case *ast.IfStmt:
+		instrumentExpr(s.Condition, hook)
+		s.Then = instrumentStmts(s.Then, hook)
+		s.Else = instrumentStmts(s.Else, hook)
+	case *ast.NumberForStmt:
+		instrumentExprs([]ast.Expr{s.Init, s.Limit, s.Step}, hook)
+		s.Stmts = instrumentStmts(s.Stmts, hook)
+	case *ast.GenericForStmt:
+		instrumentExprs(s.Exprs, hook)
+		s.Stmts = instrumentStmts(s.Stmts, hook)
+	case *ast.FuncDefStmt:
+		instrumentExpr(s.Func, hook)
+	case *ast.ReturnStmt:
+		instrumentExprs(s.Exprs, hook)
+	}
+	return stmt
+}
+
+func instrumentExprs(exprs []ast.Expr, hook string) {
+	for _, expr := range exprs {
+		instrumentExpr(expr, hook)
+	}
+}
+
+// instrumentExpr finds the functions in expression and instruments their bodies
+func instrumentExpr(expr ast.Expr, hook string) {
+	switch e := expr.(type) {
+	case *ast.FunctionExpr:
+		e.Stmts = instrumentStmts(e.Stmts, hook)
+	case *ast.FuncCallExpr:
+		instrumentExprs([]ast.Expr{e.Func, e.Receiver}, hook)
+		instrumentExprs(e.Args, hook)
+	case *ast.AttrGetExpr:
+		instrumentExprs([]ast.Expr{e.Object, e.Key}, hook)
+	case *ast.TableExpr:
+		for _, field := range e.Fields {
+			instrumentExprs([]ast.Expr{field.Key, field.Value}, hook)
+		}
+	case *ast.LogicalOpExpr:
+		instrumentExprs([]ast.Expr{e.Lhs, e.Rhs}, hook)
+	case *ast.RelationalOpExpr:
+		instrumentExprs([]ast.Expr{e.Lhs, e.Rhs}, hook)
+	case *ast.StringConcatOpExpr:
+		instrumentExprs([]ast.Expr{e.Lhs, e.Rhs}, hook)
+	case *ast.ArithmeticOpExpr:
+		instrumentExprs([]ast.Expr{e.Lhs, e.Rhs}, hook)
+	case *ast.UnaryMinusOpExpr:
+		instrumentExpr(e.Expr, hook)
+	case *ast.UnaryNotOpExpr:
+		instrumentExpr(e.Expr, hook)
+	case *ast.UnaryLenOpExpr:
+		instrumentExpr(e.Expr, hook)
+	}
+}
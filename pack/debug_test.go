@@ -0,0 +1,280 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yockp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	yockr "github.com/ansurfen/yock/runtime"
+	lua "github.com/yuin/gopher-lua"
+)
+
+const debuggeeScript = `local total = 0
+local function add(n)
+    local doubled = n * 2
+    total = total + doubled
+    return total
+end
+add(1)
+add(2)
+result = total`
+
+func runDebuggee(t *testing.T, input string) (string, *lua.LState) {
+	s := yockr.NewYState()
+	out := &bytes.Buffer{}
+	yockr.NewDebugger(strings.NewReader(input), out).Attach(s)
+	yockp := New()
+	chunk := yockp.Instrument(yockp.ParseStr(debuggeeScript), yockr.DebugHookName)
+	proto, err := lua.Compile(chunk, "main.lua")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = yockr.LuaDoFunc(s.LState(), s.LState().NewFunctionFromProto(proto)); err != nil {
+		t.Fatal(err)
+	}
+	return out.String(), s.LState()
+}
+
+func TestDebuggerBreakpoint(t *testing.T) {
+	out, l := runDebuggee(t, strings.Join([]string{
+		"b 4", "c", "l", "u", "c", "p doubled = 100", "c",
+	}, "\n"))
+	for _, want := range []string{
+		"[thread 1] main.lua:1",
+		"breakpoint at main.lua:4",
+		"[thread 1] main.lua:4",
+		"n = 1",
+		"doubled = 2",
+		"total = 0",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("%q not found in\n%s", want, out)
+		}
+	}
+	// the second call of add is changed by eval
+	if result := l.GetGlobal("result"); result.String() != "102" {
+		t.Fatal("locals aren't written back", result)
+	}
+}
+
+func TestDebuggerStep(t *testing.T) {
+	out, _ := runDebuggee(t, strings.Join([]string{
+		"b 7", "c", "s", "n", "o", "n",
+	}, "\n"))
+	want := []string{"main.lua:1", "main.lua:7", "main.lua:3", "main.lua:4", "main.lua:8", "main.lua:9"}
+	got := []string{}
+	for _, line := range strings.Split(out, "\n") {
+		if i := strings.Index(line, "[thread 1] "); i >= 0 {
+			got = append(got, line[i+len("[thread 1] "):])
+		}
+	}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
+
+func TestDebuggerThread(t *testing.T) {
+	s := yockr.NewYState()
+	out := &bytes.Buffer{}
+	yockr.NewDebugger(strings.NewReader("b 3\nc\nl\nc\n"), out).Attach(s)
+	yockp := New()
+	chunk := yockp.Instrument(yockp.ParseStr(`local base = 10
+function add(n)
+    local sum = base + n
+    return sum
+end`), yockr.DebugHookName)
+	proto, err := lua.Compile(chunk, "main.lua")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = yockr.LuaDoFunc(s.LState(), s.LState().NewFunctionFromProto(proto)); err != nil {
+		t.Fatal(err)
+	}
+	// just like job and goroutine, add is called by a new state in another goroutine
+	th, cancel := s.LState().NewThread()
+	if cancel != nil {
+		defer cancel()
+	}
+	done := make(chan error)
+	go func() {
+		done <- th.CallByParam(lua.P{Fn: th.GetGlobal("add"), NRet: 1, Protect: true}, lua.LNumber(5))
+	}()
+	if err = <-done; err != nil {
+		t.Fatal(err)
+	}
+	if sum := th.Get(-1); sum.String() != "15" {
+		t.Fatal("invalid result", sum)
+	}
+	for _, want := range []string{"[thread 1] main.lua:1", "[thread 2] main.lua:3", "n = 5"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("%q not found in\n%s", want, out)
+		}
+	}
+}
+
+type dapClient struct {
+	t    *testing.T
+	conn net.Conn
+	seq  int
+	msgs chan map[string]any
+}
+
+func newDAPClient(t *testing.T, conn net.Conn) *dapClient {
+	c := &dapClient{t: t, conn: conn, msgs: make(chan map[string]any, 64)}
+	go func() {
+		defer close(c.msgs)
+		r := textproto.NewReader(bufio.NewReader(conn))
+		for {
+			header, err := r.ReadMIMEHeader()
+			if err != nil {
+				return
+			}
+			length, _ := strconv.Atoi(header.Get("Content-Length"))
+			raw := make([]byte, length)
+			if _, err = io.ReadFull(r.R, raw); err != nil {
+				return
+			}
+			msg := map[string]any{}
+			json.Unmarshal(raw, &msg)
+			c.msgs <- msg
+		}
+	}()
+	return c
+}
+
+// request sends command and returns the body of response
+func (c *dapClient) request(command string, args any) map[string]any {
+	c.seq++
+	raw, _ := json.Marshal(map[string]any{"seq": c.seq, "type": "request", "command": command, "arguments": args})
+	fmt.Fprintf(c.conn, "Content-Length: %d\r\n\r\n%s", len(raw), raw)
+	msg := c.wait("response", command)
+	if msg["success"] != true {
+		c.t.Fatalf("%s failed: %v", command, msg["message"])
+	}
+	body, _ := msg["body"].(map[string]any)
+	return body
+}
+
+// wait returns the next message of type and name, and others are skipped
+func (c *dapClient) wait(typ, name string) map[string]any {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case msg, ok := <-c.msgs:
+			if !ok {
+				c.t.Fatalf("connection closed while waiting for %s %s", typ, name)
+			}
+			if msg["type"] == typ && (msg["command"] == name || msg["event"] == name) {
+				return msg
+			}
+		case <-timeout:
+			c.t.Fatalf("timeout while waiting for %s %s", typ, name)
+		}
+	}
+}
+
+func TestDAPServer(t *testing.T) {
+	s := yockr.NewYState()
+	srv := yockr.NewDAPServer()
+	srv.Debugger().Attach(s)
+	srv.Debugger().SetThreadNamer(func(l *lua.LState) string {
+		if l == s.LState() {
+			return "main"
+		}
+		return ""
+	})
+	server, client := net.Pipe()
+	go srv.Serve(server)
+	c := newDAPClient(t, client)
+	defer client.Close()
+
+	c.request("initialize", map[string]any{"adapterID": "yock"})
+	c.wait("event", "initialized")
+	c.request("launch", map[string]any{"stopOnEntry": false})
+	c.request("setBreakpoints", map[string]any{
+		"source":      map[string]any{"path": "main.lua"},
+		"breakpoints": []map[string]any{{"line": 4}},
+	})
+	c.request("configurationDone", nil)
+	srv.WaitConfigured()
+
+	yockp := New()
+	proto, err := lua.Compile(yockp.Instrument(yockp.ParseStr(`env = { platform = { os = "linux" } }
+`+debuggeeScript), yockr.DebugHookName), "main.lua")
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error)
+	go func() {
+		done <- yockr.LuaDoFunc(s.LState(), s.LState().NewFunctionFromProto(proto))
+	}()
+
+	stopped := c.wait("event", "stopped")["body"].(map[string]any)
+	if stopped["reason"] != "breakpoint" {
+		t.Fatal("invalid reason", stopped["reason"])
+	}
+	thread := stopped["threadId"]
+	threads := c.request("threads", nil)["threads"].([]any)
+	if name := threads[0].(map[string]any)["name"]; len(threads) != 1 || name != "main" {
+		t.Fatal("invalid threads", threads)
+	}
+	frames := c.request("stackTrace", map[string]any{"threadId": thread})["stackFrames"].([]any)
+	top := frames[0].(map[string]any)
+	// the script is shifted by the line of env
+	if top["line"] != float64(4) || top["name"] != "add" {
+		t.Fatal("invalid frame", top)
+	}
+	scopes := c.request("scopes", map[string]any{"frameId": top["id"]})["scopes"].([]any)
+	vars := map[string]any{}
+	for _, scope := range scopes {
+		scope := scope.(map[string]any)
+		for _, v := range c.request("variables", map[string]any{"variablesReference": scope["variablesReference"]})["variables"].([]any) {
+			v := v.(map[string]any)
+			vars[scope["name"].(string)+"."+v["name"].(string)] = v
+		}
+	}
+	if vars["Locals.n"].(map[string]any)["value"] != "1" || vars["Upvalues.total"].(map[string]any)["value"] != "0" {
+		t.Fatal("invalid variables", vars)
+	}
+	platform := vars["Env.platform"].(map[string]any)
+	fields := c.request("variables", map[string]any{"variablesReference": platform["variablesReference"]})["variables"].([]any)
+	if v := fields[0].(map[string]any); v["name"] != "os" || v["value"] != `"linux"` {
+		t.Fatal("invalid env", fields)
+	}
+	if res := c.request("evaluate", map[string]any{"frameId": top["id"], "expression": "n * 10"}); res["result"] != "10" {
+		t.Fatal("invalid result", res)
+	}
+
+	c.request("next", map[string]any{"threadId": thread})
+	stopped = c.wait("event", "stopped")["body"].(map[string]any)
+	if stopped["reason"] != "step" {
+		t.Fatal("invalid reason", stopped["reason"])
+	}
+	frames = c.request("stackTrace", map[string]any{"threadId": thread})["stackFrames"].([]any)
+	if line := frames[0].(map[string]any)["line"]; line != float64(5) {
+		t.Fatal("invalid line", line)
+	}
+
+	c.request("setBreakpoints", map[string]any{"source": map[string]any{"path": "main.lua"}, "breakpoints": []any{}})
+	c.request("continue", map[string]any{"threadId": thread})
+	if err = <-done; err != nil {
+		t.Fatal(err)
+	}
+	srv.Terminate(0)
+	c.wait("event", "terminated")
+	if result := s.LState().GetGlobal("result"); result.String() != "6" {
+		t.Fatal("invalid result", result)
+	}
+}
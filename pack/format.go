@@ -0,0 +1,290 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yockp
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/gopher-lua/ast"
+)
+
+// Format reformats the source of lua script in the style of BuildScript.
+// Comments and single blank lines between statements are kept, and
+// formatting the result again makes no difference.
+func (yockpack *YockPack[T]) Format(source string) (string, error) {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	stmts, err := yockpack.Parse(strings.NewReader(source), "<string>")
+	if err != nil {
+		return "", err
+	}
+	p := &luaPrinter{buf: new(bytes.Buffer), src: scanLuaSource(source), fresh: true}
+	p.stmts(stmts, nil)
+	p.comments(math.MaxInt)
+	return p.buf.String(), nil
+}
+
+// FormatFile reads and formats file
+func (yockpack *YockPack[T]) FormatFile(file string) (string, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	return yockpack.Format(string(raw))
+}
+
+type luaComment struct {
+	line     int
+	lastLine int
+	text     string
+}
+
+type luaToken struct {
+	line int
+	str  string
+}
+
+// luaSource is what the AST of gopher-lua forgets about source,
+// and luaPrinter gets back the layout from it.
+type luaSource struct {
+	comments []luaComment
+	next     int
+	// literals are raw strings queued by line and value
+	literals map[luaToken][]string
+	// braces are the lines of '}' queued by the line of '{'
+	braces     map[int][]int
+	tables     map[*ast.TableExpr]int
+	elses      []int
+	elseifs    map[int]bool
+	localFuncs map[luaToken]bool
+}
+
+// comment returns the first comment not printed yet
+func (src *luaSource) comment() *luaComment {
+	if src == nil || src.next >= len(src.comments) {
+		return nil
+	}
+	return &src.comments[src.next]
+}
+
+// commented reports whether any comment isn't printed before line
+func (src *luaSource) commented(line int) bool {
+	c := src.comment()
+	return c != nil && c.line < line
+}
+
+func (src *luaSource) literal(line int, value string) (string, bool) {
+	if src == nil {
+		return "", false
+	}
+	key := luaToken{line, value}
+	if raws := src.literals[key]; len(raws) > 0 {
+		src.literals[key] = raws[1:]
+		return raws[0], true
+	}
+	return "", false
+}
+
+// closing returns the line of '}' of table
+func (src *luaSource) closing(table *ast.TableExpr) int {
+	if src == nil {
+		return table.Line()
+	}
+	if line, ok := src.tables[table]; ok {
+		return line
+	}
+	line := table.Line()
+	if lines := src.braces[table.Line()]; len(lines) > 0 {
+		line = lines[0]
+		src.braces[table.Line()] = lines[1:]
+	}
+	src.tables[table] = line
+	return line
+}
+
+// elseAfter returns the line of first else since line
+func (src *luaSource) elseAfter(line int) int {
+	idx := sort.SearchInts(src.elses, line)
+	if idx < len(src.elses) {
+		return src.elses[idx]
+	}
+	return line
+}
+
+// scanLuaSource tokenizes source roughly, which is enough to find out
+// comments, literals, braces and keywords.
+func scanLuaSource(source string) *luaSource {
+	src := &luaSource{
+		literals:   make(map[luaToken][]string),
+		braces:     make(map[int][]int),
+		tables:     make(map[*ast.TableExpr]int),
+		elseifs:    make(map[int]bool),
+		localFuncs: make(map[luaToken]bool),
+	}
+	type brace struct{ line, idx int }
+	opens := []brace{}
+	words := []luaToken{}
+	line := 1
+	for i := 0; i < len(source); {
+		ch := source[i]
+		switch {
+		case ch == '\n':
+			line++
+			i++
+			continue
+		case ch == ' ' || ch == '\t' || ch == '\r' || ch == '\f' || ch == '\v':
+			i++
+			continue
+		case strings.HasPrefix(source[i:], "--"):
+			end := len(source)
+			if level := longBracket(source[i+2:]); level >= 0 {
+				end = closeLongBracket(source, i+2, level)
+			} else if idx := strings.IndexByte(source[i:], '\n'); idx >= 0 {
+				end = i + idx
+			}
+			text := strings.TrimRight(source[i:end], " \t\r")
+			src.comments = append(src.comments, luaComment{line: line, lastLine: line + strings.Count(text, "\n"), text: text})
+			line += strings.Count(text, "\n")
+			i = end
+			continue
+		case ch == '"' || ch == '\'':
+			j := i + 1
+			for j < len(source) && source[j] != ch && source[j] != '\n' {
+				if source[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j < len(source) {
+				j++
+			}
+			src.queue(line, source[i:j])
+			line += strings.Count(source[i:j], "\n")
+			i = j
+		case ch == '[' && longBracket(source[i:]) >= 0:
+			j := closeLongBracket(source, i, longBracket(source[i:]))
+			src.queue(line, source[i:j])
+			line += strings.Count(source[i:j], "\n")
+			i = j
+		case ch == '{':
+			opens = append(opens, brace{line, len(src.braces[line])})
+			src.braces[line] = append(src.braces[line], line)
+			i++
+		case ch == '}':
+			if len(opens) > 0 {
+				open := opens[len(opens)-1]
+				opens = opens[:len(opens)-1]
+				src.braces[open.line][open.idx] = line
+			}
+			i++
+		case isIdentChar(ch):
+			j := i
+			for j < len(source) && (isIdentChar(source[j]) || (ch >= '0' && ch <= '9' && strings.IndexByte(".+-", source[j]) >= 0 &&
+				(source[j] == '.' || strings.IndexByte("eEpP", source[j-1]) >= 0))) {
+				j++
+			}
+			word := source[i:j]
+			switch word {
+			case "else":
+				src.elses = append(src.elses, line)
+			case "elseif":
+				src.elseifs[line] = true
+			}
+			if n := len(words); n >= 2 && words[n-2].str == "local" && words[n-1].str == "function" {
+				src.localFuncs[luaToken{words[n-2].line, word}] = true
+			}
+			words = append(words, luaToken{line, word})
+			i = j
+			continue
+		default:
+			i++
+		}
+		words = append(words, luaToken{line, ""})
+	}
+	return src
+}
+
+// queue records the raw string at line
+func (src *luaSource) queue(line int, raw string) {
+	key := luaToken{line, unquoteLua(raw)}
+	src.literals[key] = append(src.literals[key], raw)
+}
+
+// longBracket returns the level of long bracket opening str, or -1
+func longBracket(str string) int {
+	if !strings.HasPrefix(str, "[") {
+		return -1
+	}
+	level := 1
+	for level < len(str) && str[level] == '=' {
+		level++
+	}
+	if level < len(str) && str[level] == '[' {
+		return level - 1
+	}
+	return -1
+}
+
+// closeLongBracket returns the end of long bracket starting at i
+func closeLongBracket(source string, i, level int) int {
+	closing := "]" + strings.Repeat("=", level) + "]"
+	if idx := strings.Index(source[i+level+2:], closing); idx >= 0 {
+		return i + level + 2 + idx + len(closing)
+	}
+	return len(source)
+}
+
+// unquoteLua returns the value of lua string literal
+func unquoteLua(raw string) string {
+	if level := longBracket(raw); level >= 0 {
+		value := strings.TrimSuffix(raw[level+2:], "]"+strings.Repeat("=", level)+"]")
+		return strings.TrimPrefix(value, "\n")
+	}
+	if len(raw) < 2 {
+		return raw
+	}
+	raw = raw[1 : len(raw)-1]
+	buf := &strings.Builder{}
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '\\' || i+1 == len(raw) {
+			buf.WriteByte(raw[i])
+			continue
+		}
+		i++
+		switch ch := raw[i]; ch {
+		case 'a':
+			buf.WriteByte('\a')
+		case 'b':
+			buf.WriteByte('\b')
+		case 'f':
+			buf.WriteByte('\f')
+		case 'n':
+			buf.WriteByte('\n')
+		case 'r':
+			buf.WriteByte('\r')
+		case 't':
+			buf.WriteByte('\t')
+		case 'v':
+			buf.WriteByte('\v')
+		default:
+			if ch < '0' || ch > '9' {
+				buf.WriteByte(ch)
+				break
+			}
+			j := i
+			for j < len(raw) && j < i+3 && raw[j] >= '0' && raw[j] <= '9' {
+				j++
+			}
+			code, _ := strconv.Atoi(raw[i:j])
+			buf.WriteByte(byte(code))
+			i = j - 1
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,550 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yockp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/yuin/gopher-lua/ast"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+// LintRule is the kind of mistake checked by linter
+type LintRule struct {
+	ID          string
+	Description string
+	// Severity is either error or warning
+	Severity string
+}
+
+var lintRules = []LintRule{
+	{"syntax", "script can't be parsed", "error"},
+	{"duplicate-job", "job or jobs declares a task whose name is already declared", "error"},
+	{"undefined-job", "jobs or needs references a task which isn't declared", "error"},
+	{"unnotified-signal", "wait on a signal which is never notified", "warning"},
+	{"unused-flag", "entry of option.flags is never used by job", "warning"},
+	{"shadowed-global", "variable shadows the global of yock stdlib", "warning"},
+}
+
+// LintRules returns the rules checked by linter
+func LintRules() []LintRule {
+	return lintRules
+}
+
+func lintSeverity(rule string) string {
+	for _, r := range lintRules {
+		if r.ID == rule {
+			return r.Severity
+		}
+	}
+	return "warning"
+}
+
+// LintIssue is a mistake found in script
+type LintIssue struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+}
+
+// lintRef is the reference to task or signal by name
+type lintRef struct {
+	name string
+	line int
+	// by is the task which references the name
+	by string
+	// ahead indicates whether the name is referenced before its declaration
+	ahead bool
+}
+
+// lintFrame collects the declarations and references while visiting script
+type lintFrame struct {
+	doc    *luaDocument
+	file   string
+	issues []LintIssue
+
+	// jobs maps the task declared by job or jobs to its line
+	jobs    map[string]int
+	members []lintRef
+	needs   []lintRef
+
+	waits    []lintRef
+	notified map[string]bool
+	// dynamicNotify is set when the signal of notify isn't literal,
+	// and unnotified signals aren't reported since then.
+	dynamicNotify bool
+
+	// flags are the entries of option.flags, the by of ref is task and name is key
+	flags    []lintRef
+	flagKeys map[string]bool
+	// flagsRefs and flagsIndexed count the references of flags field and the ones
+	// indexed by literal, and unused flags are reported only when they're equal.
+	flagsRefs    int
+	flagsIndexed int
+}
+
+func (frame *lintFrame) report(rule string, line int, word, format string, a ...any) {
+	col := frame.doc.column(line, word) + 1
+	if col <= 0 {
+		col = 1
+	}
+	frame.issues = append(frame.issues, LintIssue{
+		Rule:     rule,
+		Severity: lintSeverity(rule),
+		Message:  fmt.Sprintf(format, a...),
+		File:     frame.file,
+		Line:     line,
+		Column:   col,
+	})
+}
+
+func (frame *lintFrame) declareJob(name string, line int) {
+	if first, ok := frame.jobs[name]; ok {
+		frame.report("duplicate-job", line, name, "duplicate job name %s, first declared at line %d", name, first)
+		return
+	}
+	frame.jobs[name] = line
+}
+
+// Linter checks the yock-specific mistakes of script, which are
+// otherwise only caught at runtime or never.
+type Linter struct {
+	yockp      YockPack[*lintFrame]
+	ann        *Annotations
+	stmtHandle VisitStmtHandle[*lintFrame]
+	exprHandle VisitExprHandle[*lintFrame]
+}
+
+// NewLinter returns a linter which learns about the globals of yock stdlib from annotations
+func NewLinter(ann *Annotations) *Linter {
+	linter := &Linter{ann: ann}
+	linter.stmtHandle = VisitStmtHandle[*lintFrame]{
+		StmtAssign: func(idx int, stmt yockStmt, frame *lintFrame) {
+			s := stmt.(AssignStmt)
+			linter.visitExprs(s.Rhs, frame)
+			for _, lhs := range s.Lhs {
+				if ident, ok := lhs.(IdentExpr); ok {
+					linter.checkShadow(ident.Value, s.Line(), "assignment", frame)
+				} else {
+					linter.visitExprs([]ast.Expr{lhs}, frame)
+				}
+			}
+		},
+		StmtLocalAssign: func(idx int, stmt yockStmt, frame *lintFrame) {
+			s := stmt.(LocalAssignStmt)
+			for _, name := range s.Names {
+				linter.checkShadow(name, s.Line(), "local", frame)
+			}
+			linter.visitExprs(s.Exprs, frame)
+		},
+		StmtFuncCall: func(idx int, stmt yockStmt, frame *lintFrame) {
+			linter.visitExprs([]ast.Expr{stmt.(FuncCallStmt).Expr}, frame)
+		},
+		StmtDoBlock: func(idx int, stmt yockStmt, frame *lintFrame) {
+			linter.yockp.VisitStmt(stmt.(DoBlockStmt).Stmts, frame, linter.stmtHandle)
+		},
+		StmtWhile: func(idx int, stmt yockStmt, frame *lintFrame) {
+			s := stmt.(WhileStmt)
+			linter.visitExprs([]ast.Expr{s.Condition}, frame)
+			linter.yockp.VisitStmt(s.Stmts, frame, linter.stmtHandle)
+		},
+		StmtRepeat: func(idx int, stmt yockStmt, frame *lintFrame) {
+			s := stmt.(RepeatStmt)
+			linter.yockp.VisitStmt(s.Stmts, frame, linter.stmtHandle)
+			linter.visitExprs([]ast.Expr{s.Condition}, frame)
+		},
+		StmtIf: func(idx int, stmt yockStmt, frame *lintFrame) {
+			s := stmt.(IfStmt)
+			linter.visitExprs([]ast.Expr{s.Condition}, frame)
+			linter.yockp.VisitStmt(s.Then, frame, linter.stmtHandle)
+			linter.yockp.VisitStmt(s.Else, frame, linter.stmtHandle)
+		},
+		StmtNumbderFor: func(idx int, stmt yockStmt, frame *lintFrame) {
+			s := stmt.(NumberForStmt)
+			linter.checkShadow(s.Name, s.Line(), "loop variable", frame)
+			linter.visitExprs([]ast.Expr{s.Init, s.Limit, s.Step}, frame)
+			linter.yockp.VisitStmt(s.Stmts, frame, linter.stmtHandle)
+		},
+		StmtGenericFor: func(idx int, stmt yockStmt, frame *lintFrame) {
+			s := stmt.(GenericForStmt)
+			for _, name := range s.Names {
+				linter.checkShadow(name, s.Line(), "loop variable", frame)
+			}
+			linter.visitExprs(s.Exprs, frame)
+			linter.yockp.VisitStmt(s.Stmts, frame, linter.stmtHandle)
+		},
+		StmtFuncDef: func(idx int, stmt yockStmt, frame *lintFrame) {
+			s := stmt.(FuncDefStmt)
+			if ident, ok := s.Name.Func.(IdentExpr); ok {
+				linter.checkShadow(ident.Value, s.Line(), "function", frame)
+			} else {
+				linter.visitExprs([]ast.Expr{s.Name.Func, s.Name.Receiver}, frame)
+			}
+			linter.visitExprs([]ast.Expr{s.Func}, frame)
+		},
+		StmtReturn: func(idx int, stmt yockStmt, frame *lintFrame) {
+			linter.visitExprs(stmt.(ReturnStmt).Exprs, frame)
+		},
+	}
+	binary := func(idx int, expr yockExpr, frame *lintFrame) {
+		switch e := expr.(type) {
+		case LogicalOpExpr:
+			linter.visitExprs([]ast.Expr{e.Lhs, e.Rhs}, frame)
+		case RelationalOpExpr:
+			linter.visitExprs([]ast.Expr{e.Lhs, e.Rhs}, frame)
+		case StringConcatOpExpr:
+			linter.visitExprs([]ast.Expr{e.Lhs, e.Rhs}, frame)
+		case ArithmeticOpExpr:
+			linter.visitExprs([]ast.Expr{e.Lhs, e.Rhs}, frame)
+		case UnaryMinusOpExpr:
+			linter.visitExprs([]ast.Expr{e.Expr}, frame)
+		case UnaryNotOpExpr:
+			linter.visitExprs([]ast.Expr{e.Expr}, frame)
+		case UnaryLenOpExpr:
+			linter.visitExprs([]ast.Expr{e.Expr}, frame)
+		}
+	}
+	linter.exprHandle = VisitExprHandle[*lintFrame]{
+		ExprAttrGet: func(idx int, expr yockExpr, frame *lintFrame) {
+			e := expr.(AttrGetExpr)
+			linter.checkFlags(e, frame)
+			linter.visitExprs([]ast.Expr{e.Object, e.Key}, frame)
+		},
+		ExprTable: func(idx int, expr yockExpr, frame *lintFrame) {
+			for _, field := range expr.(TableExpr).Fields {
+				linter.visitExprs([]ast.Expr{field.Key, field.Value}, frame)
+			}
+		},
+		ExprFuncCall: func(idx int, expr yockExpr, frame *lintFrame) {
+			e := expr.(FuncCallExpr)
+			linter.checkCall(e, frame)
+			linter.visitExprs([]ast.Expr{e.Func, e.Receiver}, frame)
+			linter.visitExprs(e.Args, frame)
+		},
+		ExprFunciton: func(idx int, expr yockExpr, frame *lintFrame) {
+			e := expr.(FunctionExpr)
+			for _, name := range e.ParList.Names {
+				linter.checkShadow(name, e.Line(), "parameter", frame)
+			}
+			linter.yockp.VisitStmt(e.Stmts, frame, linter.stmtHandle)
+		},
+		ExprLogicalOp:      binary,
+		ExprRelationalOp:   binary,
+		ExprStringConcatOp: binary,
+		ExprArithmeticOp:   binary,
+		ExprUnaryMinus:     binary,
+		ExprUnaryNotOp:     binary,
+		ExprUnaryLenOp:     binary,
+	}
+	return linter
+}
+
+func (linter *Linter) visitExprs(exprs []ast.Expr, frame *lintFrame) {
+	for _, expr := range exprs {
+		if expr != nil {
+			linter.yockp.VisitExpr([]ast.Expr{expr}, frame, linter.exprHandle)
+		}
+	}
+}
+
+// LintFile checks the script of file
+func (linter *Linter) LintFile(file string) ([]LintIssue, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return linter.Lint(file, string(raw)), nil
+}
+
+// Lint checks source, and the issues are in order of line
+func (linter *Linter) Lint(file, source string) []LintIssue {
+	frame := &lintFrame{
+		doc:      &luaDocument{lines: strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")},
+		file:     file,
+		jobs:     make(map[string]int),
+		notified: make(map[string]bool),
+		flagKeys: make(map[string]bool),
+	}
+	stmts, err := linter.yockp.Parse(strings.NewReader(source), file)
+	if err != nil {
+		issue := LintIssue{Rule: "syntax", Severity: lintSeverity("syntax"), Message: err.Error(), File: file, Line: 1, Column: 1}
+		perr := &parse.Error{}
+		if errors.As(err, &perr) {
+			issue.Message = strings.TrimSpace(perr.Message)
+			issue.Line, issue.Column = perr.Pos.Line, perr.Pos.Column
+		}
+		return []LintIssue{issue}
+	}
+	linter.yockp.VisitStmt(stmts, frame, linter.stmtHandle)
+
+	for _, ref := range frame.needs {
+		if _, ok := frame.jobs[ref.name]; !ok {
+			frame.report("undefined-job", ref.line, ref.name, "job %s needs undefined job %s", ref.by, ref.name)
+		}
+	}
+	for _, ref := range frame.members {
+		if _, ok := frame.jobs[ref.name]; !ok {
+			frame.report("undefined-job", ref.line, ref.name, "jobs %s references undefined job %s", ref.by, ref.name)
+		} else if ref.ahead {
+			// jobs only takes the jobs declared before it
+			frame.report("undefined-job", ref.line, ref.name, "jobs %s references job %s before it's declared", ref.by, ref.name)
+		}
+	}
+	if !frame.dynamicNotify {
+		for _, ref := range frame.waits {
+			if !frame.notified[ref.name] {
+				frame.report("unnotified-signal", ref.line, ref.name, "wait on signal %s which is never notified", ref.name)
+			}
+		}
+	}
+	for _, ref := range frame.flags {
+		if _, ok := frame.jobs[ref.by]; !ok {
+			frame.report("unused-flag", ref.line, ref.by, "flags of undefined job %s", ref.by)
+		} else if len(ref.name) > 0 && frame.flagsRefs == frame.flagsIndexed && !frame.flagKeys[ref.name] {
+			frame.report("unused-flag", ref.line, ref.name, "flag %s of job %s is never used", ref.name, ref.by)
+		}
+	}
+	sort.SliceStable(frame.issues, func(i, j int) bool {
+		if frame.issues[i].Line != frame.issues[j].Line {
+			return frame.issues[i].Line < frame.issues[j].Line
+		}
+		return frame.issues[i].Column < frame.issues[j].Column
+	})
+	return frame.issues
+}
+
+// checkShadow reports the variable named after the function of stdlib, such as cp or rm.
+// The global assignment also reports the table of stdlib, such as env, which is overridden.
+func (linter *Linter) checkShadow(name string, line int, what string, frame *lintFrame) {
+	if linter.ann == nil || luaBuiltins[name] {
+		return
+	}
+	for _, sym := range linter.ann.Lookup(name) {
+		if sym.Kind == SymbolFunction || (sym.Kind == SymbolTable && what == "assignment") {
+			switch what {
+			case "assignment":
+				frame.report("shadowed-global", line, name, "assignment to %s overrides the global of yock stdlib", name)
+			case "function":
+				frame.report("shadowed-global", line, name, "function %s overrides the global of yock stdlib", name)
+			default:
+				frame.report("shadowed-global", line, name, "%s %s shadows the global of yock stdlib", what, name)
+			}
+			return
+		}
+	}
+}
+
+// checkCall collects the declarations and references of task and signal
+func (linter *Linter) checkCall(call FuncCallExpr, frame *lintFrame) {
+	ident, ok := call.Func.(IdentExpr)
+	if !ok {
+		return
+	}
+	line := call.Line()
+	switch ident.Value {
+	case "job":
+		name, ok := literal(call.Args, 0)
+		if !ok {
+			return
+		}
+		frame.declareJob(name, line)
+		if len(call.Args) < 3 {
+			return
+		}
+		if opt, ok := call.Args[2].(TableExpr); ok {
+			for _, need := range literals(tableField(opt, "needs")) {
+				frame.needs = append(frame.needs, lintRef{name: need.Value, line: need.Line(), by: name})
+			}
+		}
+	case "jobs":
+		name, ok := literal(call.Args, 0)
+		if !ok {
+			return
+		}
+		for i := 1; i < len(call.Args); i++ {
+			if member, ok := literal(call.Args, i); ok {
+				_, declared := frame.jobs[member]
+				frame.members = append(frame.members, lintRef{name: member, line: call.Args[i].Line(), by: name, ahead: !declared})
+			}
+		}
+		frame.declareJob(name, line)
+	case "wait", "waits":
+		for i := range call.Args {
+			if sig, ok := literal(call.Args, i); ok {
+				frame.waits = append(frame.waits, lintRef{name: sig, line: call.Args[i].Line()})
+			}
+		}
+	case "notify":
+		if sig, ok := literal(call.Args, 0); ok {
+			frame.notified[sig] = true
+		} else {
+			frame.dynamicNotify = true
+		}
+	case "option":
+		if len(call.Args) == 0 {
+			return
+		}
+		opt, ok := call.Args[0].(TableExpr)
+		if !ok {
+			return
+		}
+		flags, ok := tableField(opt, "flags").(TableExpr)
+		if !ok {
+			return
+		}
+		for _, task := range flags.Fields {
+			name, ok := task.Key.(StringExpr)
+			if !ok {
+				continue
+			}
+			entries, ok := task.Value.(TableExpr)
+			if !ok {
+				continue
+			}
+			for _, entry := range entries.Fields {
+				if key, ok := entry.Key.(StringExpr); ok {
+					frame.flags = append(frame.flags, lintRef{name: key.Value, line: entry.Value.Line(), by: name.Value})
+				}
+			}
+			if len(entries.Fields) == 0 {
+				frame.flags = append(frame.flags, lintRef{line: task.Value.Line(), by: name.Value})
+			}
+		}
+	}
+}
+
+// checkFlags collects the keys of flags indexed by literal, such as ctx.flags.port
+func (linter *Linter) checkFlags(e AttrGetExpr, frame *lintFrame) {
+	if key, ok := e.Key.(StringExpr); ok && key.Value == "flags" {
+		frame.flagsRefs++
+		// env.flags is indexed by task, so that the keys can't be checked
+		if ident, ok := e.Object.(IdentExpr); ok && ident.Value == "env" {
+			frame.flagsRefs++
+		}
+	}
+	object, ok := e.Object.(AttrGetExpr)
+	if !ok {
+		return
+	}
+	if key, ok := object.Key.(StringExpr); ok && key.Value == "flags" {
+		if k, ok := e.Key.(StringExpr); ok {
+			frame.flagsIndexed++
+			frame.flagKeys[k.Value] = true
+		}
+	}
+}
+
+// literal returns the string literal at idx of args
+func literal(args []ast.Expr, idx int) (string, bool) {
+	if idx >= len(args) {
+		return "", false
+	}
+	if s, ok := args[idx].(StringExpr); ok {
+		return s.Value, true
+	}
+	return "", false
+}
+
+// literals returns the string literals of expression, which is either string or array of string
+func literals(expr ast.Expr) []StringExpr {
+	switch e := expr.(type) {
+	case StringExpr:
+		return []StringExpr{e}
+	case TableExpr:
+		res := []StringExpr{}
+		for _, field := range e.Fields {
+			if s, ok := field.Value.(StringExpr); ok && field.Key == nil {
+				res = append(res, s)
+			}
+		}
+		return res
+	}
+	return nil
+}
+
+// tableField returns the value of field named key in table constructor
+func tableField(tbl TableExpr, key string) ast.Expr {
+	for _, field := range tbl.Fields {
+		if k, ok := field.Key.(StringExpr); ok && k.Value == key {
+			return field.Value
+		}
+	}
+	return nil
+}
+
+// WriteLint writes issues in format, which is text, json or sarif
+func WriteLint(w io.Writer, format string, issues []LintIssue) error {
+	switch format {
+	case "", "text":
+		for _, issue := range issues {
+			if _, err := fmt.Fprintf(w, "%s:%d:%d: %s: %s [%s]\n",
+				issue.File, issue.Line, issue.Column, issue.Severity, issue.Message, issue.Rule); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if issues == nil {
+			issues = []LintIssue{}
+		}
+		return enc.Encode(issues)
+	case "sarif":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(sarifLog(issues))
+	}
+	return fmt.Errorf("invalid format %s", format)
+}
+
+// sarifLog converts issues into SARIF 2.1.0, which is supported by code scanning of CI
+func sarifLog(issues []LintIssue) map[string]any {
+	rules := []map[string]any{}
+	for _, rule := range lintRules {
+		rules = append(rules, map[string]any{
+			"id":                   rule.ID,
+			"shortDescription":     map[string]any{"text": rule.Description},
+			"defaultConfiguration": map[string]any{"level": rule.Severity},
+		})
+	}
+	results := []map[string]any{}
+	for _, issue := range issues {
+		results = append(results, map[string]any{
+			"ruleId":  issue.Rule,
+			"level":   issue.Severity,
+			"message": map[string]any{"text": issue.Message},
+			"locations": []map[string]any{{
+				"physicalLocation": map[string]any{
+					"artifactLocation": map[string]any{"uri": strings.ReplaceAll(issue.File, "\\", "/")},
+					"region":           map[string]any{"startLine": issue.Line, "startColumn": issue.Column},
+				},
+			}},
+		})
+	}
+	return map[string]any{
+		"version": "2.1.0",
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"runs": []map[string]any{{
+			"tool": map[string]any{
+				"driver": map[string]any{
+					"name":           "yock lint",
+					"informationUri": "https://github.com/ansurfen/yock",
+					"rules":          rules,
+				},
+			},
+			"results": results,
+		}},
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yockp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+const lintScript = `option({
+    flags = {
+        build = { port = 8080, verbose = true },
+        release = { tag = "v1" },
+    },
+})
+
+job("build", function(ctx)
+    local cp = ctx.flags.port
+    wait("ready")
+    wait("done")
+end)
+
+job("test", function(ctx)
+    notify("ready")
+end, { needs = { "build", "lint" } })
+
+jobs("all", "build", "deploy", "test")
+job("deploy", function(ctx) end)
+job("build", function(ctx) end)`
+
+func TestLint(t *testing.T) {
+	issues := NewLinter(loadAnnotations(t)).Lint("main.lua", lintScript)
+	want := []string{
+		"3:32 unused-flag flag verbose of job build is never used",
+		"4:9 unused-flag flags of undefined job release",
+		"9:11 shadowed-global local cp shadows the global of yock stdlib",
+		"11:11 unnotified-signal wait on signal done which is never notified",
+		"16:28 undefined-job job test needs undefined job lint",
+		"18:23 undefined-job jobs all references job deploy before it's declared",
+		"20:6 duplicate-job duplicate job name build, first declared at line 8",
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("want %d issues, got %v", len(want), issues)
+	}
+	for i, issue := range issues {
+		if got := fmt.Sprintf("%d:%d %s %s", issue.Line, issue.Column, issue.Rule, issue.Message); got != want[i] {
+			t.Fatalf("want %s, got %s", want[i], got)
+		}
+	}
+}
+
+func TestWriteLint(t *testing.T) {
+	issues := NewLinter(nil).Lint("main.lua", "job(")
+	if len(issues) != 1 || issues[0].Rule != "syntax" {
+		t.Fatal("invalid syntax issue", issues)
+	}
+	out := &bytes.Buffer{}
+	if err := WriteLint(out, "sarif", issues); err != nil {
+		t.Fatal(err)
+	}
+	sarif := struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []struct {
+				RuleID string `json:"ruleId"`
+				Level  string `json:"level"`
+			} `json:"results"`
+		} `json:"runs"`
+	}{}
+	if err := json.Unmarshal(out.Bytes(), &sarif); err != nil {
+		t.Fatal(err)
+	}
+	if sarif.Version != "2.1.0" || sarif.Runs[0].Results[0].RuleID != "syntax" || sarif.Runs[0].Results[0].Level != "error" {
+		t.Fatal("invalid sarif", out.String())
+	}
+}
@@ -0,0 +1,437 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yockp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LanguageServer speaks Language Server Protocol for yock scripts. It provides
+// completion, hover, go-to-definition and diagnostics of undefined globals,
+// which learns about yock stdlib (job, env, option, etc.) from annotations.
+type LanguageServer struct {
+	yockp YockPack[NilFrame]
+	ann   *Annotations
+	// docs maps the uri of opened document to its analysis
+	docs map[string]*luaDocument
+	w    io.Writer
+}
+
+func NewLanguageServer(ann *Annotations) *LanguageServer {
+	return &LanguageServer{
+		yockp: New(),
+		ann:   ann,
+		docs:  make(map[string]*luaDocument),
+	}
+}
+
+type lspMessage struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+type lspResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result"`
+	Error   *lspError       `json:"error,omitempty"`
+}
+
+type lspError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type lspNotification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+// the error codes defined by JSON-RPC
+const (
+	lspMethodNotFound = -32601
+	lspInvalidParams  = -32602
+)
+
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+type lspLocation struct {
+	URI   string   `json:"uri"`
+	Range lspRange `json:"range"`
+}
+
+type lspParams struct {
+	TextDocument struct {
+		URI  string `json:"uri"`
+		Text string `json:"text"`
+	} `json:"textDocument"`
+	ContentChanges []struct {
+		Text string `json:"text"`
+	} `json:"contentChanges"`
+	Position lspPosition `json:"position"`
+}
+
+// Serve handles the messages from r until client exits or r is closed
+func (ls *LanguageServer) Serve(r io.Reader, w io.Writer) error {
+	ls.w = w
+	reader := textproto.NewReader(bufio.NewReader(r))
+	for {
+		header, err := reader.ReadMIMEHeader()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		length, err := strconv.Atoi(header.Get("Content-Length"))
+		if err != nil {
+			return fmt.Errorf("invalid Content-Length: %w", err)
+		}
+		raw := make([]byte, length)
+		if _, err = io.ReadFull(reader.R, raw); err != nil {
+			return err
+		}
+		msg := lspMessage{}
+		if err = json.Unmarshal(raw, &msg); err != nil {
+			return err
+		}
+		if msg.Method == "exit" {
+			return nil
+		}
+		result, rerr := ls.handle(msg)
+		// notification has no id, and needs no response
+		if len(msg.ID) == 0 {
+			continue
+		}
+		if err = ls.send(lspResponse{JSONRPC: "2.0", ID: msg.ID, Result: result, Error: rerr}); err != nil {
+			return err
+		}
+	}
+}
+
+func (ls *LanguageServer) send(msg any) error {
+	raw, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(ls.w, "Content-Length: %d\r\n\r\n%s", len(raw), raw)
+	return err
+}
+
+func (ls *LanguageServer) handle(msg lspMessage) (any, *lspError) {
+	params := lspParams{}
+	if len(msg.Params) > 0 {
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, &lspError{Code: lspInvalidParams, Message: err.Error()}
+		}
+	}
+	uri := params.TextDocument.URI
+	switch msg.Method {
+	case "initialize":
+		return map[string]any{
+			"capabilities": map[string]any{
+				// the whole document is synchronized on change
+				"textDocumentSync":   1,
+				"completionProvider": map[string]any{"triggerCharacters": []string{".", ":"}},
+				"hoverProvider":      true,
+				"definitionProvider": true,
+			},
+			"serverInfo": map[string]any{"name": "yock"},
+		}, nil
+	case "initialized", "$/cancelRequest", "$/setTrace", "workspace/didChangeConfiguration":
+		return nil, nil
+	case "shutdown":
+		return nil, nil
+	case "textDocument/didOpen":
+		ls.update(uri, params.TextDocument.Text)
+		return nil, nil
+	case "textDocument/didChange":
+		if n := len(params.ContentChanges); n > 0 {
+			ls.update(uri, params.ContentChanges[n-1].Text)
+		}
+		return nil, nil
+	case "textDocument/didClose":
+		delete(ls.docs, uri)
+		ls.send(lspNotification{
+			JSONRPC: "2.0",
+			Method:  "textDocument/publishDiagnostics",
+			Params:  map[string]any{"uri": uri, "diagnostics": []any{}},
+		})
+		return nil, nil
+	case "textDocument/completion":
+		return ls.completion(uri, params.Position), nil
+	case "textDocument/hover":
+		return ls.hover(uri, params.Position), nil
+	case "textDocument/definition":
+		return ls.definition(uri, params.Position), nil
+	}
+	return nil, &lspError{Code: lspMethodNotFound, Message: fmt.Sprintf("method %s not found", msg.Method)}
+}
+
+// update analyzes the new content of document and publishes diagnostics.
+// The scopes of last analysis are kept when the content can't be parsed,
+// since the script is mostly incomplete while typing.
+func (ls *LanguageServer) update(uri, text string) {
+	doc := analyzeDocument(ls.yockp, ls.ann, uriToPath(uri), text)
+	diagnostics := []map[string]any{}
+	if doc.err != nil {
+		if last, ok := ls.docs[uri]; ok {
+			doc.root, doc.globals = last.root, last.globals
+		}
+		line, col := doc.err.Pos.Line-1, doc.err.Pos.Column-1
+		if line < 0 {
+			line = 0
+		}
+		if col < 0 {
+			col = 0
+		}
+		diagnostics = append(diagnostics, map[string]any{
+			"range":    lspRange{lspPosition{line, col}, lspPosition{line, col + 1}},
+			"severity": 1,
+			"source":   "yock",
+			"message":  strings.TrimSpace(doc.err.Message),
+		})
+	} else {
+		reported := make(map[luaRef]bool)
+		for _, ref := range doc.undefined(ls.ann) {
+			if reported[ref] {
+				continue
+			}
+			reported[ref] = true
+			diagnostics = append(diagnostics, map[string]any{
+				"range":    doc.rangeOf(ref.line, ref.name),
+				"severity": 2,
+				"source":   "yock",
+				"message":  fmt.Sprintf("undefined global %s", ref.name),
+			})
+		}
+	}
+	ls.docs[uri] = doc
+	ls.send(lspNotification{
+		JSONRPC: "2.0",
+		Method:  "textDocument/publishDiagnostics",
+		Params:  map[string]any{"uri": uri, "diagnostics": diagnostics},
+	})
+}
+
+// rangeOf returns the range of word in line (starting from 1), or the whole line if not found
+func (doc *luaDocument) rangeOf(line int, word string) lspRange {
+	col := doc.column(line, word)
+	if col < 0 {
+		end := 0
+		if line > 0 && line <= len(doc.lines) {
+			end = len(doc.lines[line-1])
+		}
+		return lspRange{lspPosition{line - 1, 0}, lspPosition{line - 1, end}}
+	}
+	return lspRange{lspPosition{line - 1, col}, lspPosition{line - 1, col + len(word)}}
+}
+
+// the kinds of completion item defined by LSP
+const (
+	lspCompletionFunction = 3
+	lspCompletionField    = 5
+	lspCompletionVariable = 6
+	lspCompletionModule   = 9
+	lspCompletionKeyword  = 14
+)
+
+var luaKeywords = []string{
+	"and", "break", "do", "else", "elseif", "end", "false", "for", "function", "goto",
+	"if", "in", "local", "nil", "not", "or", "repeat", "return", "then", "true", "until", "while",
+}
+
+func completionItem(sym *LuaSymbol) map[string]any {
+	item := map[string]any{
+		"label":         sym.Label(),
+		"documentation": map[string]any{"kind": "markdown", "value": sym.Markdown()},
+	}
+	switch sym.Kind {
+	case SymbolFunction:
+		item["kind"] = lspCompletionFunction
+		item["detail"] = sym.Signature
+	case SymbolTable:
+		item["kind"] = lspCompletionModule
+		item["detail"] = sym.Type
+	case SymbolField:
+		item["kind"] = lspCompletionField
+		item["detail"] = sym.Type
+	}
+	return item
+}
+
+func (ls *LanguageServer) completion(uri string, pos lspPosition) map[string]any {
+	items := []map[string]any{}
+	doc, ok := ls.docs[uri]
+	if !ok {
+		return map[string]any{"isIncomplete": false, "items": items}
+	}
+	line := pos.Line + 1
+	names, seps := splitPath(doc.pathBefore(pos.Line, pos.Character))
+	if len(names) > 1 {
+		typ := doc.resolve(ls.ann, names[:len(names)-1], seps, line)
+		if len(typ) > 0 {
+			for _, sym := range ls.ann.Members(typ, seps[len(seps)-1]) {
+				items = append(items, completionItem(sym))
+			}
+		}
+		return map[string]any{"isIncomplete": false, "items": items}
+	}
+	seen := make(map[string]bool)
+	if doc.root != nil {
+		for _, v := range doc.root.visible(line) {
+			seen[v.name] = true
+			items = append(items, map[string]any{"label": v.name, "kind": lspCompletionVariable, "detail": "local " + v.name})
+		}
+		globals := []string{}
+		for name := range doc.globals {
+			globals = append(globals, name)
+		}
+		sort.Strings(globals)
+		for _, name := range globals {
+			if !seen[name] {
+				seen[name] = true
+				items = append(items, map[string]any{"label": name, "kind": lspCompletionVariable, "detail": "global " + name})
+			}
+		}
+	}
+	for _, sym := range ls.ann.Globals() {
+		if !seen[sym.Name] {
+			seen[sym.Name] = true
+			items = append(items, completionItem(sym))
+		}
+	}
+	for _, kw := range luaKeywords {
+		items = append(items, map[string]any{"label": kw, "kind": lspCompletionKeyword})
+	}
+	return map[string]any{"isIncomplete": false, "items": items}
+}
+
+// symbols returns the declarations in annotations of path at line
+func (ls *LanguageServer) symbols(doc *luaDocument, path string, line int) []*LuaSymbol {
+	names, seps := splitPath(path)
+	if len(names) == 1 {
+		if doc.root != nil && doc.root.at(line).lookup(names[0], line) != nil {
+			return nil
+		}
+		if _, ok := doc.globals[names[0]]; ok {
+			return nil
+		}
+		return ls.ann.Lookup(names[0])
+	}
+	typ := doc.resolve(ls.ann, names[:len(names)-1], seps, line)
+	if len(typ) == 0 {
+		return nil
+	}
+	return ls.ann.Member(typ, seps[len(seps)-1], names[len(names)-1])
+}
+
+func (ls *LanguageServer) hover(uri string, pos lspPosition) any {
+	doc, ok := ls.docs[uri]
+	if !ok {
+		return nil
+	}
+	line := pos.Line + 1
+	path := doc.pathAt(pos.Line, pos.Character)
+	if len(path) == 0 {
+		return nil
+	}
+	contents := []string{}
+	if !strings.ContainsAny(path, ".:") && doc.root != nil {
+		if v := doc.root.at(line).lookup(path, line); v != nil {
+			decl := "local " + v.name
+			if len(v.typ) > 0 {
+				decl += ": " + v.typ
+			}
+			contents = append(contents, fmt.Sprintf("```lua\n%s\n```", decl))
+		}
+	}
+	if len(contents) == 0 {
+		for _, sym := range ls.symbols(doc, path, line) {
+			contents = append(contents, sym.Markdown())
+		}
+	}
+	if len(contents) == 0 {
+		return nil
+	}
+	return map[string]any{
+		"contents": map[string]any{"kind": "markdown", "value": strings.Join(contents, "\n---\n")},
+	}
+}
+
+func (ls *LanguageServer) definition(uri string, pos lspPosition) any {
+	doc, ok := ls.docs[uri]
+	if !ok {
+		return nil
+	}
+	line := pos.Line + 1
+	path := doc.pathAt(pos.Line, pos.Character)
+	if len(path) == 0 {
+		return nil
+	}
+	names, _ := splitPath(path)
+	if len(names) == 1 {
+		if doc.root != nil {
+			if v := doc.root.at(line).lookup(names[0], line); v != nil {
+				return lspLocation{URI: uri, Range: doc.rangeOf(v.line, v.name)}
+			}
+		}
+		if def, ok := doc.globals[names[0]]; ok {
+			return lspLocation{URI: uri, Range: doc.rangeOf(def, names[0])}
+		}
+	}
+	locs := []lspLocation{}
+	for _, sym := range ls.symbols(doc, path, line) {
+		start := lspPosition{Line: sym.Line - 1}
+		locs = append(locs, lspLocation{URI: pathToURI(sym.File), Range: lspRange{start, start}})
+	}
+	if len(locs) == 0 {
+		return nil
+	}
+	return locs
+}
+
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	path := u.Path
+	// file:///c:/path on windows
+	if len(path) > 2 && path[0] == '/' && path[2] == ':' {
+		path = path[1:]
+	}
+	return filepath.FromSlash(path)
+}
+
+func pathToURI(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
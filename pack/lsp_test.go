@@ -0,0 +1,134 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yockp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func loadAnnotations(t *testing.T) *Annotations {
+	ann := NewAnnotations()
+	if err := ann.LoadDir("../lib/include"); err != nil {
+		t.Fatal(err)
+	}
+	return ann
+}
+
+func TestAnnotations(t *testing.T) {
+	ann := loadAnnotations(t)
+	if echo := ann.Lookup("echo"); len(echo) != 2 || echo[1].Params[0].Type != "echo_opt" {
+		t.Fatal("overloads of echo aren't loaded", echo)
+	}
+	if env := ann.Lookup("env"); len(env) == 0 || env[0].Kind != SymbolTable || env[0].Type != "env" {
+		t.Fatal("invalid env", env)
+	}
+	if platform := ann.Member("env", ".", "platform"); len(platform) == 0 || platform[0].Type != "platform" {
+		t.Fatal("invalid env.platform", platform)
+	}
+	if job := ann.Lookup("job"); len(job) == 0 || funcParamTypes(job[0].Params[1].Type)[0] != "context" {
+		t.Fatal("invalid job", job)
+	}
+	if ret := ann.Lookup("json.create"); len(ret) == 0 || ret[0].Returns[0] != "json_object" {
+		t.Fatal("invalid json.create", ret)
+	}
+}
+
+const lspScript = `local jf = json.create("a.json")
+job("build", function(ctx)
+    ctx.exit(0)
+    foo()
+end)
+print(jf)`
+
+func TestLanguageServer(t *testing.T) {
+	in := &bytes.Buffer{}
+	id := 0
+	write := func(method string, params any) {
+		id++
+		raw, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": id, "method": method, "params": params})
+		fmt.Fprintf(in, "Content-Length: %d\r\n\r\n%s", len(raw), raw)
+	}
+	doc := map[string]any{"uri": "file:///main.lua"}
+	at := func(line, char int) map[string]any {
+		return map[string]any{"textDocument": doc, "position": map[string]any{"line": line, "character": char}}
+	}
+	write("initialize", map[string]any{})
+	write("textDocument/didOpen", map[string]any{"textDocument": map[string]any{"uri": doc["uri"], "text": lspScript}})
+	write("textDocument/completion", at(2, 8))
+	write("textDocument/hover", at(1, 1))
+	write("textDocument/definition", at(5, 7))
+	write("textDocument/definition", at(1, 1))
+	// the incomplete script keeps the scopes of last analysis
+	write("textDocument/didChange", map[string]any{
+		"textDocument":   doc,
+		"contentChanges": []map[string]any{{"text": lspScript + "\njson."}},
+	})
+	write("textDocument/completion", at(6, 5))
+	write("shutdown", nil)
+	write("exit", nil)
+
+	out := &bytes.Buffer{}
+	if err := NewLanguageServer(loadAnnotations(t)).Serve(in, out); err != nil {
+		t.Fatal(err)
+	}
+	results := map[float64]any{}
+	diagnostics := [][]any{}
+	r := textproto.NewReader(bufio.NewReader(out))
+	for {
+		header, err := r.ReadMIMEHeader()
+		if err != nil {
+			break
+		}
+		length, _ := strconv.Atoi(header.Get("Content-Length"))
+		raw := make([]byte, length)
+		io.ReadFull(r.R, raw)
+		msg := map[string]any{}
+		json.Unmarshal(raw, &msg)
+		if msg["method"] == "textDocument/publishDiagnostics" {
+			diagnostics = append(diagnostics, msg["params"].(map[string]any)["diagnostics"].([]any))
+		} else {
+			results[msg["id"].(float64)] = msg["result"]
+		}
+	}
+
+	labels := func(res any) string {
+		names := []string{}
+		for _, item := range res.(map[string]any)["items"].([]any) {
+			names = append(names, item.(map[string]any)["label"].(string))
+		}
+		return strings.Join(names, " ")
+	}
+	if len(diagnostics) != 2 || len(diagnostics[0]) != 1 ||
+		diagnostics[0][0].(map[string]any)["message"] != "undefined global foo" {
+		t.Fatal("invalid diagnostics", diagnostics)
+	}
+	if ctx := labels(results[3]); !strings.Contains(ctx, "exit") || !strings.Contains(ctx, "task") {
+		t.Fatal("invalid completion of ctx", ctx)
+	}
+	hover := results[4].(map[string]any)["contents"].(map[string]any)["value"].(string)
+	if !strings.Contains(hover, "function job(name, callback, opt)") {
+		t.Fatal("invalid hover", hover)
+	}
+	local := results[5].(map[string]any)
+	if start := local["range"].(map[string]any)["start"].(map[string]any); start["line"] != float64(0) || start["character"] != float64(6) {
+		t.Fatal("invalid definition of local", local)
+	}
+	stub := results[6].([]any)[0].(map[string]any)
+	if !strings.HasSuffix(stub["uri"].(string), filepath.ToSlash("lib/include/yock/job.lua")) {
+		t.Fatal("invalid definition of job", stub)
+	}
+	if js := labels(results[8]); !strings.Contains(js, "encode") || !strings.Contains(js, "decode") {
+		t.Fatal("invalid completion of json", js)
+	}
+}
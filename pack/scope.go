@@ -0,0 +1,466 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yockp
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/gopher-lua/ast"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+// luaBuiltins are the globals provided by lua interpreter
+var luaBuiltins = map[string]bool{
+	"_G": true, "_VERSION": true, "arg": true, "assert": true, "collectgarbage": true,
+	"coroutine": true, "debug": true, "dofile": true, "error": true, "getfenv": true,
+	"getmetatable": true, "io": true, "ipairs": true, "load": true, "loadfile": true,
+	"loadstring": true, "math": true, "module": true, "newproxy": true, "next": true,
+	"os": true, "package": true, "pairs": true, "pcall": true, "print": true,
+	"rawequal": true, "rawget": true, "rawset": true, "require": true, "select": true,
+	"setfenv": true, "setmetatable": true, "string": true, "table": true, "tonumber": true,
+	"tostring": true, "type": true, "unpack": true, "xpcall": true, "channel": true,
+}
+
+// luaScope is the block of lua script, such as function and loop
+type luaScope struct {
+	parent   *luaScope
+	children []*luaScope
+	line     int
+	lastLine int
+	vars     []*luaVar
+}
+
+// luaVar is the local variable declared in scope
+type luaVar struct {
+	name string
+	line int
+	// typ is the type of variable inferred from annotations, such as context of job's callback
+	typ string
+}
+
+// lookup returns the variable visible at line in scope and its parents
+func (scope *luaScope) lookup(name string, line int) *luaVar {
+	for s := scope; s != nil; s = s.parent {
+		for i := len(s.vars) - 1; i >= 0; i-- {
+			if v := s.vars[i]; v.name == name && v.line <= line {
+				return v
+			}
+		}
+	}
+	return nil
+}
+
+// at returns the innermost scope containing line
+func (scope *luaScope) at(line int) *luaScope {
+	for _, child := range scope.children {
+		if child.line <= line && line <= child.lastLine {
+			return child.at(line)
+		}
+	}
+	return scope
+}
+
+// visible returns the variables visible at line, and the inner shadows the outer
+func (scope *luaScope) visible(line int) []*luaVar {
+	res := []*luaVar{}
+	seen := make(map[string]bool)
+	for s := scope.at(line); s != nil; s = s.parent {
+		for i := len(s.vars) - 1; i >= 0; i-- {
+			if v := s.vars[i]; v.line <= line && !seen[v.name] {
+				seen[v.name] = true
+				res = append(res, v)
+			}
+		}
+	}
+	return res
+}
+
+// luaRef is the reference to global variable
+type luaRef struct {
+	name string
+	line int
+}
+
+// luaDocument is the analysis of lua script opened by editor
+type luaDocument struct {
+	lines []string
+	root  *luaScope
+	// globals maps the global variables declared in script to the line of first assignment
+	globals map[string]int
+	refs    []luaRef
+	err     *parse.Error
+}
+
+// analyzeDocument parses source and resolves the variables with annotations.
+// When source can't be parsed, the returned document only has err.
+func analyzeDocument(yockp YockPack[NilFrame], ann *Annotations, name, source string) *luaDocument {
+	doc := &luaDocument{
+		lines:   strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n"),
+		globals: make(map[string]int),
+	}
+	stmts, err := yockp.Parse(strings.NewReader(source), name)
+	if err != nil {
+		perr := &parse.Error{}
+		if !errors.As(err, &perr) {
+			perr = &parse.Error{Message: err.Error()}
+			perr.Pos.Line = 1
+			perr.Pos.Column = 1
+		}
+		doc.err = perr
+		return doc
+	}
+	doc.root = &luaScope{line: 1, lastLine: len(doc.lines)}
+	w := &scopeWalker{ann: ann, doc: doc, scope: doc.root}
+	w.stmts(stmts)
+	return doc
+}
+
+// undefined returns the references to globals which are declared
+// neither in script nor in annotations.
+func (doc *luaDocument) undefined(ann *Annotations) []luaRef {
+	res := []luaRef{}
+	for _, ref := range doc.refs {
+		if _, ok := doc.globals[ref.name]; ok || luaBuiltins[ref.name] || len(ann.Lookup(ref.name)) > 0 {
+			continue
+		}
+		res = append(res, ref)
+	}
+	return res
+}
+
+// column returns the column (starting from 0) of word in line, and -1 if not found
+func (doc *luaDocument) column(line int, word string) int {
+	if line <= 0 || line > len(doc.lines) {
+		return -1
+	}
+	text := doc.lines[line-1]
+	for off := 0; ; {
+		i := strings.Index(text[off:], word)
+		if i < 0 {
+			return -1
+		}
+		i += off
+		end := i + len(word)
+		if (i == 0 || !isIdentChar(text[i-1])) && (end == len(text) || !isIdentChar(text[end])) {
+			return i
+		}
+		off = end
+	}
+}
+
+func isIdentChar(ch byte) bool {
+	return ch == '_' || ('a' <= ch && ch <= 'z') || ('A' <= ch && ch <= 'Z') || ('0' <= ch && ch <= '9')
+}
+
+var pathPattern = regexp.MustCompile(`[A-Za-z_][\w]*(?:[.:][A-Za-z_]?[\w]*)*$`)
+
+// pathBefore returns the expression of names before character, such as
+// env.platform. for completion
+func (doc *luaDocument) pathBefore(line, char int) string {
+	if line < 0 || line >= len(doc.lines) {
+		return ""
+	}
+	text := doc.lines[line]
+	if char > len(text) {
+		char = len(text)
+	}
+	return pathPattern.FindString(text[:char])
+}
+
+// pathAt returns the expression of names at character, which ends
+// with the name under cursor, such as json for js|on.encode
+func (doc *luaDocument) pathAt(line, char int) string {
+	if line < 0 || line >= len(doc.lines) {
+		return ""
+	}
+	text := doc.lines[line]
+	end := char
+	for end < len(text) && isIdentChar(text[end]) {
+		end++
+	}
+	return pathPattern.FindString(text[:end])
+}
+
+// splitPath splits path into names and separators, such as
+// [env platform Exf] and [. :] for env.platform:Exf
+func splitPath(path string) ([]string, []string) {
+	names, seps := []string{}, []string{}
+	last := 0
+	for i := 0; i < len(path); i++ {
+		if path[i] == '.' || path[i] == ':' {
+			names = append(names, path[last:i])
+			seps = append(seps, path[i:i+1])
+			last = i + 1
+		}
+	}
+	return append(names, path[last:]), seps
+}
+
+// typeOf returns the type of variable at line, which is inferred
+// from annotations of stdlib or the declaration of local.
+func (doc *luaDocument) typeOf(ann *Annotations, name string, line int) string {
+	if doc.root != nil {
+		if v := doc.root.at(line).lookup(name, line); v != nil {
+			return v.typ
+		}
+	}
+	if syms := ann.Lookup(name); len(syms) > 0 && syms[0].Kind == SymbolTable {
+		return syms[0].Type
+	}
+	return ""
+}
+
+// resolve returns the type of names joined by separators, and
+// empty when any of names is unknown.
+func (doc *luaDocument) resolve(ann *Annotations, names, seps []string, line int) string {
+	typ := doc.typeOf(ann, names[0], line)
+	for i := 1; i < len(names) && len(typ) > 0; i++ {
+		syms := ann.Member(typ, seps[i-1], names[i])
+		if len(syms) == 0 || syms[0].Kind == SymbolFunction {
+			return ""
+		}
+		typ = baseType(syms[0].Type)
+	}
+	return typ
+}
+
+// scopeWalker traverses the statements to build scopes and collect references
+type scopeWalker struct {
+	ann   *Annotations
+	doc   *luaDocument
+	scope *luaScope
+}
+
+func (w *scopeWalker) open(line, lastLine int) {
+	child := &luaScope{parent: w.scope, line: line, lastLine: lastLine}
+	if lastLine < line {
+		child.lastLine = w.scope.lastLine
+	}
+	w.scope.children = append(w.scope.children, child)
+	w.scope = child
+}
+
+func (w *scopeWalker) close() {
+	w.scope = w.scope.parent
+}
+
+func (w *scopeWalker) declare(name string, line int, typ string) {
+	w.scope.vars = append(w.scope.vars, &luaVar{name: name, line: line, typ: typ})
+}
+
+// assign records the assignment to name, which declares global unless it's local
+func (w *scopeWalker) assign(name string, line int) {
+	if w.scope.lookup(name, line) != nil {
+		return
+	}
+	if _, ok := w.doc.globals[name]; !ok {
+		w.doc.globals[name] = line
+	}
+}
+
+func (w *scopeWalker) block(stmts []ast.Stmt, line, lastLine int) {
+	w.open(line, lastLine)
+	w.stmts(stmts)
+	w.close()
+}
+
+func (w *scopeWalker) stmts(stmts []ast.Stmt) {
+	for _, stmt := range stmts {
+		w.stmt(stmt)
+	}
+}
+
+func (w *scopeWalker) stmt(stmt ast.Stmt) {
+	switch s := stmt.(type) {
+	case *ast.LocalAssignStmt:
+		if fn, ok := localFunction(s); ok {
+			w.declare(s.Names[0], s.Line(), "")
+			w.function(fn, nil, false)
+			return
+		}
+		w.exprs(s.Exprs)
+		for i, name := range s.Names {
+			typ := ""
+			if i < len(s.Exprs) {
+				typ = w.infer(s.Exprs[i])
+			}
+			w.declare(name, s.Line(), typ)
+		}
+	case *ast.AssignStmt:
+		w.exprs(s.Rhs)
+		for _, lhs := range s.Lhs {
+			if ident, ok := lhs.(*ast.IdentExpr); ok {
+				w.assign(ident.Value, s.Line())
+			} else {
+				w.expr(lhs)
+			}
+		}
+	case *ast.FuncCallStmt:
+		w.expr(s.Expr)
+	case *ast.DoBlockStmt:
+		w.block(s.Stmts, s.Line(), s.LastLine())
+	case *ast.WhileStmt:
+		w.expr(s.Condition)
+		w.block(s.Stmts, s.Line(), s.LastLine())
+	case *ast.RepeatStmt:
+		// the condition can see the locals of block
+		w.open(s.Line(), s.LastLine())
+		w.stmts(s.Stmts)
+		w.expr(s.Condition)
+		w.close()
+	case *ast.IfStmt:
+		w.expr(s.Condition)
+		w.block(s.Then, s.Line(), s.LastLine())
+		w.block(s.Else, s.Line(), s.LastLine())
+	case *ast.NumberForStmt:
+		w.exprs([]ast.Expr{s.Init, s.Limit, s.Step})
+		w.open(s.Line(), s.LastLine())
+		w.declare(s.Name, s.Line(), "")
+		w.stmts(s.Stmts)
+		w.close()
+	case *ast.GenericForStmt:
+		w.exprs(s.Exprs)
+		w.open(s.Line(), s.LastLine())
+		for _, name := range s.Names {
+			w.declare(name, s.Line(), "")
+		}
+		w.stmts(s.Stmts)
+		w.close()
+	case *ast.FuncDefStmt:
+		if s.Name.Func != nil {
+			if ident, ok := s.Name.Func.(*ast.IdentExpr); ok {
+				w.assign(ident.Value, s.Line())
+			} else {
+				w.expr(s.Name.Func)
+			}
+			w.function(s.Func, nil, false)
+		} else {
+			w.expr(s.Name.Receiver)
+			w.function(s.Func, nil, true)
+		}
+	case *ast.ReturnStmt:
+		w.exprs(s.Exprs)
+	}
+}
+
+// localFunction reports whether stmt is in form of local function name() end,
+// and the name is visible in its body.
+func localFunction(s *ast.LocalAssignStmt) (*ast.FunctionExpr, bool) {
+	if len(s.Names) != 1 || len(s.Exprs) != 1 {
+		return nil, false
+	}
+	fn, ok := s.Exprs[0].(*ast.FunctionExpr)
+	return fn, ok
+}
+
+func (w *scopeWalker) function(fn *ast.FunctionExpr, types []string, self bool) {
+	w.open(fn.Line(), fn.LastLine())
+	if self {
+		w.declare("self", fn.Line(), "")
+	}
+	for i, name := range fn.ParList.Names {
+		typ := ""
+		if i < len(types) {
+			typ = baseType(types[i])
+		}
+		w.declare(name, fn.Line(), typ)
+	}
+	w.stmts(fn.Stmts)
+	w.close()
+}
+
+func (w *scopeWalker) exprs(exprs []ast.Expr) {
+	for _, expr := range exprs {
+		w.expr(expr)
+	}
+}
+
+func (w *scopeWalker) expr(expr ast.Expr) {
+	switch e := expr.(type) {
+	case *ast.IdentExpr:
+		if w.scope.lookup(e.Value, e.Line()) == nil {
+			w.doc.refs = append(w.doc.refs, luaRef{name: e.Value, line: e.Line()})
+		}
+	case *ast.AttrGetExpr:
+		w.exprs([]ast.Expr{e.Object, e.Key})
+	case *ast.TableExpr:
+		for _, field := range e.Fields {
+			w.exprs([]ast.Expr{field.Key, field.Value})
+		}
+	case *ast.FuncCallExpr:
+		w.exprs([]ast.Expr{e.Func, e.Receiver})
+		callee := w.callee(e)
+		for i, arg := range e.Args {
+			if fn, ok := arg.(*ast.FunctionExpr); ok {
+				w.function(fn, w.callbackTypes(callee, i), false)
+			} else {
+				w.expr(arg)
+			}
+		}
+	case *ast.FunctionExpr:
+		w.function(e, nil, false)
+	case *ast.LogicalOpExpr:
+		w.exprs([]ast.Expr{e.Lhs, e.Rhs})
+	case *ast.RelationalOpExpr:
+		w.exprs([]ast.Expr{e.Lhs, e.Rhs})
+	case *ast.StringConcatOpExpr:
+		w.exprs([]ast.Expr{e.Lhs, e.Rhs})
+	case *ast.ArithmeticOpExpr:
+		w.exprs([]ast.Expr{e.Lhs, e.Rhs})
+	case *ast.UnaryMinusOpExpr:
+		w.expr(e.Expr)
+	case *ast.UnaryNotOpExpr:
+		w.expr(e.Expr)
+	case *ast.UnaryLenOpExpr:
+		w.expr(e.Expr)
+	}
+}
+
+// callee returns the declarations of function called in annotations
+func (w *scopeWalker) callee(call *ast.FuncCallExpr) []*LuaSymbol {
+	if call.Func == nil {
+		return nil
+	}
+	name := parseFuncExpr(call.Func)
+	if len(name) == 0 || w.scope.lookup(strings.SplitN(name, ".", 2)[0], call.Line()) != nil {
+		return nil
+	}
+	return w.ann.Lookup(name)
+}
+
+// callbackTypes returns the types of callback's parameters at idx of
+// callee's arguments, such as [context] for the callback of job.
+func (w *scopeWalker) callbackTypes(callee []*LuaSymbol, idx int) []string {
+	for _, sym := range callee {
+		if idx < len(sym.Params) {
+			if types := funcParamTypes(sym.Params[idx].Type); types != nil {
+				return types
+			}
+		}
+	}
+	return nil
+}
+
+// infer returns the type of expression, such as json_object for json.create()
+func (w *scopeWalker) infer(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.IdentExpr:
+		if v := w.scope.lookup(e.Value, e.Line()); v != nil {
+			return v.typ
+		}
+		if syms := w.ann.Lookup(e.Value); len(syms) > 0 && syms[0].Kind == SymbolTable {
+			return syms[0].Type
+		}
+	case *ast.FuncCallExpr:
+		for _, sym := range w.callee(e) {
+			if len(sym.Returns) > 0 {
+				return baseType(sym.Returns[0])
+			}
+		}
+	}
+	return ""
+}
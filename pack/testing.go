@@ -0,0 +1,364 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yockp
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	libtest "github.com/ansurfen/yock/lib/go/yock/test"
+	yockr "github.com/ansurfen/yock/runtime"
+	yocks "github.com/ansurfen/yock/scheduler"
+)
+
+// TestOpt indicates configuration of RunTests
+type TestOpt struct {
+	// LibPath is the directory of yock libraries loaded by states
+	LibPath string
+	// Parallel is the number of cases run at the same time, 1 by default.
+	// The cases changing working directory shouldn't be run in parallel.
+	Parallel int
+	// Timeout is the budget of wall clock for each case, and it's unlimited when 0.
+	Timeout time.Duration
+	// Cache keeps the compiled test files, and it's disabled when nil.
+	Cache *yockr.BytecodeCache
+}
+
+// TestResult is the result of case declared in File
+type TestResult struct {
+	File string
+	libtest.Result
+}
+
+// testDrainTimeout is how long the goroutines spawned by case are waited
+const testDrainTimeout = time.Second
+
+// DiscoverTests returns the *_test.lua files matched by patterns. The directory
+// is walked, the file is taken as it is, and the others are the glob patterns.
+// The pattern matching nothing by path is matched with the name of test files
+// under working directory, e.g. gnu* matches ./lib/test/gnu_test.lua.
+func DiscoverTests(patterns ...string) ([]string, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+	files := map[string]bool{}
+	walk := func(root string, match func(path string) bool) error {
+		return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && strings.HasSuffix(path, "_test.lua") && match(path) {
+				files[path] = true
+			}
+			return nil
+		})
+	}
+	all := func(string) bool { return true }
+	for _, pattern := range patterns {
+		if info, err := os.Stat(pattern); err == nil {
+			if !info.IsDir() {
+				files[pattern] = true
+				continue
+			}
+			if err = walk(pattern, all); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			if info, err := os.Stat(match); err == nil && info.IsDir() {
+				err = walk(match, all)
+				if err != nil {
+					return nil, err
+				}
+			} else if strings.HasSuffix(match, "_test.lua") {
+				files[match] = true
+			}
+		}
+		if len(matches) > 0 {
+			continue
+		}
+		err = walk(".", func(path string) bool {
+			ok, _ := filepath.Match(pattern, path)
+			if !ok {
+				ok, _ = filepath.Match(pattern, filepath.Base(path))
+			}
+			return ok
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	res := []string{}
+	for file := range files {
+		res = append(res, file)
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
+// RunTests runs the cases of files. Each of them is run in a new state, where
+// the file is run again to declare the cases, so that they never share globals.
+// The results are ordered by files and the declaration of cases.
+func RunTests(opt TestOpt, files []string) []TestResult {
+	type testCase struct {
+		idx  int
+		file string
+		name string
+	}
+	results := []TestResult{}
+	cases := []testCase{}
+	for _, file := range files {
+		names, err := collectTests(opt, file)
+		if err != nil {
+			res := TestResult{File: file}
+			res.Name, res.Status, res.Message = filepath.Base(file), libtest.StatusFail, err.Error()
+			results = append(results, res)
+			continue
+		}
+		for _, name := range names {
+			cases = append(cases, testCase{idx: len(results), file: file, name: name})
+			results = append(results, TestResult{File: file})
+		}
+	}
+	parallel := opt.Parallel
+	if parallel < 1 {
+		parallel = 1
+	}
+	queue := make(chan testCase)
+	wg := sync.WaitGroup{}
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range queue {
+				results[c.idx] = TestResult{File: c.file, Result: runTest(opt, c.file, c.name)}
+			}
+		}()
+	}
+	for _, c := range cases {
+		queue <- c
+	}
+	close(queue)
+	wg.Wait()
+	return results
+}
+
+// loadTests creates the state declaring the cases of file
+func loadTests(opt TestOpt, file string) (*yocks.YockScheduler, error) {
+	var runtime []yockr.YockrOption
+	if opt.Timeout > 0 {
+		runtime = append(runtime, yockr.OptionTimeout(opt.Timeout))
+	}
+	ys := yocks.Default(
+		yocks.OptionRuntime(runtime...),
+		yocks.OptionLibPath(opt.LibPath),
+		yocks.OptionBytecodeCache(opt.Cache))
+	libtest.LoadTest(ys)
+	go ys.EventLoop()
+	proto, err := opt.Cache.CompileFile(file)
+	if err != nil {
+		return ys, err
+	}
+	l := ys.State().LState()
+	return ys, yockr.LuaDoFunc(l, l.NewFunctionFromProto(proto))
+}
+
+func collectTests(opt TestOpt, file string) ([]string, error) {
+	ys, err := loadTests(opt, file)
+	defer ys.Shutdown(testDrainTimeout)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, c := range libtest.SuiteOf(ys.State().LState()).Cases {
+		names = append(names, c.Name)
+	}
+	return names, nil
+}
+
+func runTest(opt TestOpt, file, name string) libtest.Result {
+	start := time.Now()
+	ys, err := loadTests(opt, file)
+	defer ys.Shutdown(testDrainTimeout)
+	if err != nil {
+		return libtest.Result{
+			Name:     name,
+			Status:   libtest.StatusFail,
+			Message:  err.Error(),
+			Duration: time.Since(start),
+		}
+	}
+	return libtest.SuiteOf(ys.State().LState()).Run(ys.State().LState(), name)
+}
+
+// TestsFailed reports whether any of results fails
+func TestsFailed(results []TestResult) bool {
+	for _, res := range results {
+		if res.Status == libtest.StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteTestReport writes results in text, tap or junit
+func WriteTestReport(w io.Writer, format string, results []TestResult) error {
+	switch format {
+	case "", "text":
+		return writeTestText(w, results)
+	case "tap":
+		return writeTestTAP(w, results)
+	case "junit":
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return err
+		}
+		enc := xml.NewEncoder(w)
+		enc.Indent("", "  ")
+		if err := enc.Encode(junitReport(results)); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, "\n")
+		return err
+	}
+	return fmt.Errorf("invalid format %s", format)
+}
+
+func writeTestText(w io.Writer, results []TestResult) error {
+	count := map[string]int{}
+	for _, res := range results {
+		count[res.Status]++
+		status := strings.ToUpper(res.Status)
+		fmt.Fprintf(w, "--- %s: %s: %s (%.2fs)\n", status, res.File, res.Name, res.Duration.Seconds())
+		if len(res.Message) > 0 && res.Status != libtest.StatusPass {
+			fmt.Fprintf(w, "    %s\n", strings.ReplaceAll(res.Message, "\n", "\n    "))
+		}
+		for _, log := range res.Logs {
+			fmt.Fprintf(w, "    %s\n", log)
+		}
+	}
+	summary := "ok"
+	if TestsFailed(results) {
+		summary = "FAIL"
+	}
+	_, err := fmt.Fprintf(w, "%s\t%d passed, %d failed, %d skipped\n",
+		summary, count[libtest.StatusPass], count[libtest.StatusFail], count[libtest.StatusSkip])
+	return err
+}
+
+// writeTestTAP writes results in TAP version 13, and the failures
+// are explained by the YAML blocks following them.
+func writeTestTAP(w io.Writer, results []TestResult) error {
+	fmt.Fprintf(w, "TAP version 13\n1..%d\n", len(results))
+	for i, res := range results {
+		desc := strings.ReplaceAll(res.File+": "+res.Name, "#", `\#`)
+		switch res.Status {
+		case libtest.StatusPass:
+			fmt.Fprintf(w, "ok %d - %s\n", i+1, desc)
+		case libtest.StatusSkip:
+			fmt.Fprintf(w, "ok %d - %s # SKIP %s\n", i+1, desc, res.Message)
+		default:
+			fmt.Fprintf(w, "not ok %d - %s\n", i+1, desc)
+			fmt.Fprintf(w, "  ---\n  message: |\n    %s\n", strings.ReplaceAll(res.Message, "\n", "\n    "))
+			fmt.Fprintf(w, "  duration_ms: %d\n  ...\n", res.Duration.Milliseconds())
+		}
+		for _, log := range res.Logs {
+			fmt.Fprintf(w, "# %s\n", log)
+		}
+	}
+	return nil
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// junitReport groups results into test suites by file
+func junitReport(results []TestResult) junitTestSuites {
+	report := junitTestSuites{}
+	var total time.Duration
+	idx := map[string]int{}
+	durations := []time.Duration{}
+	for _, res := range results {
+		i, ok := idx[res.File]
+		if !ok {
+			i = len(report.Suites)
+			idx[res.File] = i
+			report.Suites = append(report.Suites, junitTestSuite{Name: filepath.ToSlash(res.File)})
+			durations = append(durations, 0)
+		}
+		suite := &report.Suites[i]
+		c := junitTestCase{
+			Name:      res.Name,
+			ClassName: suite.Name,
+			Time:      seconds(res.Duration),
+			SystemOut: strings.Join(res.Logs, "\n"),
+		}
+		switch res.Status {
+		case libtest.StatusFail:
+			c.Failure = &junitMessage{Message: res.Message, Body: strings.TrimSpace(res.Message + "\n" + res.Trace)}
+			suite.Failures++
+			report.Failures++
+		case libtest.StatusSkip:
+			c.Skipped = &junitMessage{Message: res.Message}
+			suite.Skipped++
+			report.Skipped++
+		}
+		suite.Tests++
+		suite.Cases = append(suite.Cases, c)
+		report.Tests++
+		durations[i] += res.Duration
+		total += res.Duration
+	}
+	for i := range report.Suites {
+		report.Suites[i].Time = seconds(durations[i])
+	}
+	report.Time = seconds(total)
+	return report
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,112 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yockp
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ansurfen/yock/ctl/conf"
+	yocke "github.com/ansurfen/yock/env"
+)
+
+const testScript = `local count = 0
+setup(function() count = count + 1 end)
+
+test("isolated", function(t)
+    t:equal(count, 1)
+    t:same({ a = { 1, 2 } }, { a = { 1, 2 } })
+    t:log("count", count)
+end)
+
+test("diff", function(t)
+    t:same({ a = { 1, 3 }, c = true }, { a = { 1, 2 }, b = "x" })
+end)
+
+test("skip", function(t) end, { skip = "unix only" })
+test("skip inside", function(t) t:skip("later") end)
+test("spin", function(t) while true do end end)`
+
+func TestRunTests(t *testing.T) {
+	yocke.InitEnv(&yocke.EnvOpt[*conf.YockConf]{
+		Workdir:  ".yock",
+		Conf:     &conf.YockConf{},
+		ConfTmpl: conf.YockConfTmpl,
+		Filename: "yock.yaml",
+	})
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "sub"), 0755)
+	os.WriteFile(filepath.Join(dir, "sub", "demo_test.lua"), []byte(testScript), 0644)
+	os.WriteFile(filepath.Join(dir, "broken_test.lua"), []byte("test("), 0644)
+	os.WriteFile(filepath.Join(dir, "helper.lua"), []byte(""), 0644)
+
+	files, err := DiscoverTests(dir)
+	if err != nil || len(files) != 2 {
+		t.Fatal("unexpected test files", files, err)
+	}
+	files, err = DiscoverTests(filepath.Join(dir, "*", "demo_*.lua"))
+	if err != nil || len(files) != 1 {
+		t.Fatal("unexpected test files", files, err)
+	}
+
+	results := RunTests(TestOpt{
+		LibPath:  "../lib/yock",
+		Parallel: 2,
+		Timeout:  time.Second,
+	}, []string{filepath.Join(dir, "broken_test.lua"), files[0]})
+	got := []string{}
+	for _, res := range results {
+		got = append(got, res.Name+" "+res.Status)
+	}
+	want := []string{
+		"broken_test.lua fail",
+		"isolated pass",
+		"diff fail",
+		"skip skip",
+		"skip inside skip",
+		"spin fail",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected results\n%s", strings.Join(got, "\n"))
+	}
+	if diff := results[2].Message; !strings.HasSuffix(diff, "not same\n\tgot.a[2]: 3 ~= 2\n\tgot.b: missing, want \"x\"\n\tgot.c: unexpected true") {
+		t.Fatal("unexpected diff", diff)
+	}
+	if results[1].Logs[0] != "count 1" || results[3].Message != "unix only" || results[4].Message != "later" {
+		t.Fatal("unexpected messages", results)
+	}
+	if results[5].Message != "exceed timeout limit 1s" {
+		t.Fatal("spin isn't stopped by timeout", results[5].Message)
+	}
+	if !TestsFailed(results) {
+		t.Fatal("failures aren't reported")
+	}
+
+	buf := &bytes.Buffer{}
+	if err = WriteTestReport(buf, "tap", results); err != nil {
+		t.Fatal(err)
+	}
+	if tap := buf.String(); !strings.HasPrefix(tap, "TAP version 13\n1..6\nnot ok 1 - ") ||
+		!strings.Contains(tap, "ok 4 - "+files[0]+": skip # SKIP unix only\n") {
+		t.Fatal("unexpected tap", tap)
+	}
+	buf.Reset()
+	if err = WriteTestReport(buf, "junit", results); err != nil {
+		t.Fatal(err)
+	}
+	report := junitTestSuites{}
+	if err = xml.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Tests != 6 || report.Failures != 3 || report.Skipped != 2 || len(report.Suites) != 2 ||
+		report.Suites[1].Cases[0].SystemOut != "count 1" {
+		t.Fatal("unexpected junit", buf.String())
+	}
+}
@@ -6,14 +6,17 @@ package yockp
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	yocki "github.com/ansurfen/yock/interface"
+	yockr "github.com/ansurfen/yock/runtime"
 	"github.com/ansurfen/yock/util"
 	"github.com/ansurfen/yock/ycho"
 	lua "github.com/yuin/gopher-lua"
 	"github.com/yuin/gopher-lua/ast"
 	"github.com/yuin/gopher-lua/parse"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -109,6 +112,12 @@ type (
 	FunctionExpr       = *ast.FunctionExpr
 )
 
+// Parse parses the content of reader into a lua statement structure, and
+// returns the error instead of exiting, which is used by long-running tools.
+func (*YockPack[T]) Parse(reader io.Reader, name string) ([]ast.Stmt, error) {
+	return parse.Parse(bufio.NewReader(reader), name)
+}
+
 // ParseStr parses the given string into a lua statement structure
 func (*YockPack[T]) ParseStr(str string) []ast.Stmt {
 	reader := bufio.NewReader(strings.NewReader(str))
@@ -149,17 +158,19 @@ func (yockpack *YockPack[T]) DumpFile(file string) string {
 type CompileOpt struct {
 	DisableAnalyse bool
 	VM             yocki.YockRuntime
+	// DebugHook is the name of global function called before every statement,
+	// and script isn't instrumented when it's empty.
+	DebugHook string
+	// Cache keeps the compiled script, and it's disabled when nil.
+	Cache *yockr.BytecodeCache
 }
 
 // Compile compiles the contents of the given file into functions that can be executed by the virtual machine.
 func (yockpack *YockPack[T]) Compile(opt CompileOpt, file string) *lua.LFunction {
-	fp, err := os.Open(file)
+	source, err := os.ReadFile(file)
 	if err != nil {
 		ycho.Fatal(err)
 	}
-	defer fp.Close()
-	reader := bufio.NewReader(fp)
-	chunk, err := parse.Parse(reader, file)
 
 	if opt.DisableAnalyse {
 		anlyzer := NewLuaDependencyAnalyzer()
@@ -186,10 +197,23 @@ func (yockpack *YockPack[T]) Compile(opt CompileOpt, file string) *lua.LFunction
 		}
 	}
 
-	if err != nil {
-		ycho.Fatal(err)
+	compile := func() (*lua.FunctionProto, error) {
+		chunk, err := parse.Parse(bytes.NewReader(source), file)
+		if err != nil {
+			return nil, err
+		}
+		if len(opt.DebugHook) > 0 {
+			chunk = yockpack.Instrument(chunk, opt.DebugHook)
+		}
+		return lua.Compile(chunk, file)
+	}
+	var proto *lua.FunctionProto
+	// the instrumented script is never cached
+	if len(opt.DebugHook) > 0 {
+		proto, err = compile()
+	} else {
+		proto, err = opt.Cache.Compile(file, source, compile)
 	}
-	proto, err := lua.Compile(chunk, file)
 	if err != nil {
 		ycho.Fatal(err)
 	}
@@ -0,0 +1,367 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yockr
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"unsafe"
+
+	"github.com/ansurfen/yock/util"
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+// bytecodeMagic heads every cached file, and it's changed
+// whenever the encoding of FunctionProto is changed.
+const bytecodeMagic = "YOCKBC\x01"
+
+var errBadBytecode = errors.New("bad bytecode")
+
+// BytecodeCache keeps the compiled FunctionProto of scripts on disk. Each
+// script takes one file named after its name and yock version, which records
+// the hash of content compiled, so that the unchanged script is loaded
+// without parsing and compiling again and the changed one overwrites it.
+type BytecodeCache struct {
+	dir string
+}
+
+func NewBytecodeCache(dir string) *BytecodeCache {
+	return &BytecodeCache{dir: dir}
+}
+
+// Compile returns the prototype of source named name. It's loaded from cache
+// when source isn't changed, otherwise it's compiled by compile and cached.
+// The cache is best effort, and the error of it never fails Compile.
+// It's same as calling compile directly for nil cache.
+func (cache *BytecodeCache) Compile(name string, source []byte, compile func() (*lua.FunctionProto, error)) (*lua.FunctionProto, error) {
+	if cache == nil {
+		return compile()
+	}
+	file, hash := cache.path(name), bytecodeHash(source)
+	if raw, err := os.ReadFile(file); err == nil {
+		if proto, err := decodeBytecode(raw, hash); err == nil {
+			return proto, nil
+		}
+	}
+	proto, err := compile()
+	if err != nil {
+		return nil, err
+	}
+	cache.store(file, hash, proto)
+	return proto, nil
+}
+
+// CompileFile is the cached version of lua.LState.LoadFile
+func (cache *BytecodeCache) CompileFile(file string) (*lua.FunctionProto, error) {
+	source, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return cache.Compile(file, source, func() (*lua.FunctionProto, error) {
+		chunk, err := parse.Parse(bytes.NewReader(source), file)
+		if err != nil {
+			return nil, err
+		}
+		return lua.Compile(chunk, file)
+	})
+}
+
+func (cache *BytecodeCache) path(name string) string {
+	if abs, err := filepath.Abs(name); err == nil {
+		name = abs
+	}
+	sum := sha256.Sum256([]byte(util.YockBuild + "\x00" + util.YockVersion + "\x00" + name))
+	return filepath.Join(cache.dir, hex.EncodeToString(sum[:16])+".luac")
+}
+
+func bytecodeHash(source []byte) []byte {
+	sum := sha256.Sum256(source)
+	return sum[:]
+}
+
+// store writes into a temporary file first, so that the concurrent
+// readers never see the file half written.
+func (cache *BytecodeCache) store(file string, hash []byte, proto *lua.FunctionProto) {
+	buf := &bytes.Buffer{}
+	buf.WriteString(bytecodeMagic)
+	buf.Write(hash)
+	if err := encodeProto(buf, proto); err != nil {
+		return
+	}
+	if err := os.MkdirAll(cache.dir, 0755); err != nil {
+		return
+	}
+	fp, err := os.CreateTemp(cache.dir, "*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = fp.Write(buf.Bytes())
+	fp.Close()
+	if err == nil {
+		err = os.Rename(fp.Name(), file)
+	}
+	if err != nil {
+		os.Remove(fp.Name())
+	}
+}
+
+// EncodeBytecode returns the bytecode of proto, which is restored by DecodeBytecode
+// in another process, e.g. the job's function dispatched to the node of cluster.
+func EncodeBytecode(proto *lua.FunctionProto) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteString(bytecodeMagic)
+	if err := encodeProto(buf, proto); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func DecodeBytecode(raw []byte) (*lua.FunctionProto, error) {
+	return decodeBytecode(raw, nil)
+}
+
+func decodeBytecode(raw, hash []byte) (*lua.FunctionProto, error) {
+	head := len(bytecodeMagic) + len(hash)
+	if len(raw) < head || string(raw[:len(bytecodeMagic)]) != bytecodeMagic ||
+		!bytes.Equal(raw[len(bytecodeMagic):head], hash) {
+		return nil, errBadBytecode
+	}
+	return decodeProto(bytes.NewReader(raw[head:]))
+}
+
+const (
+	constantNil byte = iota
+	constantFalse
+	constantTrue
+	constantNumber
+	constantString
+)
+
+type protoWriter struct {
+	w   *bufio.Writer
+	buf [binary.MaxVarintLen64]byte
+}
+
+func (pw *protoWriter) uint(v uint64) {
+	n := binary.PutUvarint(pw.buf[:], v)
+	pw.w.Write(pw.buf[:n])
+}
+
+func (pw *protoWriter) int(v int) {
+	n := binary.PutVarint(pw.buf[:], int64(v))
+	pw.w.Write(pw.buf[:n])
+}
+
+func (pw *protoWriter) str(s string) {
+	pw.uint(uint64(len(s)))
+	pw.w.WriteString(s)
+}
+
+func encodeProto(w io.Writer, proto *lua.FunctionProto) error {
+	pw := &protoWriter{w: bufio.NewWriter(w)}
+	if err := pw.proto(proto); err != nil {
+		return err
+	}
+	return pw.w.Flush()
+}
+
+func (pw *protoWriter) proto(proto *lua.FunctionProto) error {
+	pw.str(proto.SourceName)
+	pw.int(proto.LineDefined)
+	pw.int(proto.LastLineDefined)
+	pw.w.Write([]byte{proto.NumUpvalues, proto.NumParameters, proto.IsVarArg, proto.NumUsedRegisters})
+	pw.uint(uint64(len(proto.Code)))
+	for _, code := range proto.Code {
+		pw.uint(uint64(code))
+	}
+	pw.uint(uint64(len(proto.Constants)))
+	for _, constant := range proto.Constants {
+		switch v := constant.(type) {
+		case *lua.LNilType:
+			pw.w.WriteByte(constantNil)
+		case lua.LBool:
+			if v {
+				pw.w.WriteByte(constantTrue)
+			} else {
+				pw.w.WriteByte(constantFalse)
+			}
+		case lua.LNumber:
+			pw.w.WriteByte(constantNumber)
+			binary.LittleEndian.PutUint64(pw.buf[:8], math.Float64bits(float64(v)))
+			pw.w.Write(pw.buf[:8])
+		case lua.LString:
+			pw.w.WriteByte(constantString)
+			pw.str(string(v))
+		default:
+			return fmt.Errorf("%w: constant of %s", errBadBytecode, constant.Type())
+		}
+	}
+	pw.uint(uint64(len(proto.FunctionPrototypes)))
+	for _, child := range proto.FunctionPrototypes {
+		if err := pw.proto(child); err != nil {
+			return err
+		}
+	}
+	pw.uint(uint64(len(proto.DbgSourcePositions)))
+	for _, pos := range proto.DbgSourcePositions {
+		pw.int(pos)
+	}
+	pw.uint(uint64(len(proto.DbgLocals)))
+	for _, local := range proto.DbgLocals {
+		pw.str(local.Name)
+		pw.int(local.StartPc)
+		pw.int(local.EndPc)
+	}
+	pw.uint(uint64(len(proto.DbgCalls)))
+	for _, call := range proto.DbgCalls {
+		pw.str(call.Name)
+		pw.int(call.Pc)
+	}
+	pw.uint(uint64(len(proto.DbgUpvalues)))
+	for _, upvalue := range proto.DbgUpvalues {
+		pw.str(upvalue)
+	}
+	return nil
+}
+
+// protoReader remembers the first error, and the rest of reading
+// returns zero values after that.
+type protoReader struct {
+	r   *bytes.Reader
+	err error
+}
+
+func (pr *protoReader) fail(err error) {
+	if pr.err == nil {
+		pr.err = err
+	}
+}
+
+func (pr *protoReader) uint() uint64 {
+	v, err := binary.ReadUvarint(pr.r)
+	pr.fail(err)
+	return v
+}
+
+func (pr *protoReader) int() int {
+	v, err := binary.ReadVarint(pr.r)
+	pr.fail(err)
+	return int(v)
+}
+
+// len reads the length of slice, which is never more than the bytes left
+func (pr *protoReader) len() int {
+	n := pr.uint()
+	if n > uint64(pr.r.Len()) {
+		pr.fail(errBadBytecode)
+		return 0
+	}
+	return int(n)
+}
+
+func (pr *protoReader) bytes(n int) []byte {
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(pr.r, buf); err != nil {
+		pr.fail(err)
+	}
+	return buf
+}
+
+func (pr *protoReader) str() string {
+	return string(pr.bytes(pr.len()))
+}
+
+func decodeProto(r *bytes.Reader) (*lua.FunctionProto, error) {
+	pr := &protoReader{r: r}
+	proto := pr.proto()
+	if pr.err != nil {
+		return nil, pr.err
+	}
+	return proto, nil
+}
+
+func (pr *protoReader) proto() *lua.FunctionProto {
+	proto := &lua.FunctionProto{
+		SourceName:      pr.str(),
+		LineDefined:     pr.int(),
+		LastLineDefined: pr.int(),
+	}
+	head := pr.bytes(4)
+	proto.NumUpvalues, proto.NumParameters, proto.IsVarArg, proto.NumUsedRegisters = head[0], head[1], head[2], head[3]
+	proto.Code = make([]uint32, pr.len())
+	for i := range proto.Code {
+		proto.Code[i] = uint32(pr.uint())
+	}
+	proto.Constants = make([]lua.LValue, pr.len())
+	for i := range proto.Constants {
+		tag, err := pr.r.ReadByte()
+		pr.fail(err)
+		switch tag {
+		case constantNil:
+			proto.Constants[i] = lua.LNil
+		case constantFalse:
+			proto.Constants[i] = lua.LFalse
+		case constantTrue:
+			proto.Constants[i] = lua.LTrue
+		case constantNumber:
+			proto.Constants[i] = lua.LNumber(math.Float64frombits(binary.LittleEndian.Uint64(pr.bytes(8))))
+		case constantString:
+			proto.Constants[i] = lua.LString(pr.str())
+		default:
+			pr.fail(errBadBytecode)
+			return proto
+		}
+	}
+	proto.FunctionPrototypes = make([]*lua.FunctionProto, pr.len())
+	for i := range proto.FunctionPrototypes {
+		proto.FunctionPrototypes[i] = pr.proto()
+	}
+	proto.DbgSourcePositions = make([]int, pr.len())
+	for i := range proto.DbgSourcePositions {
+		proto.DbgSourcePositions[i] = pr.int()
+	}
+	proto.DbgLocals = make([]*lua.DbgLocalInfo, pr.len())
+	for i := range proto.DbgLocals {
+		proto.DbgLocals[i] = &lua.DbgLocalInfo{Name: pr.str(), StartPc: pr.int(), EndPc: pr.int()}
+	}
+	proto.DbgCalls = make([]lua.DbgCall, pr.len())
+	for i := range proto.DbgCalls {
+		proto.DbgCalls[i] = lua.DbgCall{Name: pr.str(), Pc: pr.int()}
+	}
+	proto.DbgUpvalues = make([]string, pr.len())
+	for i := range proto.DbgUpvalues {
+		proto.DbgUpvalues[i] = pr.str()
+	}
+	pr.fail(setStringConstants(proto))
+	return proto
+}
+
+// setStringConstants fills the unexported stringConstants of proto in the
+// way of lua.Compile, which is read by vm to get and set global variables.
+func setStringConstants(proto *lua.FunctionProto) error {
+	field := reflect.ValueOf(proto).Elem().FieldByName("stringConstants")
+	if !field.IsValid() || field.Type() != reflect.TypeOf([]string{}) {
+		return fmt.Errorf("%w: unsupported gopher-lua", errBadBytecode)
+	}
+	constants := make([]string, len(proto.Constants))
+	for i, constant := range proto.Constants {
+		if str, ok := constant.(lua.LString); ok {
+			constants[i] = string(str)
+		}
+	}
+	reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem().Set(reflect.ValueOf(constants))
+	return nil
+}
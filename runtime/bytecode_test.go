@@ -0,0 +1,70 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yockr
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestBytecodeCache(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "lib.lua")
+	os.WriteFile(script, []byte(`
+greeting = "hello"
+local function join(sep, ...)
+    local res = ""
+    for i, v in ipairs({ ... }) do
+        if i > 1 then res = res .. sep end
+        res = res .. tostring(v)
+    end
+    return res
+end
+result = join(", ", greeting, 1.5, true, nil == false)`), 0644)
+
+	cache := NewBytecodeCache(filepath.Join(dir, "cache"))
+	run := func() string {
+		proto, err := cache.CompileFile(script)
+		if err != nil {
+			t.Fatal(err)
+		}
+		l := lua.NewState()
+		defer l.Close()
+		if err = LuaDoFunc(l, l.NewFunctionFromProto(proto)); err != nil {
+			t.Fatal(err)
+		}
+		return l.GetGlobal("result").String()
+	}
+	if res := run(); res != "hello, 1.5, true, false" {
+		t.Fatal("unexpected result of compiled script", res)
+	}
+	files, _ := os.ReadDir(filepath.Join(dir, "cache"))
+	if len(files) != 1 {
+		t.Fatal("bytecode isn't cached", files)
+	}
+	source, _ := os.ReadFile(script)
+	proto, err := cache.Compile(script, source, func() (*lua.FunctionProto, error) {
+		t.Fatal("unchanged script is compiled again")
+		return nil, nil
+	})
+	if err != nil || proto.SourceName != script || len(proto.FunctionPrototypes) != 1 {
+		t.Fatal("invalid cached prototype", err)
+	}
+	if res := run(); res != "hello, 1.5, true, false" {
+		t.Fatal("unexpected result of cached script", res)
+	}
+
+	// the changed script overwrites the cache of old one
+	os.WriteFile(script, []byte(`result = "changed"`), 0644)
+	if res := run(); res != "changed" {
+		t.Fatal("changed script isn't compiled again", res)
+	}
+	if files, _ = os.ReadDir(filepath.Join(dir, "cache")); len(files) != 1 {
+		t.Fatal("stale bytecode is kept", files)
+	}
+}
@@ -0,0 +1,475 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yockr
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// DAPServer exposes the debugger over Debug Adapter Protocol, so that
+// editors can set breakpoints, inspect variables and step through script.
+//
+// Every state reaching the hook of debugger is a thread of DAP, such as
+// the state of job's context, and threads pause and resume independently.
+type DAPServer struct {
+	dbg *Debugger
+
+	wMut sync.Mutex
+	w    io.Writer
+	seq  int
+
+	mut     sync.Mutex
+	pauses  map[int]*dapPause
+	refs    map[int]*dapRef
+	nextRef int
+
+	configured     chan struct{}
+	configuredOnce sync.Once
+}
+
+// dapPause is a paused thread, which runs the works posted by
+// server in its own goroutine until it's resumed.
+type dapPause struct {
+	stop    *debugStop
+	frames  []stackFrame
+	work    chan func()
+	resume  chan stepMode
+	resumed chan struct{}
+}
+
+// do runs fn in the goroutine of paused thread, and reports
+// false when the thread has resumed before fn is run.
+func (p *dapPause) do(fn func()) bool {
+	done := make(chan struct{})
+	select {
+	case p.work <- func() { fn(); close(done) }:
+		<-done
+		return true
+	case <-p.resumed:
+		return false
+	}
+}
+
+// dapRef is the variables container referenced by client,
+// which is valid until its thread resumes.
+type dapRef struct {
+	thread int
+	vars   func() []debugVar
+}
+
+// NewDAPServer returns a server whose debugger doesn't pause
+// until client sets breakpoints or requests stopOnEntry.
+func NewDAPServer() *DAPServer {
+	srv := &DAPServer{
+		pauses:     make(map[int]*dapPause),
+		refs:       make(map[int]*dapRef),
+		configured: make(chan struct{}),
+	}
+	srv.dbg = newDebugger(srv)
+	return srv
+}
+
+// Debugger returns the debugger driven by server
+func (srv *DAPServer) Debugger() *Debugger {
+	return srv.dbg
+}
+
+// ListenAndServe waits for a client to connect to addr, and serves it in background
+func (srv *DAPServer) ListenAndServe(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer ln.Close()
+	conn, err := ln.Accept()
+	if err != nil {
+		return err
+	}
+	go srv.Serve(conn)
+	return nil
+}
+
+// WaitConfigured blocks until client finishes configuration
+// (configurationDone) or disconnects, and script should run after it.
+func (srv *DAPServer) WaitConfigured() {
+	<-srv.configured
+}
+
+// Terminate tells client that script has ended with exit code
+func (srv *DAPServer) Terminate(code int) {
+	srv.event("exited", map[string]any{"exitCode": code})
+	srv.event("terminated", nil)
+}
+
+type dapRequest struct {
+	Seq       int             `json:"seq"`
+	Command   string          `json:"command"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+type dapResponse struct {
+	Seq        int    `json:"seq"`
+	Type       string `json:"type"`
+	RequestSeq int    `json:"request_seq"`
+	Success    bool   `json:"success"`
+	Command    string `json:"command"`
+	Message    string `json:"message,omitempty"`
+	Body       any    `json:"body,omitempty"`
+}
+
+type dapEvent struct {
+	Seq   int    `json:"seq"`
+	Type  string `json:"type"`
+	Event string `json:"event"`
+	Body  any    `json:"body,omitempty"`
+}
+
+// Serve handles the requests from conn until it's closed or client disconnects.
+// The debugger is detached since then, and script runs to the end.
+func (srv *DAPServer) Serve(conn io.ReadWriteCloser) {
+	defer conn.Close()
+	defer srv.release()
+	srv.wMut.Lock()
+	srv.w = conn
+	srv.wMut.Unlock()
+	r := textproto.NewReader(bufio.NewReader(conn))
+	for {
+		header, err := r.ReadMIMEHeader()
+		if err != nil {
+			return
+		}
+		length, err := strconv.Atoi(header.Get("Content-Length"))
+		if err != nil {
+			return
+		}
+		raw := make([]byte, length)
+		if _, err = io.ReadFull(r.R, raw); err != nil {
+			return
+		}
+		req := dapRequest{}
+		if err = json.Unmarshal(raw, &req); err != nil {
+			return
+		}
+		body, err := srv.handle(req)
+		res := dapResponse{
+			Type:       "response",
+			RequestSeq: req.Seq,
+			Success:    err == nil,
+			Command:    req.Command,
+			Body:       body,
+		}
+		if err != nil {
+			res.Message = err.Error()
+		}
+		srv.send(&res.Seq, res)
+		switch req.Command {
+		case "initialize":
+			srv.event("initialized", nil)
+		case "disconnect":
+			return
+		}
+	}
+}
+
+// release detaches debugger and resumes all paused threads
+func (srv *DAPServer) release() {
+	srv.dbg.Detach()
+	srv.configuredOnce.Do(func() { close(srv.configured) })
+	srv.mut.Lock()
+	defer srv.mut.Unlock()
+	for id, p := range srv.pauses {
+		delete(srv.pauses, id)
+		p.resume <- stepContinue
+	}
+}
+
+func (srv *DAPServer) send(seq *int, msg any) {
+	srv.wMut.Lock()
+	defer srv.wMut.Unlock()
+	if srv.w == nil {
+		return
+	}
+	srv.seq++
+	*seq = srv.seq
+	raw, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(srv.w, "Content-Length: %d\r\n\r\n%s", len(raw), raw)
+}
+
+func (srv *DAPServer) event(name string, body any) {
+	e := dapEvent{Type: "event", Event: name, Body: body}
+	srv.send(&e.Seq, e)
+}
+
+func (srv *DAPServer) started(th *debugThread) {
+	srv.event("thread", map[string]any{"reason": "started", "threadId": th.id})
+}
+
+func (srv *DAPServer) paused(stop *debugStop) stepMode {
+	p := &dapPause{
+		stop:    stop,
+		frames:  stackFrames(stop.thread.l),
+		work:    make(chan func()),
+		resume:  make(chan stepMode, 1),
+		resumed: make(chan struct{}),
+	}
+	srv.mut.Lock()
+	srv.pauses[stop.thread.id] = p
+	srv.mut.Unlock()
+	srv.event("stopped", map[string]any{
+		"reason":            stop.reason,
+		"threadId":          stop.thread.id,
+		"allThreadsStopped": false,
+	})
+	for {
+		select {
+		case fn := <-p.work:
+			fn()
+		case mode := <-p.resume:
+			close(p.resumed)
+			return mode
+		}
+	}
+}
+
+// pausedThread returns the paused thread of id
+func (srv *DAPServer) pausedThread(id int) (*dapPause, error) {
+	srv.mut.Lock()
+	defer srv.mut.Unlock()
+	p, ok := srv.pauses[id]
+	if !ok {
+		return nil, fmt.Errorf("thread %d isn't paused", id)
+	}
+	return p, nil
+}
+
+// resume resumes the thread of id in mode, and the references of thread are released
+func (srv *DAPServer) resume(id int, mode stepMode) error {
+	srv.mut.Lock()
+	defer srv.mut.Unlock()
+	p, ok := srv.pauses[id]
+	if !ok {
+		return fmt.Errorf("thread %d isn't paused", id)
+	}
+	delete(srv.pauses, id)
+	for ref, r := range srv.refs {
+		if r.thread == id {
+			delete(srv.refs, ref)
+		}
+	}
+	p.resume <- mode
+	return nil
+}
+
+// frameOf parses the id of frame, which is composed of thread and index of frame
+func (srv *DAPServer) frameOf(id int) (*dapPause, *stackFrame, error) {
+	p, err := srv.pausedThread(id / 1000)
+	if err != nil {
+		return nil, nil, err
+	}
+	if idx := id % 1000; idx < len(p.frames) {
+		return p, &p.frames[idx], nil
+	}
+	return nil, nil, fmt.Errorf("invalid frame %d", id)
+}
+
+func (srv *DAPServer) newRef(thread int, vars func() []debugVar) int {
+	srv.mut.Lock()
+	defer srv.mut.Unlock()
+	srv.nextRef++
+	srv.refs[srv.nextRef] = &dapRef{thread: thread, vars: vars}
+	return srv.nextRef
+}
+
+// tableRef references table when v is table, otherwise returns 0
+func (srv *DAPServer) tableRef(thread int, v lua.LValue) int {
+	tbl, ok := v.(*lua.LTable)
+	if !ok {
+		return 0
+	}
+	return srv.newRef(thread, func() []debugVar {
+		vars := []debugVar{}
+		tbl.ForEach(func(key, value lua.LValue) {
+			name := key.String()
+			if _, ok := key.(lua.LString); !ok {
+				name = "[" + formatValue(key, 0) + "]"
+			}
+			vars = append(vars, debugVar{name: name, value: value})
+		})
+		sort.Slice(vars, func(i, j int) bool { return vars[i].name < vars[j].name })
+		return vars
+	})
+}
+
+type dapArguments struct {
+	ThreadID           int    `json:"threadId"`
+	FrameID            int    `json:"frameId"`
+	VariablesReference int    `json:"variablesReference"`
+	Expression         string `json:"expression"`
+	StopOnEntry        bool   `json:"stopOnEntry"`
+	Source             struct {
+		Path string `json:"path"`
+	} `json:"source"`
+	Breakpoints []struct {
+		Line int `json:"line"`
+	} `json:"breakpoints"`
+}
+
+func (srv *DAPServer) handle(req dapRequest) (any, error) {
+	args := dapArguments{}
+	if len(req.Arguments) > 0 {
+		if err := json.Unmarshal(req.Arguments, &args); err != nil {
+			return nil, err
+		}
+	}
+	switch req.Command {
+	case "initialize":
+		return map[string]any{
+			"supportsConfigurationDoneRequest": true,
+			"supportsEvaluateForHovers":        true,
+		}, nil
+	case "launch", "attach":
+		srv.dbg.entry.Store(args.StopOnEntry)
+		return nil, nil
+	case "setBreakpoints":
+		srv.dbg.ClearBreakpoints(args.Source.Path)
+		bps := []map[string]any{}
+		for _, bp := range args.Breakpoints {
+			srv.dbg.SetBreakpoint(args.Source.Path, bp.Line)
+			bps = append(bps, map[string]any{"verified": true, "line": bp.Line})
+		}
+		return map[string]any{"breakpoints": bps}, nil
+	case "setExceptionBreakpoints":
+		return map[string]any{"breakpoints": []any{}}, nil
+	case "configurationDone":
+		srv.configuredOnce.Do(func() { close(srv.configured) })
+		return nil, nil
+	case "threads":
+		threads := []map[string]any{}
+		for _, th := range srv.dbg.allThreads() {
+			threads = append(threads, map[string]any{"id": th.id, "name": th.name})
+		}
+		return map[string]any{"threads": threads}, nil
+	case "stackTrace":
+		p, err := srv.pausedThread(args.ThreadID)
+		if err != nil {
+			return nil, err
+		}
+		frames := []map[string]any{}
+		for i, f := range p.frames {
+			path, err := filepath.Abs(f.source)
+			if err != nil {
+				path = f.source
+			}
+			frames = append(frames, map[string]any{
+				"id":     args.ThreadID*1000 + i,
+				"name":   f.name,
+				"line":   f.line,
+				"column": 1,
+				"source": map[string]any{"name": filepath.Base(f.source), "path": path},
+			})
+		}
+		return map[string]any{"stackFrames": frames, "totalFrames": len(frames)}, nil
+	case "scopes":
+		p, f, err := srv.frameOf(args.FrameID)
+		if err != nil {
+			return nil, err
+		}
+		l, id := p.stop.thread.l, p.stop.thread.id
+		scopes := []map[string]any{
+			{"name": "Locals", "expensive": false, "variablesReference": srv.newRef(id, func() []debugVar {
+				return frameLocals(l, f.frame)
+			})},
+			{"name": "Upvalues", "expensive": false, "variablesReference": srv.newRef(id, func() []debugVar {
+				return frameUpvalues(l, f.frame)
+			})},
+		}
+		p.do(func() {
+			if ref := srv.tableRef(id, l.GetGlobal("env")); ref != 0 {
+				scopes = append(scopes, map[string]any{"name": "Env", "expensive": true, "variablesReference": ref})
+			}
+		})
+		return map[string]any{"scopes": scopes}, nil
+	case "variables":
+		srv.mut.Lock()
+		ref, ok := srv.refs[args.VariablesReference]
+		srv.mut.Unlock()
+		if !ok {
+			return nil, fmt.Errorf("invalid reference %d", args.VariablesReference)
+		}
+		p, err := srv.pausedThread(ref.thread)
+		if err != nil {
+			return nil, err
+		}
+		vars := []map[string]any{}
+		p.do(func() {
+			for _, v := range ref.vars() {
+				vars = append(vars, map[string]any{
+					"name":               v.name,
+					"value":              formatValue(v.value, 0),
+					"type":               v.value.Type().String(),
+					"variablesReference": srv.tableRef(ref.thread, v.value),
+				})
+			}
+		})
+		return map[string]any{"variables": vars}, nil
+	case "evaluate":
+		p, f, err := srv.frameOf(args.FrameID)
+		if err != nil {
+			return nil, err
+		}
+		var (
+			rets []lua.LValue
+			ref  int
+		)
+		p.do(func() {
+			rets, err = srv.dbg.eval(p.stop.thread.l, f.frame, args.Expression)
+			if err == nil && len(rets) == 1 {
+				ref = srv.tableRef(p.stop.thread.id, rets[0])
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+		strs := []string{}
+		for _, ret := range rets {
+			strs = append(strs, formatValue(ret, 1))
+		}
+		return map[string]any{"result": strings.Join(strs, "\t"), "variablesReference": ref}, nil
+	case "continue":
+		return map[string]any{"allThreadsContinued": false}, srv.resume(args.ThreadID, stepContinue)
+	case "next":
+		return nil, srv.resume(args.ThreadID, stepOver)
+	case "stepIn":
+		return nil, srv.resume(args.ThreadID, stepInto)
+	case "stepOut":
+		return nil, srv.resume(args.ThreadID, stepOut)
+	case "pause":
+		for _, th := range srv.dbg.allThreads() {
+			if th.id == args.ThreadID {
+				srv.dbg.pause(th)
+				return nil, nil
+			}
+		}
+		return nil, fmt.Errorf("invalid thread %d", args.ThreadID)
+	case "disconnect":
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unsupported command %s", req.Command)
+}
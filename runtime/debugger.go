@@ -0,0 +1,598 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yockr
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	yocki "github.com/ansurfen/yock/interface"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// DebugHookName is the global function called before every statement
+// of the script instrumented by yockp, which is how debugger takes control.
+const DebugHookName = "__yock_debug"
+
+type stepMode int
+
+const (
+	stepContinue stepMode = iota
+	// stepInto pauses at next statement
+	stepInto
+	// stepOver pauses at next statement in the same or outer function
+	stepOver
+	// stepOut pauses at next statement after the current function returns
+	stepOut
+)
+
+type stepState struct {
+	mode  stepMode
+	depth int
+}
+
+// the reasons why state pauses
+const (
+	stopEntry      = "entry"
+	stopBreakpoint = "breakpoint"
+	stopStep       = "step"
+	stopPause      = "pause"
+)
+
+// debugThread is a state which has run the instrumented script
+type debugThread struct {
+	id int
+	l  *lua.LState
+	// name is given by ThreadNamer, such as the task of state
+	name string
+}
+
+// debugStop describes where and why the state pauses
+type debugStop struct {
+	thread *debugThread
+	frame  *lua.Debug
+	depth  int
+	reason string
+}
+
+// debugFrontend interacts with user when state pauses
+type debugFrontend interface {
+	// paused is called in the goroutine of paused state,
+	// and blocks until user decides how to resume.
+	paused(stop *debugStop) stepMode
+	// started is called when a state reaches hook for the first time
+	started(th *debugThread)
+}
+
+// Debugger is a step debugger for instrumented scripts.
+//
+// Every state (main, job and goroutine) has its own stepping,
+// and is regarded as a thread of debugger. How to interact with
+// user is decided by frontend, such as console or DAP.
+type Debugger struct {
+	front debugFrontend
+
+	bpMut       sync.RWMutex
+	breakpoints map[string]map[int]bool
+
+	// steps records the states stepping, and the states without step run freely
+	steps sync.Map
+	// evaluating records the states running eval, whose hooks are ignored
+	evaluating sync.Map
+	// entry makes the next state reaching hook pause
+	entry atomic.Bool
+	// detached is set when frontend is gone, and script runs without pausing since then
+	detached atomic.Bool
+
+	thMut   sync.Mutex
+	threads map[*lua.LState]*debugThread
+	namer   func(l *lua.LState) string
+}
+
+// NewDebugger returns a debugger interacting with user by console,
+// which pauses at the first statement of script.
+func NewDebugger(in io.Reader, out io.Writer) *Debugger {
+	dbg := newDebugger(nil)
+	dbg.front = &consoleFrontend{
+		dbg:     dbg,
+		in:      bufio.NewScanner(in),
+		out:     out,
+		sources: make(map[string][]string),
+	}
+	dbg.entry.Store(true)
+	return dbg
+}
+
+func newDebugger(front debugFrontend) *Debugger {
+	return &Debugger{
+		front:       front,
+		breakpoints: make(map[string]map[int]bool),
+		threads:     make(map[*lua.LState]*debugThread),
+	}
+}
+
+// Attach registers the hook of debugger into state, and the states
+// created by NewState share it because of the same globals.
+func (dbg *Debugger) Attach(s yocki.YockState) {
+	s.LState().SetGlobal(DebugHookName, s.LState().NewFunction(dbg.hook))
+}
+
+// SetThreadNamer decides the name of thread by its state, for example,
+// the scheduler names the state of job by its task.
+func (dbg *Debugger) SetThreadNamer(namer func(l *lua.LState) string) {
+	dbg.namer = namer
+}
+
+// SetBreakpoint sets a breakpoint at line of file
+func (dbg *Debugger) SetBreakpoint(file string, line int) {
+	dbg.bpMut.Lock()
+	defer dbg.bpMut.Unlock()
+	file = filepath.Clean(file)
+	if _, ok := dbg.breakpoints[file]; !ok {
+		dbg.breakpoints[file] = make(map[int]bool)
+	}
+	dbg.breakpoints[file][line] = true
+}
+
+// ClearBreakpoint removes the breakpoint at line of file, and reports whether it exists
+func (dbg *Debugger) ClearBreakpoint(file string, line int) bool {
+	dbg.bpMut.Lock()
+	defer dbg.bpMut.Unlock()
+	file = filepath.Clean(file)
+	if lines, ok := dbg.breakpoints[file]; ok && lines[line] {
+		delete(lines, line)
+		return true
+	}
+	return false
+}
+
+// ClearBreakpoints removes all breakpoints of file
+func (dbg *Debugger) ClearBreakpoints(file string) {
+	dbg.bpMut.Lock()
+	defer dbg.bpMut.Unlock()
+	delete(dbg.breakpoints, filepath.Clean(file))
+}
+
+// Detach lets script run to the end without pausing
+func (dbg *Debugger) Detach() {
+	dbg.detached.Store(true)
+}
+
+// hit reports whether a breakpoint is set at line of source.
+// The file of breakpoint can be the suffix of source, such as
+// main.lua for ./scripts/main.lua
+func (dbg *Debugger) hit(source string, line int) bool {
+	dbg.bpMut.RLock()
+	defer dbg.bpMut.RUnlock()
+	source = filepath.Clean(source)
+	for file, lines := range dbg.breakpoints {
+		if lines[line] && (source == file || strings.HasSuffix(source, string(filepath.Separator)+file) ||
+			(filepath.IsAbs(file) && sameFile(source, file))) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameFile(a, b string) bool {
+	abs, err := filepath.Abs(a)
+	return err == nil && abs == b
+}
+
+// thread returns the thread of state, and registers it when it's first seen
+func (dbg *Debugger) thread(l *lua.LState) *debugThread {
+	dbg.thMut.Lock()
+	th, ok := dbg.threads[l]
+	if !ok {
+		th = &debugThread{id: len(dbg.threads) + 1, l: l}
+		dbg.threads[l] = th
+	}
+	if dbg.namer != nil {
+		th.name = dbg.namer(l)
+	}
+	if len(th.name) == 0 {
+		th.name = fmt.Sprintf("thread %d", th.id)
+	}
+	dbg.thMut.Unlock()
+	if !ok {
+		dbg.front.started(th)
+	}
+	return th
+}
+
+// allThreads returns the threads in order of id
+func (dbg *Debugger) allThreads() []*debugThread {
+	dbg.thMut.Lock()
+	defer dbg.thMut.Unlock()
+	res := make([]*debugThread, 0, len(dbg.threads))
+	for _, th := range dbg.threads {
+		res = append(res, th)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].id < res[j].id })
+	return res
+}
+
+// pause makes the thread pause at its next statement
+func (dbg *Debugger) pause(th *debugThread) {
+	dbg.steps.Store(th.l, &stepState{mode: stepInto})
+}
+
+func (dbg *Debugger) hook(l *lua.LState) int {
+	if dbg.detached.Load() {
+		return 0
+	}
+	if _, ok := dbg.evaluating.Load(l); ok {
+		return 0
+	}
+	frame, ok := l.GetStack(1)
+	if !ok {
+		return 0
+	}
+	if _, err := l.GetInfo("Sl", frame, lua.LNil); err != nil {
+		return 0
+	}
+	th := dbg.thread(l)
+	depth := stackDepth(l)
+	reason := ""
+	switch {
+	case dbg.entry.CompareAndSwap(true, false):
+		reason = stopEntry
+	case dbg.hit(frame.Source, frame.CurrentLine):
+		reason = stopBreakpoint
+	default:
+		if v, ok := dbg.steps.Load(l); ok {
+			step := v.(*stepState)
+			switch {
+			case step.mode == stepInto && step.depth == 0:
+				reason = stopPause
+			case step.mode == stepInto,
+				step.mode == stepOver && depth <= step.depth,
+				step.mode == stepOut && depth < step.depth:
+				reason = stopStep
+			}
+		}
+	}
+	if len(reason) == 0 {
+		return 0
+	}
+	mode := dbg.front.paused(&debugStop{
+		thread: th,
+		frame:  frame,
+		depth:  depth,
+		reason: reason,
+	})
+	if mode == stepContinue {
+		dbg.steps.Delete(l)
+	} else {
+		dbg.steps.Store(l, &stepState{mode: mode, depth: depth})
+	}
+	return 0
+}
+
+// eval runs code in the scope of frame, that the locals and upvalues are
+// visible, and the assignments to locals are written back after running.
+func (dbg *Debugger) eval(l *lua.LState, frame *lua.Debug, code string) ([]lua.LValue, error) {
+	env := l.NewTable()
+	mt := l.NewTable()
+	mt.RawSetString("__index", l.Get(lua.GlobalsIndex))
+	env.Metatable = mt
+	upvalues := frameUpvalues(l, frame)
+	locals := frameLocals(l, frame)
+	for _, vars := range [][]debugVar{upvalues, locals} {
+		for _, v := range vars {
+			env.RawSetString(v.name, v.value)
+		}
+	}
+	fn, err := l.LoadString("return " + code)
+	if err != nil {
+		fn, err = l.LoadString(code)
+	}
+	if err != nil {
+		return nil, err
+	}
+	fn.Env = env
+	dbg.evaluating.Store(l, true)
+	defer dbg.evaluating.Delete(l)
+	top := l.GetTop()
+	if err = l.CallByParam(lua.P{Fn: fn, NRet: lua.MultRet, Protect: true}); err != nil {
+		return nil, err
+	}
+	rets := []lua.LValue{}
+	for i := top + 1; i <= l.GetTop(); i++ {
+		rets = append(rets, l.Get(i))
+	}
+	l.SetTop(top)
+	for _, v := range locals {
+		if nv := env.RawGetString(v.name); nv != v.value {
+			l.SetLocal(frame, v.index, nv)
+		}
+	}
+	return rets, nil
+}
+
+// consoleFrontend interacts with user by reading commands line by line.
+// Only one state can be paused at a time, and others reaching breakpoints
+// are blocked until the prompt is released.
+type consoleFrontend struct {
+	dbg *Debugger
+	// mut serializes the prompts of states, and guards the fields used by prompt
+	mut sync.Mutex
+	in  *bufio.Scanner
+	out io.Writer
+	// sources caches the lines of files to print
+	sources map[string][]string
+}
+
+func (c *consoleFrontend) started(th *debugThread) {}
+
+func (c *consoleFrontend) paused(stop *debugStop) stepMode {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	l, frame := stop.thread.l, stop.frame
+	c.printLocation(stop)
+	for {
+		fmt.Fprint(c.out, "(yock) ")
+		if !c.in.Scan() {
+			// there is no more input, so let script run to the end
+			c.dbg.Detach()
+			return stepContinue
+		}
+		cmd, arg, _ := strings.Cut(strings.TrimSpace(c.in.Text()), " ")
+		arg = strings.TrimSpace(arg)
+		switch cmd {
+		case "c", "continue":
+			return stepContinue
+		case "n", "next":
+			return stepOver
+		case "s", "step":
+			return stepInto
+		case "o", "out":
+			return stepOut
+		case "b", "break":
+			if file, line, ok := c.parseLocation(arg, frame.Source); ok {
+				c.dbg.SetBreakpoint(file, line)
+				fmt.Fprintf(c.out, "breakpoint at %s:%d\n", file, line)
+			}
+		case "d", "delete":
+			if file, line, ok := c.parseLocation(arg, frame.Source); ok {
+				if !c.dbg.ClearBreakpoint(file, line) {
+					fmt.Fprintf(c.out, "no breakpoint at %s:%d\n", file, line)
+				}
+			}
+		case "bl", "breakpoints":
+			c.printBreakpoints()
+		case "l", "locals":
+			for _, v := range frameLocals(l, frame) {
+				fmt.Fprintf(c.out, "%s = %s\n", v.name, formatValue(v.value, 2))
+			}
+		case "u", "upvalues":
+			for _, v := range frameUpvalues(l, frame) {
+				fmt.Fprintf(c.out, "%s = %s\n", v.name, formatValue(v.value, 2))
+			}
+		case "bt", "backtrace":
+			fmt.Fprintln(c.out, "stack traceback:")
+			for _, f := range stackFrames(l) {
+				fmt.Fprintf(c.out, "\t%s:%d: in %s\n", f.source, f.line, f.name)
+			}
+		case "p", "eval":
+			if len(arg) == 0 {
+				continue
+			}
+			rets, err := c.dbg.eval(l, frame, arg)
+			if err != nil {
+				fmt.Fprintln(c.out, err)
+				continue
+			}
+			if len(rets) > 0 {
+				strs := []string{}
+				for _, ret := range rets {
+					strs = append(strs, formatValue(ret, 2))
+				}
+				fmt.Fprintln(c.out, strings.Join(strs, "\t"))
+			}
+		case "where":
+			c.printLocation(stop)
+		case "q", "quit":
+			fmt.Fprintln(c.out, "quit")
+			os.Exit(1)
+		case "h", "help":
+			fmt.Fprint(c.out, debuggerHelp)
+		case "":
+		default:
+			fmt.Fprintf(c.out, "unknown command %s, type h for help\n", cmd)
+		}
+	}
+}
+
+const debuggerHelp = `c, continue         run until next breakpoint
+n, next             step over, pause at next line of current function
+s, step             step into, pause at next line
+o, out              step out, pause after current function returns
+b, break [file:]line     set breakpoint, file defaults to current
+d, delete [file:]line    remove breakpoint
+bl, breakpoints     list breakpoints
+l, locals           print local variables
+u, upvalues         print upvalues of current function
+bt, backtrace       print call stack
+p, eval expr        evaluate expression or statement with locals
+where               print current location
+q, quit             abort script
+`
+
+func (c *consoleFrontend) parseLocation(loc, current string) (string, int, bool) {
+	file, lineStr := current, loc
+	if i := strings.LastIndex(loc, ":"); i >= 0 {
+		file, lineStr = loc[:i], loc[i+1:]
+	}
+	line, err := strconv.Atoi(lineStr)
+	if err != nil || line <= 0 {
+		fmt.Fprintf(c.out, "invalid location %s\n", loc)
+		return "", 0, false
+	}
+	return file, line, true
+}
+
+func (c *consoleFrontend) printBreakpoints() {
+	c.dbg.bpMut.RLock()
+	defer c.dbg.bpMut.RUnlock()
+	locs := []string{}
+	for file, lines := range c.dbg.breakpoints {
+		for line := range lines {
+			locs = append(locs, fmt.Sprintf("%s:%d", file, line))
+		}
+	}
+	sort.Strings(locs)
+	for _, loc := range locs {
+		fmt.Fprintln(c.out, loc)
+	}
+}
+
+func (c *consoleFrontend) printLocation(stop *debugStop) {
+	fmt.Fprintf(c.out, "[%s] %s:%d\n", stop.thread.name, stop.frame.Source, stop.frame.CurrentLine)
+	if line := c.sourceLine(stop.frame.Source, stop.frame.CurrentLine); len(line) > 0 {
+		fmt.Fprintf(c.out, "%5d\t%s\n", stop.frame.CurrentLine, line)
+	}
+}
+
+// sourceLine returns the content of line in file, and the file is cached after read
+func (c *consoleFrontend) sourceLine(file string, line int) string {
+	lines, ok := c.sources[file]
+	if !ok {
+		raw, err := os.ReadFile(file)
+		if err == nil {
+			lines = strings.Split(strings.ReplaceAll(string(raw), "\r\n", "\n"), "\n")
+		}
+		c.sources[file] = lines
+	}
+	if line <= 0 || line > len(lines) {
+		return ""
+	}
+	return lines[line-1]
+}
+
+type debugVar struct {
+	name  string
+	value lua.LValue
+	// index is the position of local in frame
+	index int
+}
+
+// frameLocals returns the active locals of frame, and the temporaries are ignored
+func frameLocals(l *lua.LState, frame *lua.Debug) []debugVar {
+	vars := []debugVar{}
+	for i := 1; ; i++ {
+		name, value := l.GetLocal(frame, i)
+		if len(name) == 0 {
+			break
+		}
+		if strings.HasPrefix(name, "(") {
+			continue
+		}
+		vars = append(vars, debugVar{name: name, value: value, index: i})
+	}
+	return vars
+}
+
+func frameUpvalues(l *lua.LState, frame *lua.Debug) []debugVar {
+	vars := []debugVar{}
+	fn, err := l.GetInfo("f", frame, lua.LNil)
+	if err != nil {
+		return vars
+	}
+	lfn, ok := fn.(*lua.LFunction)
+	if !ok {
+		return vars
+	}
+	for i := 1; i <= len(lfn.Upvalues); i++ {
+		name, value := l.GetUpvalue(lfn, i)
+		vars = append(vars, debugVar{name: name, value: value, index: i})
+	}
+	return vars
+}
+
+// stackDepth returns the number of frames in state
+func stackDepth(l *lua.LState) int {
+	depth := 0
+	for {
+		if _, ok := l.GetStack(depth); !ok {
+			return depth
+		}
+		depth++
+	}
+}
+
+type stackFrame struct {
+	// level is the level of frame for GetStack
+	level  int
+	name   string
+	source string
+	line   int
+	frame  *lua.Debug
+}
+
+// stackFrames returns the lua frames of state from top to bottom,
+// and the hook of debugger and go functions are skipped.
+func stackFrames(l *lua.LState) []stackFrame {
+	frames := []stackFrame{}
+	for i := 1; ; i++ {
+		frame, ok := l.GetStack(i)
+		if !ok {
+			break
+		}
+		if _, err := l.GetInfo("Sln", frame, lua.LNil); err != nil {
+			break
+		}
+		if frame.What == "G" {
+			continue
+		}
+		name := frame.Name
+		if len(name) == 0 {
+			name = "?"
+			if frame.What == "main" {
+				name = "main chunk"
+			}
+		}
+		frames = append(frames, stackFrame{
+			level:  i,
+			name:   name,
+			source: frame.Source,
+			line:   frame.CurrentLine,
+			frame:  frame,
+		})
+	}
+	return frames
+}
+
+// formatValue formats v in form of lua, and tables are expanded up to depth
+func formatValue(v lua.LValue, depth int) string {
+	switch v := v.(type) {
+	case lua.LString:
+		return strconv.Quote(string(v))
+	case *lua.LTable:
+		if depth <= 0 {
+			return v.String()
+		}
+		fields := []string{}
+		v.ForEach(func(key, value lua.LValue) {
+			k := key.String()
+			if _, ok := key.(lua.LString); !ok {
+				k = "[" + formatValue(key, 0) + "]"
+			}
+			fields = append(fields, k+" = "+formatValue(value, depth-1))
+		})
+		sort.Strings(fields)
+		return "{ " + strings.Join(fields, ", ") + " }"
+	default:
+		return v.String()
+	}
+}
@@ -14,7 +14,8 @@ import (
 
 // New initialize yock runtime and returns the its pointer
 func New(opts ...YockrOption) yocki.YockRuntime {
-	var yockr yocki.YockRuntime = &YockInterp{state: NewYState()}
+	interp := &YockInterp{state: NewYState()}
+	var yockr yocki.YockRuntime = interp
 
 	for _, opt := range opts {
 		if err := opt(yockr); err != nil {
@@ -30,6 +31,11 @@ func New(opts ...YockrOption) yocki.YockRuntime {
 		yockr.SetState(s)
 	}
 
+	if interp.limits.cancelable() {
+		interp.limits.watch()
+		interp.limits.bind(interp.state.LState())
+	}
+
 	return yockr
 }
 
@@ -39,7 +45,8 @@ type YockrOption func(yockr yocki.YockRuntime) error
 
 // YockInterp abstracts lua interpreter
 type YockInterp struct {
-	state yocki.YockState
+	state  yocki.YockState
+	limits limits
 }
 
 // State returns LState
@@ -51,18 +58,26 @@ func (yockr *YockInterp) SetState(l yocki.YockState) {
 	yockr.state = l
 }
 
+// NewState returns the thread of state. It has own budget of time when
+// timeout is limited, rather than sharing the one of parent.
 func (yockr *YockInterp) NewState() (yocki.YockState, context.CancelFunc) {
 	ls, cancel := yockr.state.LState().NewThread()
+	if yockr.limits.cancelable() {
+		if cancel != nil {
+			cancel()
+		}
+		cancel = yockr.limits.bind(ls)
+	}
 	return UpgradeLState(ls), cancel
 }
 
 // FastCall to call specify function without arguments and not return value
 func (yockr *YockInterp) FastCall(fun string) error {
-	return yockr.state.LState().CallByParam(lua.P{
+	return limitError(yockr.state.LState(), yockr.state.LState().CallByParam(lua.P{
 		Fn:      yockr.state.LState().GetGlobal(fun),
 		NRet:    0,
 		Protect: true,
-	})
+	}))
 }
 
 // Call to call specify function without arguments
@@ -73,7 +88,7 @@ func (yockr *YockInterp) Call(fun string) ([]lua.LValue, error) {
 		NRet:    0,
 		Protect: true,
 	}); err != nil {
-		return ret, err
+		return ret, limitError(yockr.state.LState(), err)
 	}
 	for i := 1; i <= yockr.state.LState().GetTop(); i++ {
 		ret = append(ret, yockr.state.LState().CheckAny(i))
@@ -99,13 +114,13 @@ func (yockr *YockInterp) SafeSetGlobalFn(loaders map[string]lua.LGFunction) {
 
 // Eval to execute string of script
 func (yockr *YockInterp) Eval(script string) error {
-	return yockr.state.LState().DoString(script)
+	return limitError(yockr.state.LState(), yockr.state.LState().DoString(script))
 }
 
 // EvalFile to execute file of script
 func (yockr *YockInterp) EvalFile(fullpath string) error {
 	if filepath.Ext(fullpath) == ".lua" {
-		return yockr.state.LState().DoFile(fullpath)
+		return limitError(yockr.state.LState(), yockr.state.LState().DoFile(fullpath))
 	}
 	return nil
 }
@@ -117,7 +132,7 @@ func (yockr *YockInterp) EvalFunc(fn lua.LValue, args []lua.LValue) ([]lua.LValu
 		Fn:      fn,
 		Protect: true,
 	}, args...); err != nil {
-		return ret, err
+		return ret, limitError(yockr.state.LState(), err)
 	}
 	for i := 1; i <= yockr.state.LState().GetTop(); i++ {
 		ret = append(ret, yockr.state.LState().CheckAny(i))
@@ -127,10 +142,10 @@ func (yockr *YockInterp) EvalFunc(fn lua.LValue, args []lua.LValue) ([]lua.LValu
 
 // FastEvalFunc to execute function and not return value
 func (yockr *YockInterp) FastEvalFunc(fn lua.LValue, args []lua.LValue) error {
-	return yockr.state.LState().CallByParam(lua.P{
+	return limitError(yockr.state.LState(), yockr.state.LState().CallByParam(lua.P{
 		Fn:      fn,
 		Protect: true,
-	}, args...)
+	}, args...))
 }
 
 // GetGlobalVar returns global variable
@@ -0,0 +1,150 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yockr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/metrics"
+	"strconv"
+	"strings"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// The limits of state reported by LimitError
+const (
+	LimitCallStack = "call stack"
+	LimitRegistry  = "registry"
+	LimitMemory    = "memory"
+	LimitTimeout   = "timeout"
+)
+
+// LimitError is returned when the state runs beyond the limit of runtime,
+// and Err is the lua error raised by the violation with stack trace.
+type LimitError struct {
+	Limit string
+	Max   string
+	Err   error
+}
+
+func (err *LimitError) Error() string {
+	msg := fmt.Sprintf("exceed %s limit %s", err.Limit, err.Max)
+	if err.Err != nil {
+		msg += ": " + err.Err.Error()
+	}
+	return msg
+}
+
+func (err *LimitError) Unwrap() error {
+	return err.Err
+}
+
+// memoryMetric is the bytes of heap occupied by live and unswept objects
+const memoryMetric = "/memory/classes/heap/objects:bytes"
+
+// memoryInterval is how often the heap is sampled when memory is limited
+var memoryInterval = 100 * time.Millisecond
+
+// limits is shared by the states of runtime. The size of call stack and
+// registry are set into lua.Options. The timeout and memory are enforced
+// by cancelling the context of states, since the vm checks it between
+// instructions.
+type limits struct {
+	callStackSize   int
+	registrySize    int
+	registryMaxSize int
+	maxMemory       uint64
+	timeout         time.Duration
+
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+}
+
+func (lim *limits) options() lua.Options {
+	return lua.Options{
+		CallStackSize:   lim.callStackSize,
+		RegistrySize:    lim.registrySize,
+		RegistryMaxSize: lim.registryMaxSize,
+	}
+}
+
+// cancelable reports whether states need context to be cancelled
+func (lim *limits) cancelable() bool {
+	return lim.maxMemory > 0 || lim.timeout > 0
+}
+
+// watch starts to sample heap when memory is limited, and all states
+// are cancelled once the heap of process exceeds maxMemory.
+func (lim *limits) watch() {
+	lim.ctx, lim.cancel = context.WithCancelCause(context.Background())
+	if lim.maxMemory == 0 {
+		return
+	}
+	go func() {
+		sample := []metrics.Sample{{Name: memoryMetric}}
+		ticker := time.NewTicker(memoryInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-lim.ctx.Done():
+				return
+			case <-ticker.C:
+				metrics.Read(sample)
+				if sample[0].Value.Kind() == metrics.KindUint64 && sample[0].Value.Uint64() > lim.maxMemory {
+					lim.cancel(&LimitError{Limit: LimitMemory, Max: strconv.FormatUint(lim.maxMemory, 10) + "B"})
+					return
+				}
+			}
+		}
+	}()
+}
+
+// bind sets the context of l, which is cancelled after timeout
+// since it's bound, and returns the function to release it.
+func (lim *limits) bind(l *lua.LState) context.CancelFunc {
+	ctx, cancel := context.WithCancelCause(lim.ctx)
+	stop := func() bool { return false }
+	if lim.timeout > 0 {
+		stop = time.AfterFunc(lim.timeout, func() {
+			cancel(&LimitError{Limit: LimitTimeout, Max: lim.timeout.String()})
+		}).Stop
+	}
+	l.SetContext(ctx)
+	return func() {
+		stop()
+		cancel(context.Canceled)
+	}
+}
+
+// limitError converts err raised by the violation of limits into *LimitError
+func limitError(l *lua.LState, err error) error {
+	if err == nil {
+		return nil
+	}
+	limit := &LimitError{}
+	if errors.As(err, &limit) {
+		return err
+	}
+	if ctx := l.Context(); ctx != nil {
+		if errors.As(context.Cause(ctx), &limit) {
+			return &LimitError{Limit: limit.Limit, Max: limit.Max, Err: err}
+		}
+	}
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "stack overflow"):
+		return &LimitError{Limit: LimitCallStack, Max: strconv.Itoa(l.Options.CallStackSize), Err: err}
+	case strings.Contains(msg, "registry overflow"):
+		max := l.Options.RegistryMaxSize
+		if max == 0 {
+			max = l.Options.RegistrySize
+		}
+		return &LimitError{Limit: LimitRegistry, Max: strconv.Itoa(max), Err: err}
+	}
+	return err
+}
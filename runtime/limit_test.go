@@ -0,0 +1,51 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yockr
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	yocki "github.com/ansurfen/yock/interface"
+)
+
+func TestLimits(t *testing.T) {
+	expect := func(err error, limit string) {
+		t.Helper()
+		le := &LimitError{}
+		if !errors.As(err, &le) || le.Limit != limit {
+			t.Fatalf("expect %s limit, but got %v", limit, err)
+		}
+	}
+
+	yockr := New(OptionCallStackSize(64), OptionRegistrySize(256, 1024))
+	expect(yockr.Eval(`local function f() return 1 + f() end f()`), LimitCallStack)
+	expect(yockr.Eval(`local function f(...) return f(1, 2, 3, 4, ...) end f()`), LimitRegistry)
+	if err := yockr.Eval(`local function f(n) if n > 0 then return f(n - 1) + 1 end return 0 end f(32)`); err != nil {
+		t.Fatal(err)
+	}
+
+	yockr = New(OptionTimeout(50 * time.Millisecond))
+	yockr.Eval(`function spin() while true do end end`)
+	s, cancel := yockr.NewState()
+	start := time.Now()
+	expect(s.Call(yocki.YockFuncInfo{Fn: s.LState().GetGlobal("spin"), Protect: true}), LimitTimeout)
+	cancel()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatal("timeout isn't enforced in time", elapsed)
+	}
+	// the thread created later has own budget
+	time.Sleep(60 * time.Millisecond)
+	s, cancel = yockr.NewState()
+	defer cancel()
+	if err := s.LState().DoString(`x = 1`); err != nil {
+		t.Fatal(err)
+	}
+
+	memoryInterval = time.Millisecond
+	yockr = New(OptionMaxMemory(1))
+	expect(yockr.Eval(`local t = {} while true do t[#t + 1] = {} end`), LimitMemory)
+}
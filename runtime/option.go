@@ -5,10 +5,15 @@
 package yockr
 
 import (
+	"errors"
+	"time"
+
 	yocki "github.com/ansurfen/yock/interface"
 	lua "github.com/yuin/gopher-lua"
 )
 
+var errUnlimitedRuntime = errors.New("limits are only supported by YockInterp")
+
 func OptionLState(opt lua.Options) YockrOption {
 	return func(yockr yocki.YockRuntime) error {
 		state := lua.NewState(opt)
@@ -23,3 +28,61 @@ func OptionEnableInterpPool() YockrOption {
 		return nil
 	}
 }
+
+// OptionCallStackSize limits the depth of calls in state. It creates the
+// state again, so that it should be given before the options changing state.
+func OptionCallStackSize(size int) YockrOption {
+	return func(yockr yocki.YockRuntime) error {
+		interp, ok := yockr.(*YockInterp)
+		if !ok {
+			return errUnlimitedRuntime
+		}
+		interp.limits.callStackSize = size
+		interp.SetState(UpgradeLState(lua.NewState(interp.limits.options())))
+		return nil
+	}
+}
+
+// OptionRegistrySize limits the data stack of state, which starts with
+// size and grows up to max. Same as OptionCallStackSize, it creates the
+// state again.
+func OptionRegistrySize(size, max int) YockrOption {
+	return func(yockr yocki.YockRuntime) error {
+		interp, ok := yockr.(*YockInterp)
+		if !ok {
+			return errUnlimitedRuntime
+		}
+		interp.limits.registrySize = size
+		interp.limits.registryMaxSize = max
+		interp.SetState(UpgradeLState(lua.NewState(interp.limits.options())))
+		return nil
+	}
+}
+
+// OptionMaxMemory cancels all states once the heap of process exceeds
+// bytes. The memory of go can't be accounted by state, so that it acts
+// as the ceiling of whole runtime.
+func OptionMaxMemory(bytes uint64) YockrOption {
+	return func(yockr yocki.YockRuntime) error {
+		interp, ok := yockr.(*YockInterp)
+		if !ok {
+			return errUnlimitedRuntime
+		}
+		interp.limits.maxMemory = bytes
+		return nil
+	}
+}
+
+// OptionTimeout gives each state the budget of wall clock, and the state
+// running longer than timeout is cancelled with *LimitError. The states
+// created by NewState count their own time from creation.
+func OptionTimeout(timeout time.Duration) YockrOption {
+	return func(yockr yocki.YockRuntime) error {
+		interp, ok := yockr.(*YockInterp)
+		if !ok {
+			return errUnlimitedRuntime
+		}
+		interp.limits.timeout = timeout
+		return nil
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yockr
+
+import (
+	"io"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+const outputKey = "__yock_output"
+
+// SetOutput makes l and the threads of it write what's printed by script into w
+// instead of stdout, such as the output of print, echo, sh and ycho.
+func SetOutput(l *lua.LState, w io.Writer) {
+	ud := l.NewUserData()
+	ud.Value = w
+	l.G.Registry.RawSetString(outputKey, ud)
+}
+
+// OutputOf returns the writer set by SetOutput, and it's nil when l prints to stdout.
+func OutputOf(l *lua.LState) io.Writer {
+	if ud, ok := l.G.Registry.RawGetString(outputKey).(*lua.LUserData); ok {
+		w, _ := ud.Value.(io.Writer)
+		return w
+	}
+	return nil
+}
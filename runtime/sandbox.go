@@ -0,0 +1,361 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yockr
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	yocki "github.com/ansurfen/yock/interface"
+	lua "github.com/yuin/gopher-lua"
+	"gopkg.in/yaml.v3"
+)
+
+// The capabilities granted by sandbox. Each of them can be narrowed by
+// a scope after colon, such as fs:write:./build, net:github.com and exec:git.
+const (
+	// CapFSRead reads files under the directory of scope
+	CapFSRead = "fs:read"
+	// CapFSWrite creates, changes and removes files under the directory
+	// of scope, and it implies CapFSRead of the same directory.
+	CapFSWrite = "fs:write"
+	// CapNet connects the host of scope and its subdomains
+	CapNet = "net"
+	// CapExec executes the program of scope. The command is run by shell,
+	// so the one with shell metacharacters requires CapExec without scope.
+	CapExec = "exec"
+	// CapEnv reads and writes the environment variable of scope
+	CapEnv = "env"
+	// CapFFI loads the dynamic library of scope
+	CapFFI = "ffi"
+)
+
+var sandboxCaps = []string{CapFSRead, CapFSWrite, CapNet, CapExec, CapEnv, CapFFI}
+
+// shellMetachars are the characters which make shell run more than the program of command
+const shellMetachars = ";&|$`><\n\r"
+
+// SandboxError is raised when script acts beyond the capabilities of sandbox
+type SandboxError struct {
+	Profile string
+	Cap     string
+	Target  string
+}
+
+func (err *SandboxError) Error() string {
+	msg := "sandbox"
+	if len(err.Profile) > 0 {
+		msg += " " + err.Profile
+	}
+	msg += " denies " + err.Cap
+	if len(err.Target) > 0 {
+		msg += " " + err.Target
+	}
+	return msg
+}
+
+type sandboxGrant struct {
+	// all is true when the capability is granted without scope
+	all    bool
+	scopes []string
+}
+
+// Sandbox is the capability profile of untrusted scripts, and anything
+// not granted explicitly is denied.
+type Sandbox struct {
+	Name   string
+	grants map[string]*sandboxGrant
+}
+
+// NewSandbox returns the sandbox granting caps, such as fs:read and exec:git.
+// The relative directory of fs scope is taken from working directory.
+func NewSandbox(name string, caps ...string) (*Sandbox, error) {
+	sb := &Sandbox{Name: name, grants: make(map[string]*sandboxGrant)}
+	for _, c := range caps {
+		if err := sb.grant(strings.TrimSpace(c)); err != nil {
+			return nil, err
+		}
+	}
+	return sb, nil
+}
+
+type sandboxProfile struct {
+	Name         string   `yaml:"name"`
+	Capabilities []string `yaml:"capabilities"`
+}
+
+// LoadSandbox reads the sandbox from profile in form of yaml, e.g.
+//
+//	name: third-party
+//	capabilities:
+//	  - fs:read
+//	  - fs:write:./build
+//	  - net:github.com
+func LoadSandbox(file string) (*Sandbox, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	profile := sandboxProfile{}
+	if err = yaml.Unmarshal(raw, &profile); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	if len(profile.Name) == 0 {
+		profile.Name = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	}
+	return NewSandbox(profile.Name, profile.Capabilities...)
+}
+
+func (sb *Sandbox) grant(c string) error {
+	for _, name := range sandboxCaps {
+		if c != name && !strings.HasPrefix(c, name+":") {
+			continue
+		}
+		g, ok := sb.grants[name]
+		if !ok {
+			g = &sandboxGrant{}
+			sb.grants[name] = g
+		}
+		scope := strings.TrimPrefix(strings.TrimPrefix(c, name), ":")
+		if len(scope) == 0 {
+			g.all = true
+			return nil
+		}
+		if name == CapFSRead || name == CapFSWrite {
+			abs, err := filepath.Abs(scope)
+			if err != nil {
+				return err
+			}
+			scope = realPath(abs)
+		}
+		g.scopes = append(g.scopes, scope)
+		return nil
+	}
+	return fmt.Errorf("invalid capability %q", c)
+}
+
+// Granted reports whether the capability is granted without scope, which is
+// required by the bindings can't be checked call by call.
+func (sb *Sandbox) Granted(c string) bool {
+	if sb == nil {
+		return true
+	}
+	if c == CapFSRead && sb.Granted(CapFSWrite) {
+		return true
+	}
+	g, ok := sb.grants[c]
+	return ok && g.all
+}
+
+// Check returns *SandboxError when the capability isn't granted on target.
+// The target is the path for fs, url or host for net, command for exec,
+// variable name for env and library for ffi. Nil sandbox allows everything.
+func (sb *Sandbox) Check(c, target string) error {
+	if sb == nil || sb.allow(c, target) {
+		return nil
+	}
+	return &SandboxError{Profile: sb.Name, Cap: c, Target: target}
+}
+
+func (sb *Sandbox) allow(c, target string) bool {
+	if c == CapFSRead && sb.allow(CapFSWrite, target) {
+		return true
+	}
+	g, ok := sb.grants[c]
+	if !ok {
+		return false
+	}
+	if g.all {
+		return true
+	}
+	if len(target) == 0 {
+		return false
+	}
+	switch c {
+	case CapFSRead, CapFSWrite:
+		abs, err := filepath.Abs(target)
+		if err != nil {
+			return false
+		}
+		abs = realPath(abs)
+		for _, dir := range g.scopes {
+			if rel, err := filepath.Rel(dir, abs); err == nil && rel != ".." &&
+				!strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return true
+			}
+		}
+	case CapNet:
+		host := target
+		if u, err := url.Parse(target); err == nil && len(u.Host) > 0 {
+			host = u.Hostname()
+		} else if h, _, err := net.SplitHostPort(target); err == nil {
+			host = h
+		}
+		for _, scope := range g.scopes {
+			if host == scope || strings.HasSuffix(host, "."+scope) {
+				return true
+			}
+		}
+	case CapExec:
+		// the command chained or substituted by shell runs other programs
+		if strings.ContainsAny(target, shellMetachars) {
+			return false
+		}
+		fields := strings.Fields(target)
+		if len(fields) == 0 {
+			return false
+		}
+		program := filepath.Base(fields[0])
+		for _, scope := range g.scopes {
+			if program == scope || strings.TrimSuffix(program, filepath.Ext(program)) == scope {
+				return true
+			}
+		}
+	case CapFFI:
+		lib := filepath.Base(target)
+		for _, scope := range g.scopes {
+			if lib == scope || strings.TrimSuffix(lib, filepath.Ext(lib)) == scope {
+				return true
+			}
+		}
+	default:
+		for _, scope := range g.scopes {
+			if target == scope {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// realPath resolves the symbolic links of path, and the part not existed yet is kept.
+func realPath(path string) string {
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		return real
+	}
+	dir := filepath.Dir(path)
+	if dir == path {
+		return path
+	}
+	return filepath.Join(realPath(dir), filepath.Base(path))
+}
+
+const sandboxKey = "__yock_sandbox"
+
+// OptionSandbox runs the runtime in sandbox
+func OptionSandbox(sb *Sandbox) YockrOption {
+	return func(yockr yocki.YockRuntime) error {
+		SetSandbox(yockr.State().LState(), sb)
+		return nil
+	}
+}
+
+// SetSandbox puts l and the threads of it into sandbox, and the lua builtins
+// reaching files, processes and environment variables are checked as well.
+func SetSandbox(l *lua.LState, sb *Sandbox) {
+	ud := l.NewUserData()
+	ud.Value = sb
+	l.G.Registry.RawSetString(sandboxKey, ud)
+	sandboxBuiltins(l)
+}
+
+// SandboxOf returns the sandbox of l, and it's nil when l isn't in sandbox.
+func SandboxOf(l *lua.LState) *Sandbox {
+	if ud, ok := l.G.Registry.RawGetString(sandboxKey).(*lua.LUserData); ok {
+		sb, _ := ud.Value.(*Sandbox)
+		return sb
+	}
+	return nil
+}
+
+// Guard raises lua error when the sandbox of l doesn't grant the capability
+// on any of targets. It's called by bindings before acting.
+func Guard(l *lua.LState, c string, targets ...string) {
+	sb := SandboxOf(l)
+	if sb == nil {
+		return
+	}
+	if len(targets) == 0 {
+		targets = []string{""}
+	}
+	for _, target := range targets {
+		if err := sb.Check(c, target); err != nil {
+			l.RaiseError("%s", err.Error())
+		}
+	}
+}
+
+// sandboxBuiltins wraps the functions of lua stdlib with checking
+func sandboxBuiltins(l *lua.LState) {
+	// check guards the first argument of builtin, which is optional
+	// for the ones reading stdin or writing stdout
+	check := func(c string, optional bool) func(l *lua.LState) {
+		return func(l *lua.LState) {
+			if l.Get(1).Type() == lua.LTString {
+				Guard(l, c, l.ToString(1))
+			} else if !optional {
+				Guard(l, c)
+			}
+		}
+	}
+	guards := map[string]map[string]func(l *lua.LState){
+		lua.OsLibName: {
+			"execute": check(CapExec, false),
+			"remove":  check(CapFSWrite, false),
+			"rename": func(l *lua.LState) {
+				Guard(l, CapFSWrite, l.CheckString(1), l.CheckString(2))
+			},
+			"getenv": check(CapEnv, false),
+			"setenv": check(CapEnv, false),
+		},
+		lua.IoLibName: {
+			"open": func(l *lua.LState) {
+				if strings.ContainsAny(l.OptString(2, "r"), "wa+") {
+					Guard(l, CapFSWrite, l.CheckString(1))
+				} else {
+					Guard(l, CapFSRead, l.CheckString(1))
+				}
+			},
+			"popen":  check(CapExec, false),
+			"lines":  check(CapFSRead, true),
+			"input":  check(CapFSRead, true),
+			"output": check(CapFSWrite, true),
+		},
+		lua.BaseLibName: {
+			"dofile":   check(CapFSRead, true),
+			"loadfile": check(CapFSRead, true),
+		},
+	}
+	for libName, funcs := range guards {
+		lib := l.G.Global
+		if libName != lua.BaseLibName {
+			var ok bool
+			if lib, ok = l.GetGlobal(libName).(*lua.LTable); !ok {
+				continue
+			}
+		}
+		for name, guard := range funcs {
+			fn, ok := lib.RawGetString(name).(*lua.LFunction)
+			if !ok {
+				continue
+			}
+			guard := guard
+			lib.RawSetString(name, l.NewFunction(func(l *lua.LState) int {
+				guard(l)
+				top := l.GetTop()
+				l.Push(fn)
+				for i := 1; i <= top; i++ {
+					l.Push(l.Get(i))
+				}
+				l.Call(top, lua.MultRet)
+				return l.GetTop() - top
+			}))
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yockr
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestSandbox(t *testing.T) {
+	dir := t.TempDir()
+	build := filepath.Join(dir, "build")
+	profile := filepath.Join(dir, "third-party.yaml")
+	os.WriteFile(profile, []byte(`
+capabilities:
+  - fs:write:`+build+`
+  - net:github.com
+  - exec:git
+  - env:HOME
+`), 0644)
+	sb, err := LoadSandbox(profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sb.Name != "third-party" {
+		t.Fatal("unexpected name of sandbox", sb.Name)
+	}
+	cases := []struct {
+		cap, target string
+		allow       bool
+	}{
+		{CapFSWrite, filepath.Join(build, "out", "a.txt"), true},
+		{CapFSRead, filepath.Join(build, "a.txt"), true},
+		{CapFSWrite, build, true},
+		{CapFSWrite, filepath.Join(build, "..", "a.txt"), false},
+		{CapFSWrite, build + "2", false},
+		{CapFSRead, dir, false},
+		{CapNet, "https://api.github.com/repos", true},
+		{CapNet, "github.com:22", true},
+		{CapNet, "evilgithub.com", false},
+		{CapExec, "git clone https://github.com/ansurfen/yock", true},
+		{CapExec, "/usr/bin/git.exe status", true},
+		{CapExec, "rm -rf /", false},
+		{CapExec, "git status; rm -rf ~", false},
+		{CapExec, "git log | sh", false},
+		{CapExec, "git status && rm -rf ~", false},
+		{CapExec, "git commit -m $(rm -rf ~)", false},
+		{CapExec, "git commit -m `rm -rf ~`", false},
+		{CapExec, "git diff > ~/.bashrc", false},
+		{CapExec, "git status\nrm -rf ~", false},
+		{CapEnv, "HOME", true},
+		{CapEnv, "PATH", false},
+		{CapFFI, "libc.so", false},
+	}
+	for _, c := range cases {
+		if err := sb.Check(c.cap, c.target); (err == nil) != c.allow {
+			t.Fatal("unexpected check", c.cap, c.target, err)
+		}
+	}
+	if sb.Granted(CapNet) || sb.Granted(CapFSRead) {
+		t.Fatal("scoped capability is granted without scope")
+	}
+	if _, err = NewSandbox("", "fs:exec"); err == nil {
+		t.Fatal("invalid capability is accepted")
+	}
+
+	l := lua.NewState()
+	defer l.Close()
+	SetSandbox(l, sb)
+	l.SetGlobal("build", lua.LString(build))
+	err = l.DoString(`
+os.execute("mkdir -p " .. build)
+`)
+	if err == nil || !strings.Contains(err.Error(), "sandbox third-party denies exec mkdir -p") {
+		t.Fatal("os.execute isn't checked", err)
+	}
+	os.MkdirAll(build, 0755)
+	if err = l.DoString(`
+local fp = io.open(build .. "/a.txt", "w")
+fp:write("yock")
+fp:close()
+home = os.getenv("HOME")
+`); err != nil {
+		t.Fatal(err)
+	}
+	err = l.DoString(`io.open(build .. "/../a.txt", "a")`)
+	if err == nil || !strings.Contains(err.Error(), "denies fs:write") {
+		t.Fatal("io.open isn't checked", err)
+	}
+}
@@ -32,7 +32,7 @@ func (s *YockState) Call(info yocki.YockFuncInfo, args ...any) error {
 	for _, arg := range args {
 		lua_args = append(lua_args, luar.New(s.ls, arg))
 	}
-	return s.ls.CallByParam(lua.P(info), lua_args...)
+	return limitError(s.ls, s.ls.CallByParam(lua.P(info), lua_args...))
 }
 
 func (s *YockState) PCall() error {
@@ -249,5 +249,5 @@ func (s *YockState) Clone() yocki.YockState {
 func LuaDoFunc(lvm *lua.LState, fun *lua.LFunction) error {
 	lfunc := lvm.NewFunctionFromProto(fun.Proto)
 	lvm.Push(lfunc)
-	return lvm.PCall(0, lua.MultRet, nil)
+	return limitError(lvm, lvm.PCall(0, lua.MultRet, nil))
 }
@@ -7,10 +7,12 @@ package yocks
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	yocki "github.com/ansurfen/yock/interface"
 	"github.com/ansurfen/yock/util"
+	"github.com/ansurfen/yock/ycho"
 	lua "github.com/yuin/gopher-lua"
 	luar "layeh.com/gopher-luar"
 )
@@ -19,9 +21,21 @@ type Context struct {
 	s      yocki.YockState
 	tbl    yocki.Table
 	cancel context.CancelFunc
+	task   string
 	source string
+	unbind func()
+	// busy is closed when the call overrunning returns,
+	// and it's nil when the state isn't occupied.
+	busy chan struct{}
+	// snapshot is the fields of context before the job overrunning,
+	// which are read instead of the table occupied by the job.
+	snapshot *lua.LTable
 }
 
+// jobGracePeriod is how long the job overrunning is waited
+// to unwind after its state is canceled.
+var jobGracePeriod = 5 * time.Second
+
 type contextExitCode int
 
 func (c contextExitCode) String() string {
@@ -52,7 +66,16 @@ func newContext(name string, job yocki.YockJob, flags yocki.Table, yocks yocki.Y
 		},
 		"yield": func(timeout ...int) {
 			if len(timeout) > 0 {
-				time.Sleep(time.Duration(timeout[0]))
+				// wakes up in advance when the job is canceled
+				if c := s.LState().Context(); c != nil {
+					select {
+					case <-time.After(time.Duration(timeout[0])):
+					case <-c.Done():
+						s.LState().RaiseError("[%s] %s", name, c.Err())
+					}
+				} else {
+					time.Sleep(time.Duration(timeout[0]))
+				}
 			} else {
 				// TODO: wait for
 			}
@@ -83,43 +106,83 @@ func newContext(name string, job yocki.YockJob, flags yocki.Table, yocks yocki.Y
 			}
 		},
 		"set_os": func(os string) {
-			platform := tbl.Value().RawGetString("platform").(*lua.LUserData).Value.(util.Platform)
-			platform.OS = os
-			tbl.Value().RawSetString("platform", luar.New(s.LState(), platform))
+			setOS(s, tbl, os)
 		},
 	})
+	if j, ok := job.(*yockJob); ok && j.cell != nil {
+		tbl.SetLTable("matrix", j.cell.matrix())
+		// the os of matrix is regarded as the target platform of job
+		if os, ok := j.cell.coords.RawGetString("os").(lua.LString); ok {
+			setOS(s, tbl, os.String())
+		}
+	}
 	if flags != nil {
 		if tmp, ok := flags.GetLTable(name); ok {
 			tbl.SetLTable("flags", tmp)
 		}
 	}
-	source := ""
-	if name == job.Name() {
-		source = name
-	} else {
-		source = name + ":" + job.Name()
-	}
-	return &Context{
+	ctx := &Context{
 		s:      s,
 		cancel: cancel,
 		tbl:    tbl,
-		source: source,
+		task:   name,
+		source: jobSource(name, job),
+	}
+	if ys, ok := yocks.(*YockScheduler); ok {
+		ctx.unbind = ys.bindGroup(s, name)
+	}
+	return ctx
+}
+
+// setOS changes the os of platform in context table, if any
+func setOS(s yocki.YockState, tbl yocki.Table, os string) {
+	ud, ok := tbl.Value().RawGetString("platform").(*lua.LUserData)
+	if !ok {
+		return
 	}
+	platform, ok := ud.Value.(util.Platform)
+	if !ok {
+		return
+	}
+	platform.OS = os
+	tbl.Value().RawSetString("platform", luar.New(s.LState(), platform))
+}
+
+// jobSource returns the identity of job in task, in form of task:job
+func jobSource(task string, job yocki.YockJob) string {
+	source := task
+	if task != job.Name() {
+		source = task + ":" + job.Name()
+	}
+	// the cells of matrix are distinguished by their coordinates, such as build[arch=amd64,os=linux]
+	if j, ok := job.(*yockJob); ok && j.cell != nil {
+		source += "[" + j.cell.name + "]"
+	}
+	return source
 }
 
-func (ctx *Context) Call(fn *lua.LFunction) (c contextExitCode) {
+// Call runs fn with the context table, and returns the exit code
+// and the error raised from fn (if any), which is always *lua.ApiError
+// to carry the traceback of lua.
+func (ctx *Context) Call(fn *lua.LFunction) (c contextExitCode, err error) {
 	c = contextExitCode(1)
 	defer func() {
 		msg := recover()
 		switch v := msg.(type) {
-		case error:
-			fmt.Print(v)
-			panic(v)
+		case *lua.ApiError:
+			c, err = contextExitCode(0), v
 		case contextExitCode:
 			c = v
+		case nil:
+		default:
+			c, err = contextExitCode(0), &lua.ApiError{
+				Type:       lua.ApiErrorPanic,
+				Object:     lua.LString(fmt.Sprint(v)),
+				StackTrace: ctx.traceback(),
+			}
 		}
 	}()
-	err := ctx.s.Call(yocki.YockFuncInfo{
+	err = ctx.s.Call(yocki.YockFuncInfo{
 		Fn: fn,
 	}, ctx.tbl.Value())
 	if err != nil {
@@ -128,6 +191,110 @@ func (ctx *Context) Call(fn *lua.LFunction) (c contextExitCode) {
 	return
 }
 
+// run calls the function of job and records the result.
+// When timeout is greater than 0, the state of context will be canceled
+// once the job overruns, and the job is regarded as failure after the call
+// unwinds. The call blocked in go function might not observe the cancel,
+// and it isn't waited longer than jobGracePeriod, in which case the context
+// is closed until the call returns.
+func (ctx *Context) run(job *yockJob, timeout time.Duration) *JobResult {
+	res := &JobResult{
+		Task:   ctx.tbl.Value().RawGetString("task").String(),
+		Source: ctx.source,
+		Cell:   job.cellName(),
+		Status: JobSucceeded,
+	}
+	start := time.Now()
+	var (
+		code contextExitCode
+		err  error
+	)
+	if timeout > 0 {
+		deadline := ctx.setTimeout(timeout)
+		ctx.snapshot = plainValue(ctx.tbl.Value(), make(map[*lua.LTable]*lua.LTable)).(*lua.LTable)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			code, err = ctx.Call(job.fn)
+		}()
+		select {
+		case <-done:
+		case <-deadline.Done():
+			res.Duration = time.Since(start)
+			res.Status = JobFailed
+			res.Error = fmt.Sprintf("%s after %s", util.ErrJobTimeout, timeout)
+			select {
+			case <-done:
+			case <-time.After(jobGracePeriod):
+				ycho.Warnf("[%s] doesn't unwind in %s after timeout", ctx.source, jobGracePeriod)
+				ctx.busy = done
+			}
+			return res
+		}
+	} else {
+		code, err = ctx.Call(job.fn)
+	}
+	res.Duration = time.Since(start)
+	res.ExitCode = int(code)
+	if err != nil {
+		res.Status = JobFailed
+		res.Error = err.Error()
+		if e, ok := err.(*lua.ApiError); ok {
+			res.Error = e.Object.String()
+			res.Traceback = e.StackTrace
+		}
+		return res
+	}
+	if job.spec != nil {
+		if err = job.spec.save(ctx.source); err != nil {
+			ycho.Warnf("[%s] fail to save fingerprint, %s", ctx.source, err)
+		}
+	}
+	return res
+}
+
+// setTimeout binds deadline to the state of context
+// and returns the context of deadline.
+func (ctx *Context) setTimeout(timeout time.Duration) context.Context {
+	parent := ctx.s.LState().Context()
+	if parent == nil {
+		parent = context.Background()
+	}
+	deadline, cancel := context.WithTimeout(parent, timeout)
+	ctx.s.LState().SetContext(deadline)
+	if old := ctx.cancel; old != nil {
+		ctx.cancel = func() {
+			cancel()
+			old()
+		}
+	} else {
+		ctx.cancel = cancel
+	}
+	return deadline
+}
+
+// fields returns the fields of context table without functions.
+// When the job still occupies the state, they're taken before the job runs.
+func (ctx *Context) fields() *lua.LTable {
+	if ctx.busy != nil {
+		return ctx.snapshot
+	}
+	return plainValue(ctx.tbl.Value(), make(map[*lua.LTable]*lua.LTable)).(*lua.LTable)
+}
+
+// traceback returns the current stack of context's state
+func (ctx *Context) traceback() string {
+	buf := []string{"stack traceback:"}
+	for i := 0; ; i++ {
+		dbg, ok := ctx.s.Stack(i)
+		if !ok {
+			break
+		}
+		buf = append(buf, fmt.Sprintf("\t%s:%d", dbg.Source, dbg.CurrentLine))
+	}
+	return strings.Join(buf, "\n")
+}
+
 func (ctx *Context) Extends(super *Context) {
 	platform := super.tbl.Value().RawGetString("platform").(*lua.LUserData).Value.(util.Platform)
 	ctx.tbl.Value().RawSetString("platform", luar.New(ctx.s.LState(), platform))
@@ -135,7 +302,18 @@ func (ctx *Context) Extends(super *Context) {
 }
 
 func (ctx *Context) Close() {
+	if busy := ctx.busy; busy != nil {
+		ctx.busy = nil
+		go func() {
+			<-busy
+			ctx.Close()
+		}()
+		return
+	}
 	if ctx.cancel != nil {
 		ctx.cancel()
 	}
+	if ctx.unbind != nil {
+		ctx.unbind()
+	}
 }
@@ -0,0 +1,316 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yocks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ansurfen/yock/ctl/conf"
+	"github.com/ansurfen/yock/daemon/net"
+	pb "github.com/ansurfen/yock/daemon/proto"
+	yocke "github.com/ansurfen/yock/env"
+	yocki "github.com/ansurfen/yock/interface"
+	liby "github.com/ansurfen/yock/lib/go/yock"
+	yockr "github.com/ansurfen/yock/runtime"
+	"github.com/ansurfen/yock/util"
+	"github.com/ansurfen/yock/ycho"
+	lua "github.com/yuin/gopher-lua"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// node returns the name of yockd where job runs, and it's empty for local job.
+func (job *yockJob) node() string {
+	if job.opt == nil {
+		return ""
+	}
+	if node, ok := job.opt.RawGetString("node").(lua.LString); ok {
+		return string(node)
+	}
+	return ""
+}
+
+// nodeClient returns the client of yockd named node, which is dialed by
+// yockd.dial or declared in the peer of yockd option.
+func (yocks *YockScheduler) nodeClient(node string) (yocki.YockdClient, error) {
+	yocks.daemonMu.Lock()
+	defer yocks.daemonMu.Unlock()
+	if cli, ok := yocks.daemon[node]; ok {
+		return cli, nil
+	}
+	if env, ok := yocke.LookupEnv[*conf.YockConf](); ok {
+		if peer, ok := env.Conf().Yockd.Peer[node]; ok {
+			yocks.daemon[node] = net.NewDirect(&net.YockdClientOption{
+				IP:   peer.IP,
+				Port: int(peer.Port),
+			})
+			return yocks.daemon[node], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", util.ErrNodeNotFound, node)
+}
+
+// ctxLocalFields are the fields of context decided by node itself
+var ctxLocalFields = map[string]bool{
+	"platform":  true,
+	"workdir":   true,
+	"yock_path": true,
+	"conf":      true,
+	"task":      true,
+	"flags":     true,
+}
+
+// newDispatchRequest packages the function of job along with the context.
+// The upvalues of function must be encoded by json, and the fields of
+// context which can't be encoded, such as functions, are left out.
+func newDispatchRequest(ctx *Context, job *yockJob, timeout time.Duration) (*pb.DispatchRequest, error) {
+	chunk, err := yockr.EncodeBytecode(job.fn.Proto)
+	if err != nil {
+		return nil, err
+	}
+	upvalues := []json.RawMessage{}
+	for i, uv := range job.fn.Upvalues {
+		raw, err := liby.Encode(uv.Value())
+		if err != nil {
+			return nil, fmt.Errorf("fail to dispatch upvalue %s, %s", job.fn.Proto.DbgUpvalues[i], err)
+		}
+		upvalues = append(upvalues, raw)
+	}
+	env := map[string]json.RawMessage{}
+	ctx.tbl.Value().ForEach(func(k, v lua.LValue) {
+		key, ok := k.(lua.LString)
+		if !ok || ctxLocalFields[string(key)] {
+			return
+		}
+		if raw, err := liby.Encode(v); err == nil {
+			env[string(key)] = raw
+		}
+	})
+	req := &pb.DispatchRequest{
+		Task:    ctx.tbl.Value().RawGetString("task").String(),
+		Job:     job.name,
+		Chunk:   chunk,
+		Timeout: int64(timeout),
+	}
+	raw, err := json.Marshal(upvalues)
+	if err != nil {
+		return nil, err
+	}
+	req.Upvalues = string(raw)
+	if raw, err = json.Marshal(env); err != nil {
+		return nil, err
+	}
+	req.Env = string(raw)
+	if flags, ok := ctx.tbl.Value().RawGetString("flags").(*lua.LTable); ok {
+		if raw, err = liby.Encode(flags); err != nil {
+			return nil, err
+		}
+		req.Flags = string(raw)
+	}
+	return req, nil
+}
+
+// dispatch runs job on its node instead of the state of ctx, and the lines
+// printed by job are logged with the source of ctx. The fingerprint of job
+// isn't saved, because its inputs and outputs are on the node.
+//
+// The job on node isn't limited by sandbox, so that it's dispatched only when
+// the capabilities it might use are granted without scope.
+func (yocks *YockScheduler) dispatch(ctx *Context, job *yockJob, timeout time.Duration) *JobResult {
+	res := &JobResult{
+		Task:   ctx.tbl.Value().RawGetString("task").String(),
+		Source: ctx.source,
+		Cell:   job.cellName(),
+		Status: JobFailed,
+	}
+	start := time.Now()
+	defer func() {
+		if res.Duration == 0 {
+			res.Duration = time.Since(start)
+		}
+	}()
+	for _, c := range []string{yockr.CapFSWrite, yockr.CapNet, yockr.CapExec, yockr.CapEnv} {
+		if err := yocks.guard(c); err != nil {
+			res.Error = err.Error()
+			return res
+		}
+	}
+	req, err := newDispatchRequest(ctx, job, timeout)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	var ret *pb.DispatchResult
+	if sel := job.selector(); sel != nil {
+		res.Node, ret, err = yocks.dispatchSelected(ctx, sel, req)
+	} else {
+		res.Node = job.node()
+		var cli yocki.YockdClient
+		if cli, err = yocks.nodeClient(res.Node); err == nil {
+			ret, err = cli.Dispatch(req, dispatchLog(ctx, res.Node))
+		}
+	}
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.Status = JobStatus(ret.GetStatus())
+	res.Error = ret.GetError()
+	res.Traceback = ret.GetTraceback()
+	res.Duration = time.Duration(ret.GetDuration())
+	res.ExitCode = int(ret.GetExitCode())
+	return res
+}
+
+// dispatchSelected runs job on the least-loaded member of cluster matched by sel.
+// Like loadbalance, the member unreachable is given up and the next is tried,
+// until half of the members are tried. The job which may have started on the
+// member isn't run again, for it isn't known whether job is done.
+func (yocks *YockScheduler) dispatchSelected(ctx *Context, sel *nodeSelector, req *pb.DispatchRequest) (string, *pb.DispatchResult, error) {
+	members, err := yocks.clusterMembers()
+	if err != nil {
+		return "", nil, err
+	}
+	nodes := selectNodes(members, sel)
+	if len(nodes) == 0 {
+		return "", nil, fmt.Errorf("%w: %s", util.ErrNodeNotFound, sel)
+	}
+	maxRetry := len(nodes)/2 + 1
+	for i := 0; ; i++ {
+		node := nodes[i].GetName()
+		cli, err := yocks.memberClient(nodes[i])
+		if err == nil {
+			logged, log := false, dispatchLog(ctx, node)
+			var ret *pb.DispatchResult
+			ret, err = cli.Dispatch(req, func(line string) {
+				logged = true
+				log(line)
+			})
+			if err == nil || logged || status.Code(err) != codes.Unavailable {
+				return node, ret, err
+			}
+		}
+		if i+1 >= maxRetry {
+			return node, nil, err
+		}
+		ycho.Warnf("fail to dispatch %s to %s, %s", req.GetJob(), node, err)
+	}
+}
+
+// dispatchLog logs the lines printed by job on node with the source of ctx
+func dispatchLog(ctx *Context, node string) func(string) {
+	return func(line string) {
+		ycho.Infof("[%s@%s] %s", ctx.source, node, line)
+	}
+}
+
+// RunDispatch runs the job of req in yocks, which is the scheduler created
+// by node for it, and log is called with each line printed by job, such as
+// the output of print, echo, sh and ycho.
+func (yocks *YockScheduler) RunDispatch(req *pb.DispatchRequest, log func(string)) *pb.DispatchResult {
+	l := yocks.State().LState()
+	fn, err := dispatchedFunc(l, req)
+	if err != nil {
+		return &pb.DispatchResult{Status: int32(JobFailed), Error: err.Error()}
+	}
+	out := &dispatchOutput{log: log}
+	yockr.SetOutput(l, out)
+	defer out.flush()
+	job := &yockJob{name: req.GetJob(), fn: fn}
+	ctx := newContext(req.GetTask(), job, nil, yocks)
+	defer ctx.Close()
+	if len(req.GetEnv()) > 0 {
+		env, err := liby.Decode(l, []byte(req.GetEnv()))
+		if err != nil {
+			return &pb.DispatchResult{Status: int32(JobFailed), Error: err.Error()}
+		}
+		if env, ok := env.(*lua.LTable); ok {
+			env.ForEach(func(k, v lua.LValue) {
+				ctx.tbl.Value().RawSet(k, v)
+			})
+		}
+	}
+	if len(req.GetFlags()) > 0 {
+		flags, err := liby.Decode(l, []byte(req.GetFlags()))
+		if err != nil {
+			return &pb.DispatchResult{Status: int32(JobFailed), Error: err.Error()}
+		}
+		ctx.tbl.Value().RawSetString("flags", flags)
+	}
+	res := ctx.run(job, time.Duration(req.GetTimeout()))
+	return &pb.DispatchResult{
+		Status:    int32(res.Status),
+		Error:     res.Error,
+		Traceback: res.Traceback,
+		Duration:  int64(res.Duration),
+		ExitCode:  int32(res.ExitCode),
+	}
+}
+
+// dispatchOutput splits what's printed by the dispatched job into lines
+type dispatchOutput struct {
+	mu  sync.Mutex
+	buf []byte
+	log func(string)
+}
+
+func (out *dispatchOutput) Write(p []byte) (int, error) {
+	out.mu.Lock()
+	defer out.mu.Unlock()
+	out.buf = append(out.buf, p...)
+	for {
+		i := bytes.IndexByte(out.buf, '\n')
+		if i < 0 {
+			break
+		}
+		out.log(strings.TrimSuffix(string(out.buf[:i]), "\r"))
+		out.buf = out.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush logs the last line printed without line break
+func (out *dispatchOutput) flush() {
+	out.mu.Lock()
+	defer out.mu.Unlock()
+	if len(out.buf) > 0 {
+		out.log(string(out.buf))
+		out.buf = nil
+	}
+}
+
+// dispatchedFunc restores the function of job and its upvalues in l
+func dispatchedFunc(l *lua.LState, req *pb.DispatchRequest) (*lua.LFunction, error) {
+	proto, err := yockr.DecodeBytecode(req.GetChunk())
+	if err != nil {
+		return nil, err
+	}
+	upvalues := []json.RawMessage{}
+	if len(req.GetUpvalues()) > 0 {
+		if err = json.Unmarshal([]byte(req.GetUpvalues()), &upvalues); err != nil {
+			return nil, err
+		}
+	}
+	if len(upvalues) != int(proto.NumUpvalues) {
+		return nil, fmt.Errorf("want %d upvalues, but got %d", proto.NumUpvalues, len(upvalues))
+	}
+	fn := l.NewFunctionFromProto(proto)
+	for i, raw := range upvalues {
+		v, err := liby.Decode(l, raw)
+		if err != nil {
+			return nil, err
+		}
+		// the upvalue without register is closed, which holds value itself
+		uv := &lua.Upvalue{}
+		uv.SetValue(v)
+		fn.Upvalues[i] = uv
+	}
+	return fn, nil
+}
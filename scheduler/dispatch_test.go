@@ -0,0 +1,150 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yocks
+
+import (
+	"context"
+	"fmt"
+	gonet "net"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ansurfen/yock/daemon/net"
+	pb "github.com/ansurfen/yock/daemon/proto"
+	"google.golang.org/grpc"
+)
+
+type dispatchNode struct {
+	pb.UnimplementedYockDaemonServer
+	members []*pb.Member
+}
+
+func (node *dispatchNode) ClusterMembers(context.Context, *pb.ClusterMembersRequest) (*pb.ClusterMembersResponse, error) {
+	return &pb.ClusterMembersResponse{Members: node.members}, nil
+}
+
+func (*dispatchNode) Dispatch(req *pb.DispatchRequest, stream pb.YockDaemon_DispatchServer) error {
+	ys := Default(OptionLibPath("../lib/yock"))
+	go ys.EventLoop()
+	defer ys.Shutdown(time.Second)
+	res := ys.RunDispatch(req, func(line string) {
+		stream.Send(&pb.DispatchResponse{Log: line})
+	})
+	return stream.Send(&pb.DispatchResponse{Result: res})
+}
+
+func TestDispatch(t *testing.T) {
+	listen, err := gonet.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	addr := listen.Addr().String()
+	pb.RegisterYockDaemonServer(srv, &dispatchNode{members: []*pb.Member{
+		{Name: "web-1", Addr: addr, Os: runtime.GOOS, Labels: []string{"docker"}, Cpu: 50, Mem: 50},
+		// the least-loaded member is unreachable
+		{Name: "web-2", Addr: "127.0.0.1:1", Os: runtime.GOOS, Labels: []string{"docker"}, Cpu: 10, Mem: 10},
+		{Name: "web-3", Addr: addr, Os: runtime.GOOS, Labels: []string{"gpu"}, State: pb.MemberState_Dead},
+	}})
+	go srv.Serve(listen)
+	defer srv.Stop()
+
+	ys := Default(OptionLibPath("../lib/yock"))
+	go ys.EventLoop()
+	defer ys.Shutdown(time.Second)
+	ys.daemon["web-1"] = net.NewDirect(&net.YockdClientOption{
+		IP:   "127.0.0.1",
+		Port: listen.Addr().(*gonet.TCPAddr).Port,
+	})
+	ys.daemon["default"] = ys.daemon["web-1"]
+	err = ys.Eval(fmt.Sprintf(`
+local greeting, hosts = "hello", { "a", "b" }
+env.version = "1.0"
+job("deploy", function(ctx)
+    print(greeting, hosts[2], ctx.version, ctx.task)
+    ycho.info("from ycho")
+    sh("echo from sh")
+    echo("from echo")
+end, { node = "web-1" })
+job("fail", function(ctx)
+    error("boom")
+end, { node = "web-1" })
+job("lost", function(ctx) end, { node = "web-4" })
+job("select", function(ctx) end, { node = { os = %q, labels = "docker" } })
+job("unmatched", function(ctx) end, { node = { labels = { "docker", "gpu" } } })`, runtime.GOOS))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job := ys.task["deploy"][0]
+	ctx := newContext("deploy", job, nil, ys)
+	defer ctx.Close()
+	req, err := newDispatchRequest(ctx, job, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli, _ := ys.nodeClient("web-1")
+	mu, logs := sync.Mutex{}, []string{}
+	res, err := cli.Dispatch(req, func(line string) {
+		mu.Lock()
+		defer mu.Unlock()
+		logs = append(logs, line)
+	})
+	if err != nil || res.GetStatus() != int32(JobSucceeded) {
+		t.Fatal("fail to dispatch", res, err)
+	}
+	if len(logs) != 4 || logs[0] != "hello\tb\t1.0\tdeploy" ||
+		!strings.HasPrefix(logs[1], "[INFO]") || !strings.HasSuffix(logs[1], "from ycho") ||
+		logs[2] != "from sh" || strings.TrimSpace(logs[3]) != "from echo" {
+		t.Fatalf("unexpected logs %q", logs)
+	}
+
+	report := ys.LaunchTasks("deploy", "fail", "lost", "select", "unmatched")
+	status := map[string]*JobResult{}
+	for _, res := range report.Results {
+		status[res.Task] = res
+	}
+	if status["deploy"].Status != JobSucceeded {
+		t.Fatal("deploy should succeed", status["deploy"].Error)
+	}
+	if res := status["fail"]; res.Status != JobFailed || !strings.Contains(res.Error, "boom") || len(res.Traceback) == 0 {
+		t.Fatal("the error of node should be sent back", res)
+	}
+	if res := status["lost"]; res.Status != JobFailed || !strings.Contains(res.Error, "node not found") {
+		t.Fatal("unknown node should fail the job", res)
+	}
+	if res := status["select"]; res.Status != JobSucceeded || res.Node != "web-1" {
+		t.Fatal("job should run on the reachable member", res)
+	}
+	if res := status["unmatched"]; res.Status != JobFailed || !strings.Contains(res.Error, "node not found") {
+		t.Fatal("job shouldn't run on the dead member", res)
+	}
+}
+
+func TestSelectNodes(t *testing.T) {
+	members := []*pb.Member{
+		{Name: "a", Os: "linux", Labels: []string{"docker", "gpu-free"}, Cpu: 80, Mem: 40},
+		{Name: "b", Os: "linux", Labels: []string{"docker", "gpu-free", "ssd"}, Cpu: 20, Mem: 30},
+		{Name: "c", Os: "windows", Labels: []string{"docker", "gpu-free"}},
+		{Name: "d", Os: "linux", Labels: []string{"docker"}},
+		{Name: "e", Os: "linux", Labels: []string{"docker", "gpu-free"}, State: pb.MemberState_Suspect},
+	}
+	names := func(sel *nodeSelector) string {
+		res := []string{}
+		for _, m := range selectNodes(members, sel) {
+			res = append(res, m.GetName())
+		}
+		return strings.Join(res, ",")
+	}
+	if got := names(&nodeSelector{os: "linux", labels: []string{"gpu-free", "docker"}}); got != "b,a" {
+		t.Fatal("unexpected nodes", got)
+	}
+	if got := names(&nodeSelector{}); got != "c,d,b,a" {
+		t.Fatal("unexpected nodes", got)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/ansurfen/yock/ctl/conf"
 	yocke "github.com/ansurfen/yock/env"
 	yocki "github.com/ansurfen/yock/interface"
+	yockr "github.com/ansurfen/yock/runtime"
 	"github.com/ansurfen/yock/util"
 	lua "github.com/yuin/gopher-lua"
 )
@@ -20,8 +21,16 @@ func loadEnv(yocks yocki.YockScheduler) {
 		"platform":  util.CurPlatform,
 		"workdir":   util.WorkSpace,
 		"yock_path": util.YockPath,
-		"conf":      yocke.GetEnv[*conf.YockConf]().Conf(),
 	})
+	// yockd runs the dispatched jobs without yock's configuration, and
+	// it's hidden in sandbox unless fs:write is granted, for it can be
+	// written to any path.
+	env, ok := yocke.LookupEnv[*conf.YockConf]()
+	if ok && yockr.SandboxOf(yocks.State().LState()).Granted(yockr.CapFSWrite) {
+		lib.SetField(map[string]any{
+			"conf": env.Conf(),
+		})
+	}
 	lib.SetFunctions(map[string]lua.LGFunction{
 		"set_args": envSetArgs,
 	})
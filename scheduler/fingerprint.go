@@ -0,0 +1,230 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yocks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ansurfen/yock/util"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// jobSpec declares the files which job reads and writes.
+// When both inputs and outputs of job are unchanged since
+// its last successful run, the job is regarded as up-to-date
+// and skipped by scheduler, just like make.
+type jobSpec struct {
+	// inputs are glob patterns, and ** matches any number of directories
+	inputs []string
+	// outputs are paths of file or directory
+	outputs []string
+}
+
+func checkJobSpec(opt *lua.LTable) *jobSpec {
+	spec := &jobSpec{
+		inputs:  checkStrings(opt.RawGetString("inputs")),
+		outputs: checkStrings(opt.RawGetString("outputs")),
+	}
+	if len(spec.inputs) == 0 && len(spec.outputs) == 0 {
+		return nil
+	}
+	return spec
+}
+
+// fingerprint is the snapshot of job's files after a successful run
+type fingerprint struct {
+	Inputs  string `json:"inputs"`
+	Outputs string `json:"outputs"`
+}
+
+// fingerprintPath returns the location of job's fingerprint in yock's cache,
+// which is distinguished by working directory and the source of job.
+// It's kept out of @/tmp, so that only yock clean --fingerprint invalidates it.
+func fingerprintPath(source string) string {
+	wd, _ := os.Getwd()
+	return filepath.Join(util.Pathf("@/cache/fingerprint"), util.MD5(wd+"\x00"+source)+".json")
+}
+
+func (spec *jobSpec) fingerprint() (*fingerprint, error) {
+	inputs, err := globFiles(spec.inputs)
+	if err != nil {
+		return nil, err
+	}
+	outputs, err := walkFiles(spec.outputs)
+	if err != nil {
+		return nil, err
+	}
+	in, err := hashFiles(inputs)
+	if err != nil {
+		return nil, err
+	}
+	out, err := hashFiles(outputs)
+	if err != nil {
+		return nil, err
+	}
+	return &fingerprint{Inputs: in, Outputs: out}, nil
+}
+
+// upToDate reports whether the files of job are same as the last successful run
+func (spec *jobSpec) upToDate(source string) bool {
+	raw, err := util.ReadStraemFromFile(fingerprintPath(source))
+	if err != nil {
+		return false
+	}
+	last := fingerprint{}
+	if err = json.Unmarshal(raw, &last); err != nil {
+		return false
+	}
+	cur, err := spec.fingerprint()
+	if err != nil {
+		return false
+	}
+	return last == *cur
+}
+
+// save records the files of job after it runs successfully
+func (spec *jobSpec) save(source string) error {
+	cur, err := spec.fingerprint()
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(cur)
+	if err != nil {
+		return err
+	}
+	file := fingerprintPath(source)
+	if err = util.SafeMkdirs(filepath.Dir(file)); err != nil {
+		return err
+	}
+	return util.WriteFile(file, raw)
+}
+
+// hashFiles returns the SHA256 checksum of the files' name and content
+func hashFiles(files []string) (string, error) {
+	sum := strings.Builder{}
+	for _, file := range files {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		sum.WriteString(fmt.Sprintf("%s:%s\n", filepath.ToSlash(file), util.SHA256(string(raw))))
+	}
+	return util.SHA256(sum.String()), nil
+}
+
+// walkFiles returns all files of the paths in order,
+// and fails when any path isn't exist.
+func walkFiles(paths []string) ([]string, error) {
+	res := []string{}
+	for _, p := range paths {
+		err := filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				res = append(res, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
+// globFiles returns all files matched by the patterns in order
+func globFiles(patterns []string) ([]string, error) {
+	set := make(map[string]bool)
+	for _, pattern := range patterns {
+		pattern = filepath.ToSlash(filepath.Clean(pattern))
+		if !strings.ContainsAny(pattern, "*?[") {
+			if util.IsExist(pattern) {
+				files, err := walkFiles([]string{pattern})
+				if err != nil {
+					return nil, err
+				}
+				for _, file := range files {
+					set[file] = true
+				}
+			}
+			continue
+		}
+		root := globRoot(pattern)
+		if !util.IsExist(root) {
+			continue
+		}
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && matchGlob(strings.Split(pattern, "/"), strings.Split(filepath.ToSlash(path), "/")) {
+				set[path] = true
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	res := []string{}
+	for file := range set {
+		res = append(res, file)
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
+// globRoot returns the longest directory of pattern without wildcard
+func globRoot(pattern string) string {
+	parts := strings.Split(pattern, "/")
+	root := []string{}
+	for _, part := range parts[:len(parts)-1] {
+		if strings.ContainsAny(part, "*?[") {
+			break
+		}
+		root = append(root, part)
+	}
+	if len(root) == 0 {
+		if strings.HasPrefix(pattern, "/") {
+			return "/"
+		}
+		return "."
+	}
+	if len(root) == 1 && root[0] == "" {
+		return "/"
+	}
+	return strings.Join(root, "/")
+}
+
+// matchGlob matches path with pattern segment by segment,
+// and ** matches zero or more segments.
+func matchGlob(pattern, path []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(path); i++ {
+			if matchGlob(pattern[1:], path[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(path) == 0 {
+		return false
+	}
+	if ok, _ := filepath.Match(pattern[0], path[0]); !ok {
+		return false
+	}
+	return matchGlob(pattern[1:], path[1:])
+}
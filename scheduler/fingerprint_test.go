@@ -0,0 +1,104 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yocks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ansurfen/yock/util"
+)
+
+func TestMatchGlob(t *testing.T) {
+	cases := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"src/*.go", "src/a.go", true},
+		{"src/*.go", "src/pkg/a.go", false},
+		{"src/**/*.go", "src/a.go", true},
+		{"src/**/*.go", "src/pkg/sub/a.go", true},
+		{"src/**", "src/pkg/a.lua", true},
+		{"**/*.lua", "lib/yock/a.lua", true},
+		{"**/*.lua", "lib/yock/a.go", false},
+	}
+	for _, c := range cases {
+		if got := matchGlob(strings.Split(c.pattern, "/"), strings.Split(c.path, "/")); got != c.want {
+			t.Fatalf("%s %s: want %v, got %v", c.pattern, c.path, c.want, got)
+		}
+	}
+}
+
+func TestGlobFiles(t *testing.T) {
+	dir := filepath.ToSlash(t.TempDir())
+	for _, file := range []string{"a.go", "b.lua", "pkg/c.go", "pkg/sub/d.go", "pkg/sub/e.lua"} {
+		writeTestFile(t, filepath.Join(dir, file), file)
+	}
+	files, err := globFiles([]string{dir + "/**/*.go", dir + "/b.lua", dir + "/none/*.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"a.go", "b.lua", "pkg/c.go", "pkg/sub/d.go"}
+	if len(files) != len(want) {
+		t.Fatalf("want %v, got %v", want, files)
+	}
+	for i, file := range files {
+		if filepath.ToSlash(file) != dir+"/"+want[i] {
+			t.Fatalf("want %v, got %v", want, files)
+		}
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	workspace := util.WorkSpace
+	util.WorkSpace = t.TempDir()
+	defer func() { util.WorkSpace = workspace }()
+
+	dir := t.TempDir()
+	input, output := filepath.Join(dir, "main.go"), filepath.Join(dir, "bin", "main")
+	writeTestFile(t, input, "package main")
+	writeTestFile(t, output, "binary")
+	spec := &jobSpec{inputs: []string{filepath.Join(dir, "*.go")}, outputs: []string{filepath.Join(dir, "bin")}}
+	source := "TestFingerprint"
+
+	if spec.upToDate(source) {
+		t.Fatal("job without fingerprint is up-to-date")
+	}
+	if err := spec.save(source); err != nil {
+		t.Fatal(err)
+	}
+	if !spec.upToDate(source) {
+		t.Fatal("unchanged job isn't up-to-date")
+	}
+	if spec.upToDate("TestFingerprint2") {
+		t.Fatal("fingerprint is shared with another job")
+	}
+	writeTestFile(t, input, "package main\n\nfunc main() {}")
+	if spec.upToDate(source) {
+		t.Fatal("job whose input changed is up-to-date")
+	}
+	if err := spec.save(source); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(output); err != nil {
+		t.Fatal(err)
+	}
+	if spec.upToDate(source) {
+		t.Fatal("job whose output is removed is up-to-date")
+	}
+}
+
+func writeTestFile(t *testing.T, file, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
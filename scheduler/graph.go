@@ -0,0 +1,102 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yocks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ansurfen/yock/util"
+)
+
+// taskGraph is a directed acyclic graph formed by the needs of tasks,
+// and an edge from a to b means that a can't start until b is finished.
+type taskGraph struct {
+	needs map[string][]string
+}
+
+func newTaskGraph() *taskGraph {
+	return &taskGraph{needs: make(map[string][]string)}
+}
+
+// addNeeds declares the tasks which the task depends on.
+// The tasks to be needed aren't required to be registered in advance,
+// but it'll fail and not change anything when a cycle is formed.
+func (g *taskGraph) addNeeds(task string, needs ...string) error {
+	for _, need := range needs {
+		if path := g.path(need, task); path != nil {
+			return fmt.Errorf("%w: %s", util.ErrCircularDependency,
+				strings.Join(append([]string{task}, path...), " -> "))
+		}
+	}
+	for _, need := range needs {
+		if !g.has(task, need) {
+			g.needs[task] = append(g.needs[task], need)
+		}
+	}
+	return nil
+}
+
+// depends returns the tasks which are directly needed by the task
+func (g *taskGraph) depends(task string) []string {
+	return g.needs[task]
+}
+
+func (g *taskGraph) has(task, need string) bool {
+	for _, n := range g.needs[task] {
+		if n == need {
+			return true
+		}
+	}
+	return false
+}
+
+// path returns the chain of dependency from src to dst,
+// and returns nil when dst isn't reachable.
+func (g *taskGraph) path(src, dst string) []string {
+	if src == dst {
+		return []string{dst}
+	}
+	visited := make(map[string]bool)
+	var walk func(cur string) []string
+	walk = func(cur string) []string {
+		if visited[cur] {
+			return nil
+		}
+		visited[cur] = true
+		for _, next := range g.needs[cur] {
+			if next == dst {
+				return []string{cur, dst}
+			}
+			if p := walk(next); p != nil {
+				return append([]string{cur}, p...)
+			}
+		}
+		return nil
+	}
+	return walk(src)
+}
+
+// order returns the tasks and all what they need in topological order,
+// which ensures that a task always appears after its needs.
+func (g *taskGraph) order(tasks ...string) []string {
+	res := []string{}
+	visited := make(map[string]bool)
+	var walk func(cur string)
+	walk = func(cur string) {
+		if visited[cur] {
+			return
+		}
+		visited[cur] = true
+		for _, need := range g.needs[cur] {
+			walk(need)
+		}
+		res = append(res, cur)
+	}
+	for _, task := range tasks {
+		walk(task)
+	}
+	return res
+}
@@ -0,0 +1,39 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yocks
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ansurfen/yock/util"
+)
+
+func TestTaskGraph(t *testing.T) {
+	g := newTaskGraph()
+	if err := g.addNeeds("deploy", "build"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.addNeeds("build", "fetch", "proto"); err != nil {
+		t.Fatal(err)
+	}
+	order := g.order("deploy")
+	fmt.Println(order)
+	if len(order) != 4 || order[len(order)-1] != "deploy" {
+		t.Fatal("invalid order")
+	}
+	err := g.addNeeds("fetch", "deploy")
+	fmt.Println(err)
+	if !errors.Is(err, util.ErrCircularDependency) {
+		t.Fatal("cycle isn't detected")
+	}
+	if len(g.depends("fetch")) != 0 {
+		t.Fatal("graph is changed by invalid needs")
+	}
+	if err = g.addNeeds("proto", "proto"); !errors.Is(err, util.ErrCircularDependency) {
+		t.Fatal("self cycle isn't detected")
+	}
+}
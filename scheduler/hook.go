@@ -0,0 +1,165 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yocks
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	yocki "github.com/ansurfen/yock/interface"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// the events in lifecycle of job, which hooks can be registered on
+const (
+	// hookBefore is triggered before job runs, and job fails when the hook fails
+	hookBefore = "before"
+	// hookAfter is triggered after job succeeds, and job fails when the hook fails
+	hookAfter = "after"
+	// hookOnFailure is triggered after job fails
+	hookOnFailure = "on_failure"
+	// hookFinally is always triggered after job ends
+	hookFinally = "finally"
+)
+
+type lifecycleHook struct {
+	// task is the name of task which hook belongs to, and empty means global
+	task string
+	fn   *lua.LFunction
+}
+
+// taskHooks stores the hooks registered by script in order of registration
+type taskHooks struct {
+	mut   sync.RWMutex
+	hooks map[string][]lifecycleHook
+}
+
+func newTaskHooks() *taskHooks {
+	return &taskHooks{hooks: make(map[string][]lifecycleHook)}
+}
+
+func (h *taskHooks) add(event, task string, fn *lua.LFunction) error {
+	switch event {
+	case hookBefore, hookAfter, hookOnFailure, hookFinally:
+	default:
+		return fmt.Errorf("invalid hook event %s", event)
+	}
+	h.mut.Lock()
+	defer h.mut.Unlock()
+	h.hooks[event] = append(h.hooks[event], lifecycleHook{task: task, fn: fn})
+	return nil
+}
+
+// get returns the hooks of task on event. The global hooks are
+// in front of task's when job starts, and behind when job ends,
+// just like the nested scope.
+func (h *taskHooks) get(event, task string) []*lua.LFunction {
+	h.mut.RLock()
+	defer h.mut.RUnlock()
+	global, local := []*lua.LFunction{}, []*lua.LFunction{}
+	for _, hook := range h.hooks[event] {
+		switch hook.task {
+		case "":
+			global = append(global, hook.fn)
+		case task:
+			local = append(local, hook.fn)
+		}
+	}
+	if event == hookBefore {
+		return append(global, local...)
+	}
+	return append(local, global...)
+}
+
+// taskHook registers hook on the event of job, and it's
+// global when the task isn't specified.
+//
+// @param event string
+//
+// @param task? string
+//
+// @param fn function
+func taskHook(ys yocki.YockScheduler, l yocki.YockState) int {
+	yocks := ys.(*YockScheduler)
+	event := l.LState().CheckString(1)
+	task := ""
+	fn := (*lua.LFunction)(nil)
+	if l.IsFunction(2) {
+		fn = l.CheckFunction(2)
+	} else {
+		task = l.LState().CheckString(2)
+		fn = l.LState().CheckFunction(3)
+	}
+	if err := yocks.hooks.add(event, task, fn); err != nil {
+		l.LState().ArgError(1, err.Error())
+	}
+	return 0
+}
+
+// callHooks calls the hooks of task on event in new states with the
+// fields of context and the result of job (if any). Because the state
+// of context might still be occupied by the job overrunning.
+//
+// For before and after, it stops at the first hook failed and returns
+// its error. Others are called anyway, and their errors are joined.
+func (yocks *YockScheduler) callHooks(event string, ctx *Context, res *JobResult) error {
+	fns := yocks.hooks.get(event, ctx.task)
+	if len(fns) == 0 {
+		return nil
+	}
+	fields := ctx.fields()
+	var errs error
+	for _, fn := range fns {
+		err := func() error {
+			s, cancel := yocks.NewState()
+			if cancel != nil {
+				defer cancel()
+			}
+			defer yocks.bindGroup(s, ctx.task)()
+			// each hook has its own copy, so that hooks can't affect each other
+			args := []any{plainValue(fields, make(map[*lua.LTable]*lua.LTable))}
+			if res != nil {
+				args = append(args, res.table())
+			}
+			return s.Call(yocki.YockFuncInfo{Fn: fn, Protect: true}, args...)
+		}()
+		if err == nil {
+			continue
+		}
+		if e, ok := err.(*lua.ApiError); ok {
+			err = fmt.Errorf("%s hook: %s", event, e.Object.String())
+		} else {
+			err = fmt.Errorf("%s hook: %w", event, err)
+		}
+		if event == hookBefore || event == hookAfter {
+			return err
+		}
+		errs = errors.Join(errs, err)
+	}
+	return errs
+}
+
+// plainValue returns the deep copy of v without functions, which are bound
+// to the state creating them. References between tables are kept.
+func plainValue(v lua.LValue, seen map[*lua.LTable]*lua.LTable) lua.LValue {
+	switch v := v.(type) {
+	case *lua.LFunction:
+		return lua.LNil
+	case *lua.LTable:
+		if dst, ok := seen[v]; ok {
+			return dst
+		}
+		dst := &lua.LTable{}
+		seen[v] = dst
+		v.ForEach(func(key, value lua.LValue) {
+			if key, value = plainValue(key, seen), plainValue(value, seen); key != lua.LNil && value != lua.LNil {
+				dst.RawSet(key, value)
+			}
+		})
+		return dst
+	}
+	return v
+}
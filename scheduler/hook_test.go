@@ -0,0 +1,70 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yocks
+
+import (
+	"testing"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestTaskHooks(t *testing.T) {
+	hooks := newTaskHooks()
+	global, build, test := &lua.LFunction{}, &lua.LFunction{}, &lua.LFunction{}
+	for _, h := range []struct {
+		event, task string
+		fn          *lua.LFunction
+	}{
+		{hookBefore, "", global},
+		{hookBefore, "build", build},
+		{hookBefore, "test", test},
+		{hookFinally, "", global},
+		{hookFinally, "build", build},
+	} {
+		if err := hooks.add(h.event, h.task, h.fn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := hooks.add("after_all", "", global); err == nil {
+		t.Fatal("invalid event is accepted")
+	}
+	if fns := hooks.get(hookBefore, "build"); len(fns) != 2 || fns[0] != global || fns[1] != build {
+		t.Fatal("global hooks should be called first before job")
+	}
+	if fns := hooks.get(hookFinally, "build"); len(fns) != 2 || fns[0] != build || fns[1] != global {
+		t.Fatal("global hooks should be called last after job")
+	}
+	if fns := hooks.get(hookAfter, "build"); len(fns) != 0 {
+		t.Fatal("unexpected hooks")
+	}
+}
+
+func TestCallHooks(t *testing.T) {
+	ys := Default(OptionLibPath("../lib/yock"))
+	go ys.EventLoop()
+	defer ys.Shutdown(time.Second)
+	err := ys.Eval(`
+env.build = { mode = "release" }
+hook("before", "build", function(ctx)
+    assert(ctx.throw == nil and ctx.yield == nil, "functions of context are passed to hook")
+    assert(ctx.task == "build" and ctx.build.mode == "release")
+    ctx.build.mode = "debug"
+end)
+hook("finally", "build", function(ctx, res)
+    assert(ctx.build.mode == "release", "hooks share the context")
+    assert(res.status == "succeeded")
+end)
+job("build", function(ctx)
+    assert(ctx.build.mode == "release", "context is changed by hook")
+end)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := ys.LaunchTasks("build")
+	if len(report.Results) != 1 || report.Failed() {
+		t.Fatal("hooks should get the copy of context", report.Results[0].Error)
+	}
+}
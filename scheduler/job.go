@@ -24,6 +24,11 @@ import (
 type yockJob struct {
 	name string
 	fn   *lua.LFunction
+	spec *jobSpec
+	// opt is the option declared in job, such as timeout, retry and etc.
+	opt *lua.LTable
+	// cell is the combination of matrix which job is expanded for
+	cell *jobCell
 }
 
 func (job *yockJob) Name() string {
@@ -34,10 +39,18 @@ func (job *yockJob) Func() *lua.LFunction {
 	return job.fn
 }
 
+func (job *yockJob) cellName() string {
+	if job.cell == nil {
+		return ""
+	}
+	return job.cell.name
+}
+
 func loadTask(yocks yocki.YockScheduler) {
 	yocks.RegYocksFn(yocki.YocksFuncs{
 		"job":    taskJob,
 		"jobs":   taskJobs,
+		"hook":   taskHook,
 		"option": yocksOption,
 	})
 }
@@ -49,20 +62,68 @@ func loadTask(yocks yocki.YockScheduler) {
 // @param jobName string
 //
 // @param jobFn function
-func taskJob(yocks yocki.YockScheduler, l yocki.YockState) int {
+//
+// @param opt? table
+func taskJob(ys yocki.YockScheduler, l yocki.YockState) int {
+	yocks := ys.(*YockScheduler)
 	jobName := l.LState().CheckString(1)
 	jobFn := l.LState().CheckFunction(2)
+	opt := l.LState().OptTable(3, nil)
 	if yocks.GetTask(jobName) {
 		ycho.Fatal(util.ErrDumplicateJobName)
-	} else {
-		yocks.AppendTask(jobName, &yockJob{
-			name: jobName,
-			fn:   jobFn,
-		})
 	}
+	job := &yockJob{
+		name: jobName,
+		fn:   jobFn,
+		opt:  opt,
+	}
+	if opt != nil {
+		if err := yocks.graph.addNeeds(jobName, checkNeeds(opt)...); err != nil {
+			ycho.Fatal(err)
+		}
+		job.spec = checkJobSpec(opt)
+		cells, err := checkMatrix(opt)
+		if err != nil {
+			ycho.Fatal(err)
+		}
+		// the cells of matrix run one by one as the jobs of task
+		for _, cell := range cells {
+			yocks.AppendTask(jobName, &yockJob{
+				name: jobName,
+				fn:   jobFn,
+				opt:  opt,
+				spec: job.spec,
+				cell: cell,
+			})
+		}
+		if len(cells) > 0 {
+			return 0
+		}
+	}
+	yocks.AppendTask(jobName, job)
 	return 0
 }
 
+// checkNeeds takes the needs field from the option of job,
+// which can be either single string or array of string.
+func checkNeeds(opt *lua.LTable) []string {
+	return checkStrings(opt.RawGetString("needs"))
+}
+
+// checkStrings converts single string or array of string into slice
+func checkStrings(v lua.LValue) []string {
+	res := []string{}
+	switch vv := v.(type) {
+	case lua.LString:
+		res = append(res, vv.String())
+	case *lua.LTable:
+		vv.ForEach(func(_, s lua.LValue) {
+			res = append(res, s.String())
+		})
+	}
+	return res
+}
+
 // taskJob packages multiple jobs as a task and registers it with the scheduler
 //
 // NOTE: the name of the job and jobs cannot be duplicated
@@ -83,11 +144,26 @@ func taskJobs(ys yocki.YockScheduler, l yocki.YockState) int {
 	if yocks.GetTask(name) {
 		ycho.Fatal(util.ErrDumplicateJobName)
 	}
+	members := make(map[string]bool)
 	for _, n := range groups[1:] {
+		members[n] = true
 		if job, ok := yocks.task[n]; ok {
 			yocks.task[name] = append(yocks.task[name], job...)
 		}
 	}
+	// the jobs of group run one by one, so the group only
+	// needs what its members need except for the members themselves.
+	needs := []string{}
+	for _, n := range groups[1:] {
+		for _, need := range yocks.graph.depends(n) {
+			if !members[need] {
+				needs = append(needs, need)
+			}
+		}
+	}
+	if err := yocks.graph.addNeeds(name, needs...); err != nil {
+		ycho.Fatal(err)
+	}
 	return 0
 }
 
@@ -122,6 +198,14 @@ func yocksOption(yocks yocki.YockScheduler, l yocki.YockState) int {
 	if cfg.Yockd.Port > 0 {
 		env.Conf().Yockd.Port = cfg.Yockd.Port
 	}
+	// the peers are the nodes where jobs can be dispatched
+	if env.Conf().Yockd.Peer == nil {
+		env.Conf().Yockd.Peer = cfg.Yockd.Peer
+	} else {
+		for name, peer := range cfg.Yockd.Peer {
+			env.Conf().Yockd.Peer[name] = peer
+		}
+	}
 	if cfg.Yockd.SelfBoot {
 		infos, err := yockc.Lsof()
 		if err != nil {
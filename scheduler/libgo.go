@@ -19,21 +19,30 @@ import (
 	libsync "github.com/ansurfen/yock/lib/go/sync"
 	libtime "github.com/ansurfen/yock/lib/go/time"
 	"github.com/ansurfen/yock/lib/go/unicode"
+	liby "github.com/ansurfen/yock/lib/go/yock"
+	yockr "github.com/ansurfen/yock/runtime"
 )
 
-var libgo = []loader{
-	fmt.LoadFmt,
-	netlib.LoadNet,
-	path.LoadPath,
-	regexp.LoadRegexp,
-	libstrings.LoadStrings,
-	libtime.LoadTime,
-	libsync.LoadSync,
-	iolib.LoadIo,
-	bufio.LoadBufio,
-	unicode.LoadUnicode,
-	oslib.LoadOs,
-	strconv.LoadStrconv,
-	compresslib.LoadCompress,
-	archive.LoadArchive,
+// libgo mirrors go libraries by reflection. The functions of them can't be
+// checked one by one, so that they're loaded in sandbox only when the
+// capabilities in need are granted without scope.
+var libgo = []struct {
+	load loader
+	caps []string
+}{
+	{fmt.LoadFmt, nil},
+	{netlib.LoadNet, []string{yockr.CapNet}},
+	{path.LoadPath, []string{yockr.CapFSRead}},
+	{regexp.LoadRegexp, nil},
+	{libstrings.LoadStrings, nil},
+	{libtime.LoadTime, nil},
+	{libsync.LoadSync, nil},
+	{iolib.LoadIo, []string{yockr.CapFSWrite}},
+	{bufio.LoadBufio, nil},
+	{unicode.LoadUnicode, nil},
+	{oslib.LoadOs, []string{yockr.CapFSWrite, yockr.CapExec, yockr.CapEnv}},
+	{strconv.LoadStrconv, nil},
+	{compresslib.LoadCompress, nil},
+	{archive.LoadArchive, []string{yockr.CapFSRead}},
+	{liby.LoadGin, []string{yockr.CapNet, yockr.CapFSRead}},
 }
@@ -0,0 +1,39 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yocks
+
+import (
+	"testing"
+	"time"
+
+	yockr "github.com/ansurfen/yock/runtime"
+)
+
+func TestLoadLibsSandbox(t *testing.T) {
+	ys := Default(OptionLibPath("../lib/yock"))
+	go ys.EventLoop()
+	defer ys.Shutdown(time.Second)
+	if err := ys.Eval(`assert(gin ~= nil and gin.Default ~= nil)`); err != nil {
+		t.Fatal(err)
+	}
+
+	sb, err := yockr.NewSandbox("third-party", "fs:read:.", "net")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ys = Default(OptionLibPath("../lib/yock"), OptionSandbox(sb))
+	go ys.EventLoop()
+	defer ys.Shutdown(time.Second)
+	err = ys.Eval(`
+assert(gin == nil, "gin is loaded without fs:read")
+assert(env.conf == nil, "conf of yock is exposed without fs:write")
+local ok, err = pcall(open_conf, "/etc/yock.yaml")
+assert(not ok and err:find("denies fs:read /etc/yock.yaml", 1, true), err)
+ok, err = pcall(open_conf, "yock.yaml")
+assert(not ok and err:find("denies fs:write", 1, true), err)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
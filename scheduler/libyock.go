@@ -9,6 +9,7 @@ import liby "github.com/ansurfen/yock/lib/go/yock"
 var libyock = []loader{
 	liby.LoadCheck,
 	liby.LoadGoroutine,
+	liby.LoadChannel,
 	liby.LoadXML,
 	liby.LoadType,
 	liby.LoadGNU,
@@ -21,5 +22,4 @@ var libyock = []loader{
 	liby.LoadBit,
 	loadEnv,
 	loadTask,
-	liby.LoadGin,
 }
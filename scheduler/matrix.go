@@ -0,0 +1,129 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yocks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// jobCell is a combination of the matrix declared in job,
+// and each cell is expanded into a concrete job.
+type jobCell struct {
+	// coords are the coordinates of cell, such as { os = "linux", arch = "amd64" }
+	coords *lua.LTable
+	// name is the coordinates in form of k=v, which are sorted by key and joined by comma
+	name string
+}
+
+// matrix returns the copy of coordinates, so that the job can't change others'
+func (cell *jobCell) matrix() *lua.LTable {
+	tbl := &lua.LTable{}
+	cell.coords.ForEach(func(k, v lua.LValue) {
+		tbl.RawSet(k, v)
+	})
+	return tbl
+}
+
+// checkMatrix expands the matrix field of job's option into cells, such as:
+//
+//	{ matrix = { os = { "linux", "windows" }, arch = { "amd64", "arm64" } } }
+//
+// The cells are in order of cartesian product of axes sorted by name,
+// and the value of axis can be either single value or array.
+func checkMatrix(opt *lua.LTable) ([]*jobCell, error) {
+	matrix, ok := opt.RawGetString("matrix").(*lua.LTable)
+	if !ok {
+		return nil, nil
+	}
+	axes := []string{}
+	values := make(map[string][]lua.LValue)
+	var err error
+	matrix.ForEach(func(k, v lua.LValue) {
+		axis, ok := k.(lua.LString)
+		if !ok {
+			err = fmt.Errorf("invalid axis %s of matrix", k)
+			return
+		}
+		axes = append(axes, axis.String())
+		if tbl, ok := v.(*lua.LTable); ok {
+			for i := 1; i <= tbl.Len(); i++ {
+				values[axis.String()] = append(values[axis.String()], tbl.RawGetInt(i))
+			}
+		} else {
+			values[axis.String()] = []lua.LValue{v}
+		}
+		if len(values[axis.String()]) == 0 {
+			err = fmt.Errorf("empty axis %s of matrix", axis)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(axes) == 0 {
+		return nil, nil
+	}
+	sort.Strings(axes)
+	cells := []*jobCell{{coords: &lua.LTable{}}}
+	for _, axis := range axes {
+		next := []*jobCell{}
+		for _, cell := range cells {
+			for _, v := range values[axis] {
+				coords := cell.matrix()
+				coords.RawSetString(axis, v)
+				name := fmt.Sprintf("%s=%s", axis, v.String())
+				if len(cell.name) > 0 {
+					name = cell.name + "," + name
+				}
+				next = append(next, &jobCell{coords: coords, name: name})
+			}
+		}
+		cells = next
+	}
+	return cells, nil
+}
+
+// cellSummary is the results of jobs in the same cell across tasks
+type cellSummary struct {
+	name   string
+	counts map[JobStatus]int
+}
+
+// summarizeCells aggregates results by cell in order of appearance,
+// and the results not belonging to matrix are ignored.
+func summarizeCells(results []*JobResult) []*cellSummary {
+	res := []*cellSummary{}
+	index := make(map[string]*cellSummary)
+	for _, r := range results {
+		if len(r.Cell) == 0 {
+			continue
+		}
+		sum, ok := index[r.Cell]
+		if !ok {
+			sum = &cellSummary{name: r.Cell, counts: make(map[JobStatus]int)}
+			index[r.Cell] = sum
+			res = append(res, sum)
+		}
+		sum.counts[r.Status]++
+	}
+	return res
+}
+
+func (sum *cellSummary) row() []string {
+	status := JobSucceeded
+	if sum.counts[JobFailed] > 0 {
+		status = JobFailed
+	}
+	counts := []string{}
+	for _, s := range []JobStatus{JobSucceeded, JobFailed, JobSkipped, JobUpToDate} {
+		if n := sum.counts[s]; n > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", n, s))
+		}
+	}
+	return []string{sum.name, status.String(), strings.Join(counts, ", ")}
+}
@@ -0,0 +1,90 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yocks
+
+import (
+	"testing"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestCheckMatrix(t *testing.T) {
+	l := lua.NewState()
+	defer l.Close()
+	if err := l.DoString(`
+opt = { matrix = { os = { "linux", "windows" }, arch = { "amd64", "arm64" }, cgo = 0 } }
+empty = { matrix = { os = {} } }`); err != nil {
+		t.Fatal(err)
+	}
+	cells, err := checkMatrix(l.GetGlobal("opt").(*lua.LTable))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"arch=amd64,cgo=0,os=linux",
+		"arch=amd64,cgo=0,os=windows",
+		"arch=arm64,cgo=0,os=linux",
+		"arch=arm64,cgo=0,os=windows",
+	}
+	if len(cells) != len(want) {
+		t.Fatalf("want %d cells, got %d", len(want), len(cells))
+	}
+	for i, w := range want {
+		if cells[i].name != w {
+			t.Fatalf("cell %d: want %s, got %s", i, w, cells[i].name)
+		}
+	}
+	if os := cells[1].matrix().RawGetString("os"); os.String() != "windows" {
+		t.Fatal("invalid coordinates", os)
+	}
+	if _, err = checkMatrix(l.GetGlobal("empty").(*lua.LTable)); err == nil {
+		t.Fatal("empty axis is accepted")
+	}
+}
+
+func TestSummarizeCells(t *testing.T) {
+	cells := summarizeCells([]*JobResult{
+		{Source: "build[os=linux]", Cell: "os=linux", Status: JobSucceeded},
+		{Source: "build[os=windows]", Cell: "os=windows", Status: JobFailed},
+		{Source: "test[os=linux]", Cell: "os=linux", Status: JobUpToDate},
+		{Source: "deploy", Status: JobSkipped},
+	})
+	if len(cells) != 2 {
+		t.Fatalf("want 2 cells, got %d", len(cells))
+	}
+	if row := cells[0].row(); row[1] != "succeeded" || row[2] != "1 succeeded, 1 up-to-date" {
+		t.Fatal("invalid summary", row)
+	}
+	if row := cells[1].row(); row[1] != "failed" {
+		t.Fatal("invalid summary", row)
+	}
+}
+
+func TestMatrixFailedCell(t *testing.T) {
+	ys := Default(OptionLibPath("../lib/yock"))
+	go ys.EventLoop()
+	defer ys.Shutdown(time.Second)
+	err := ys.Eval(`
+job("build", function(ctx)
+    if ctx.matrix.os == "linux" then
+        error("compile error")
+    end
+    ctx.exit(0)
+end, { matrix = { os = { "linux", "windows" } } })
+job("deploy", function(ctx) end, { needs = "build" })`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := ys.LaunchTasks("deploy")
+	if !report.Failed() {
+		t.Fatal("report should fail")
+	}
+	for _, res := range report.Results {
+		if res.Task == "deploy" && res.Status != JobSkipped {
+			t.Fatal("deploy should be skipped when a cell of build fails", res)
+		}
+	}
+}
@@ -5,15 +5,20 @@
 package yocks
 
 import (
+	"time"
+
 	yocke "github.com/ansurfen/yock/env"
+	yockr "github.com/ansurfen/yock/runtime"
 )
 
 type YockSchedulerOption func(*YockScheduler) error
 
 // OptionUpgradeSingalStream upgrades SingleSignalStream to CooperationSingalStream to meet distributed needs.
-func OptionUpgradeSingalStream() YockSchedulerOption {
+// The signals are sent to daemon in namespace ns, and live ttl there. They're global when ns is empty,
+// and never expire when ttl is 0.
+func OptionUpgradeSingalStream(ns string, ttl time.Duration) YockSchedulerOption {
 	return func(ys *YockScheduler) error {
-		ys.signals = upgradeSingalStream(ys.signals, ys.defaultYockd())
+		ys.signals = upgradeSingalStream(ys.signals, ys.defaultYockd(), ns, ttl)
 		return nil
 	}
 }
@@ -43,3 +48,41 @@ func OptionLibPath(path string) YockSchedulerOption {
 		return nil
 	}
 }
+
+// OptionMaxGoroutine limits the number of running tasks and the number of
+// functions spawned by go respectively. It's unlimited when max <= 0.
+func OptionMaxGoroutine(max int) YockSchedulerOption {
+	return func(ys *YockScheduler) error {
+		ys.goroutines = newWorkerPool(max)
+		ys.tasks = newWorkerPool(max)
+		return nil
+	}
+}
+
+// OptionBytecodeCache loads the libraries from the compiled bytecode
+// kept in cache, instead of parsing and compiling them at every start.
+func OptionBytecodeCache(cache *yockr.BytecodeCache) YockSchedulerOption {
+	return func(ys *YockScheduler) error {
+		ys.bytecode = cache
+		return nil
+	}
+}
+
+// OptionSandbox runs scripts in sandbox, and the bindings check the
+// capabilities of it before acting.
+func OptionSandbox(sb *yockr.Sandbox) YockSchedulerOption {
+	return func(ys *YockScheduler) error {
+		ys.sandbox = sb
+		yockr.SetSandbox(ys.State().LState(), sb)
+		return nil
+	}
+}
+
+// OptionRuntime creates the runtime of scheduler with opts, such as the
+// limits of states. It should be given before the options changing state.
+func OptionRuntime(opts ...yockr.YockrOption) YockSchedulerOption {
+	return func(ys *YockScheduler) error {
+		ys.YockRuntime = yockr.New(opts...)
+		return nil
+	}
+}
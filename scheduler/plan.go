@@ -0,0 +1,176 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yocks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	yocki "github.com/ansurfen/yock/interface"
+	"github.com/ansurfen/yock/util"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// PlanAction is what scheduler would do with a job
+type PlanAction int
+
+const (
+	PlanRun PlanAction = iota
+	// PlanUpToDate means the job would be skipped because its files are unchanged
+	PlanUpToDate
+	// PlanSkipped means the job would be skipped because what its task needs would fail
+	PlanSkipped
+	// PlanMissing means the task isn't registered, so that it would fail
+	PlanMissing
+)
+
+func (a PlanAction) String() string {
+	switch a {
+	case PlanRun:
+		return "run"
+	case PlanUpToDate:
+		return "up-to-date"
+	case PlanSkipped:
+		return "skip"
+	case PlanMissing:
+		return "missing"
+	default:
+		return "unknown"
+	}
+}
+
+// JobPlan describes a job in the plan
+type JobPlan struct {
+	Task   string
+	Source string
+	Action PlanAction
+	// Reason explains why the job wouldn't run
+	Reason string
+	Needs  []string
+	// Flags are resolved from the flags field of option({}) for the task
+	Flags string
+}
+
+// RunPlan is the execution plan of tasks, which is worked out
+// without running any job, just like what LaunchTasks would do.
+type RunPlan struct {
+	Modes []string
+	// Args are the flags of script passed after --
+	Args []string
+	Jobs []*JobPlan
+}
+
+// Plan works out the execution plan of tasks in topological order.
+//
+// NOTE: the job exiting with ctx.exit(0) stops the rest of its task,
+// which is only decided at runtime, so that the rest are still planned to run.
+func (yocks *YockScheduler) Plan(names ...string) *RunPlan {
+	plan := &RunPlan{Modes: names}
+	if args, ok := yocks.env.Meta().GetLTable("args"); ok {
+		args.ForEach(func(_, v lua.LValue) {
+			plan.Args = append(plan.Args, v.String())
+		})
+	}
+	flags := yocks.flags()
+	failed := make(map[string]bool)
+	for _, name := range yocks.graph.order(names...) {
+		needs := yocks.graph.depends(name)
+		taskFlags := ""
+		if flags != nil {
+			if tbl, ok := flags.GetLTable(name); ok {
+				taskFlags = formatLValue(tbl)
+			}
+		}
+		reason := ""
+		for _, need := range needs {
+			if failed[need] {
+				reason = fmt.Sprintf("%s failed", need)
+				break
+			}
+		}
+		jobs, found := yocks.task[name]
+		switch {
+		case len(reason) > 0:
+			failed[name] = true
+			plan.Jobs = append(plan.Jobs, &JobPlan{
+				Task: name, Source: name, Action: PlanSkipped,
+				Reason: reason, Needs: needs, Flags: taskFlags,
+			})
+			continue
+		case !found:
+			failed[name] = true
+			plan.Jobs = append(plan.Jobs, &JobPlan{
+				Task: name, Source: name, Action: PlanMissing,
+				Reason: util.ErrJobNotFound.Error(), Needs: needs, Flags: taskFlags,
+			})
+			continue
+		}
+		for _, job := range jobs {
+			jp := &JobPlan{
+				Task:   name,
+				Source: jobSource(name, job),
+				Action: PlanRun,
+				Needs:  needs,
+				Flags:  taskFlags,
+			}
+			if job.spec != nil && job.spec.upToDate(jp.Source) {
+				jp.Action = PlanUpToDate
+				jp.Reason = "inputs and outputs are unchanged"
+			}
+			plan.Jobs = append(plan.Jobs, jp)
+		}
+	}
+	return plan
+}
+
+// Print prints the plan in the order of execution
+func (plan *RunPlan) Print() {
+	fmt.Printf("modes: %s\n", strings.Join(plan.Modes, ", "))
+	if len(plan.Args) > 0 {
+		fmt.Printf("args: %s\n", strings.Join(plan.Args, " "))
+	}
+	rows := [][]string{}
+	for i, jp := range plan.Jobs {
+		action := jp.Action.String()
+		if len(jp.Reason) > 0 {
+			action = fmt.Sprintf("%s (%s)", action, jp.Reason)
+		}
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", i+1), jp.Source, action, strings.Join(jp.Needs, ","), jp.Flags})
+	}
+	util.Prinf(util.PrintfOpt{MaxLen: 50}, []string{"#", "Job", "Action", "Needs", "Flags"}, rows)
+}
+
+// flags returns the flags field of option({}), which is indexed by task
+func (yocks *YockScheduler) flags() yocki.Table {
+	if yocks.opt != nil {
+		if tmp, ok := yocks.opt.GetTable("flags"); ok {
+			return tmp
+		}
+	}
+	return nil
+}
+
+// formatLValue formats v in form of lua's table constructor, and keys are sorted
+func formatLValue(v lua.LValue) string {
+	switch v := v.(type) {
+	case *lua.LTable:
+		fields := []string{}
+		v.ForEach(func(key, value lua.LValue) {
+			if _, ok := key.(lua.LString); ok {
+				fields = append(fields, fmt.Sprintf("%s=%s", key.String(), formatLValue(value)))
+			} else {
+				fields = append(fields, fmt.Sprintf("[%s]=%s", formatLValue(key), formatLValue(value)))
+			}
+		})
+		sort.Strings(fields)
+		return "{" + strings.Join(fields, ", ") + "}"
+	case lua.LString:
+		return fmt.Sprintf("%q", string(v))
+	default:
+		return v.String()
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yocks
+
+import (
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestPlan(t *testing.T) {
+	yocks := New()
+	yocks.task["build"] = []*yockJob{{name: "compile"}, {name: "link"}}
+	yocks.task["deploy"] = []*yockJob{{name: "deploy"}}
+	yocks.task["notify"] = []*yockJob{{name: "notify"}}
+	if err := yocks.graph.addNeeds("deploy", "build", "test"); err != nil {
+		t.Fatal(err)
+	}
+	if err := yocks.graph.addNeeds("notify", "deploy"); err != nil {
+		t.Fatal(err)
+	}
+	plan := yocks.Plan("notify")
+	want := []struct {
+		source string
+		action PlanAction
+	}{
+		{"build:compile", PlanRun},
+		{"build:link", PlanRun},
+		{"test", PlanMissing},
+		{"deploy", PlanSkipped},
+		{"notify", PlanSkipped},
+	}
+	if len(plan.Jobs) != len(want) {
+		t.Fatalf("want %d jobs, got %d", len(want), len(plan.Jobs))
+	}
+	for i, w := range want {
+		if jp := plan.Jobs[i]; jp.Source != w.source || jp.Action != w.action {
+			t.Fatalf("job %d: want %s %s, got %s %s", i, w.source, w.action, jp.Source, jp.Action)
+		}
+	}
+}
+
+func TestFormatLValue(t *testing.T) {
+	l := lua.NewState()
+	defer l.Close()
+	if err := l.DoString(`flags = { port = 8080, host = "web-1", tags = { "a", "b" } }`); err != nil {
+		t.Fatal(err)
+	}
+	want := `{host="web-1", port=8080, tags={[1]="a", [2]="b"}}`
+	if got := formatLValue(l.GetGlobal("flags")); got != want {
+		t.Fatalf("want %s, got %s", want, got)
+	}
+}
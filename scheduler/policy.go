@@ -0,0 +1,101 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yocks
+
+import (
+	"math"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// jobPolicy decides how scheduler deals with the job when it overruns or fails.
+type jobPolicy struct {
+	// timeout cancels the state of job when it overruns, and 0 means no limit.
+	timeout time.Duration
+	// retry is the maximum times to re-run job after it fails.
+	retry   int
+	backoff jobBackoff
+	// priority decides the order of tasks in goroutines pool, and higher runs first.
+	priority int
+}
+
+// jobBackoff is the delay between two attempts of job,
+// and it grows by factor after each failure until max.
+type jobBackoff struct {
+	delay  time.Duration
+	factor float64
+	max    time.Duration
+}
+
+// wait returns the delay before the next attempt, and attempt starts from 1.
+func (b jobBackoff) wait(attempt int) time.Duration {
+	factor := b.factor
+	if factor < 1 {
+		factor = 1
+	}
+	d := time.Duration(float64(b.delay) * math.Pow(factor, float64(attempt-1)))
+	if b.max > 0 && d > b.max {
+		d = b.max
+	}
+	return d
+}
+
+// mergeJobPolicy overrides policy with the fields declared in opt, such as:
+//
+//	{ timeout = 10 * time.Second, retry = 3, backoff = time.Second }
+//	{ retry = 3, backoff = { delay = time.Second, factor = 2, max = time.Minute } }
+func mergeJobPolicy(policy jobPolicy, opt *lua.LTable) jobPolicy {
+	if opt == nil {
+		return policy
+	}
+	if v, ok := opt.RawGetString("timeout").(lua.LNumber); ok {
+		policy.timeout = time.Duration(v)
+	}
+	if v, ok := opt.RawGetString("retry").(lua.LNumber); ok {
+		policy.retry = int(v)
+	}
+	if v, ok := opt.RawGetString("priority").(lua.LNumber); ok {
+		policy.priority = int(v)
+	}
+	switch v := opt.RawGetString("backoff").(type) {
+	case lua.LNumber:
+		policy.backoff = jobBackoff{delay: time.Duration(v)}
+	case *lua.LTable:
+		if delay, ok := v.RawGetString("delay").(lua.LNumber); ok {
+			policy.backoff.delay = time.Duration(delay)
+		}
+		if factor, ok := v.RawGetString("factor").(lua.LNumber); ok {
+			policy.backoff.factor = float64(factor)
+		}
+		if max, ok := v.RawGetString("max").(lua.LNumber); ok {
+			policy.backoff.max = time.Duration(max)
+		}
+	}
+	return policy
+}
+
+// jobPolicy returns the policy of job, which is declared in
+// the option of job and falls back to the job field of option({}).
+func (yocks *YockScheduler) jobPolicy(job *yockJob) jobPolicy {
+	policy := jobPolicy{}
+	if yocks.opt != nil {
+		if def, ok := yocks.opt.GetLTable("job"); ok {
+			policy = mergeJobPolicy(policy, def)
+		}
+	}
+	return mergeJobPolicy(policy, job.opt)
+}
+
+// taskPriority returns the highest priority among the jobs of task
+func (yocks *YockScheduler) taskPriority(name string) int {
+	priority := 0
+	for i, job := range yocks.task[name] {
+		if p := yocks.jobPolicy(job).priority; i == 0 || p > priority {
+			priority = p
+		}
+	}
+	return priority
+}
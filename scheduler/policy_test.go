@@ -0,0 +1,92 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yocks
+
+import (
+	"testing"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestJobBackoff(t *testing.T) {
+	b := jobBackoff{delay: time.Second, factor: 2, max: 5 * time.Second}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if got := b.wait(i + 1); got != w {
+			t.Fatalf("attempt %d: want %s, got %s", i+1, w, got)
+		}
+	}
+}
+
+func TestMergeJobPolicy(t *testing.T) {
+	l := lua.NewState()
+	defer l.Close()
+	if err := l.DoString(`
+def = { retry = 1, timeout = 10 }
+opt = { retry = 3, backoff = 5 }`); err != nil {
+		t.Fatal(err)
+	}
+	policy := mergeJobPolicy(jobPolicy{}, l.GetGlobal("def").(*lua.LTable))
+	policy = mergeJobPolicy(policy, l.GetGlobal("opt").(*lua.LTable))
+	if policy.retry != 3 || policy.timeout != 10 || policy.backoff.wait(2) != 5 {
+		t.Fatal("invalid policy", policy)
+	}
+}
+
+func TestJobTimeout(t *testing.T) {
+	grace := jobGracePeriod
+	jobGracePeriod = 200 * time.Millisecond
+	defer func() { jobGracePeriod = grace }()
+
+	ys := Default(OptionLibPath("../lib/yock"))
+	go ys.EventLoop()
+	defer ys.Shutdown(time.Second)
+	release := make(chan struct{})
+	// block ignores the cancel of state
+	ys.State().LState().SetGlobal("block", ys.State().LState().NewFunction(func(l *lua.LState) int {
+		<-release
+		return 0
+	}))
+	if err := ys.Eval(`
+job("spin", function(ctx)
+    while true do end
+end)
+job("block", function(ctx)
+    block()
+end)`); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(name string) (*Context, *JobResult) {
+		job := ys.task[name][0]
+		ctx := newContext(name, job, nil, ys)
+		return ctx, ctx.run(job, 50*time.Millisecond)
+	}
+	ctx, res := run("spin")
+	if !res.Failed() || ctx.busy != nil {
+		t.Fatal("spin should unwind after timeout", res)
+	}
+	ctx.Close()
+
+	ctx, res = run("block")
+	if !res.Failed() || ctx.busy == nil {
+		t.Fatal("block should still occupy the state", res)
+	}
+	closed := make(chan struct{})
+	ctx.unbind = func() { close(closed) }
+	ctx.Close()
+	select {
+	case <-closed:
+		t.Fatal("context is closed before the call returns")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("context isn't closed after the call returns")
+	}
+}
@@ -5,48 +5,161 @@
 package yocks
 
 import (
+	"sync"
 	"time"
 
 	yocki "github.com/ansurfen/yock/interface"
 	"github.com/ansurfen/yock/util/container"
 )
 
-var _ yocki.GoPool = (*ChannelPool)(nil)
+var _ yocki.GoPool = (*WorkerPool)(nil)
 
-type job func()
+type job struct {
+	fn       func()
+	priority int
+	// seq keeps the order of submission for the jobs with same priority
+	seq uint64
+}
+
+func compareJob(a, b *job) int {
+	switch {
+	case a.priority > b.priority:
+		return 1
+	case a.priority < b.priority:
+		return -1
+	case a.seq < b.seq:
+		return 1
+	case a.seq > b.seq:
+		return -1
+	}
+	return 0
+}
 
-type ChannelPool struct {
-	goroutines chan job
+// WorkerPool is a bounded pool of goroutines.
+//
+// The functions are queued by group (task), and groups take turns to be picked,
+// so that a group spawning lots of goroutines can't starve others.
+// Within a group, the function with higher priority runs first.
+type WorkerPool struct {
+	mut  sync.Mutex
+	cond *sync.Cond
+	// max is the limit of running goroutines, and it's unlimited when max <= 0.
+	max     int
+	running int
+	seq     uint64
+	groups  map[string]container.Heap[*job]
+	// ring is the groups which have pending jobs, in order of round robin.
+	ring   []string
+	next   int
+	closed bool
 }
 
-func newChannelPool(cap int) *ChannelPool {
-	return &ChannelPool{goroutines: make(chan job, cap)}
+func newWorkerPool(max int) *WorkerPool {
+	pool := &WorkerPool{
+		max:    max,
+		groups: make(map[string]container.Heap[*job]),
+	}
+	pool.cond = sync.NewCond(&pool.mut)
+	return pool
 }
 
-func (pool *ChannelPool) Go(f func()) {
-	pool.goroutines <- f
+// Go pushes f into the default group
+func (pool *WorkerPool) Go(f func()) {
+	pool.GoWith("", 0, f)
 }
 
-func (pool *ChannelPool) Run() {
-	sleeping := time.Microsecond * 500
+// GoWith pushes f into the queue of group with priority.
+func (pool *WorkerPool) GoWith(group string, priority int, f func()) {
+	pool.mut.Lock()
+	defer pool.mut.Unlock()
+	jobs, ok := pool.groups[group]
+	if !ok {
+		jobs = container.NewSliceHeap(container.HeapMax, compareJob)
+		pool.groups[group] = jobs
+	}
+	if jobs.Empty() {
+		pool.ring = append(pool.ring, group)
+	}
+	pool.seq++
+	jobs.Push(&job{fn: f, priority: priority, seq: pool.seq})
+	pool.cond.Broadcast()
+}
+
+// Run assigns goroutines for queued functions until the pool is shut down and drained.
+func (pool *WorkerPool) Run() {
+	pool.mut.Lock()
+	defer pool.mut.Unlock()
 	for {
-		timer := time.NewTimer(sleeping)
-		select {
-		case f := <-pool.goroutines:
-			go f()
-		default:
-			// time.Sleep(1 * time.Second)
-			<-timer.C
-			timer.Reset(sleeping)
+		for len(pool.ring) == 0 || pool.full() {
+			if pool.closed && len(pool.ring) == 0 && pool.running == 0 {
+				return
+			}
+			pool.cond.Wait()
 		}
+		j := pool.pop()
+		pool.running++
+		go func() {
+			defer func() {
+				pool.mut.Lock()
+				pool.running--
+				pool.cond.Broadcast()
+				pool.mut.Unlock()
+			}()
+			j.fn()
+		}()
 	}
 }
 
-type PriorityPool struct {
-	jobs container.Heap[job]
+// Shutdown marks the pool closed and waits for queued and running functions
+// to finish. It gives up waiting after timeout when timeout > 0, and reports
+// whether the pool is drained.
+//
+// NOTE: the functions spawned during draining are still accepted,
+// otherwise the peers waiting for them would be blocked forever.
+func (pool *WorkerPool) Shutdown(timeout time.Duration) bool {
+	pool.mut.Lock()
+	pool.closed = true
+	pool.cond.Broadcast()
+	pool.mut.Unlock()
+	drained := make(chan struct{})
+	go func() {
+		pool.mut.Lock()
+		for len(pool.ring) > 0 || pool.running > 0 {
+			pool.cond.Wait()
+		}
+		pool.mut.Unlock()
+		close(drained)
+	}()
+	if timeout <= 0 {
+		<-drained
+		return true
+	}
+	select {
+	case <-drained:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
 }
 
-func (pool *PriorityPool) Go(f func()) {
+func (pool *WorkerPool) full() bool {
+	return pool.max > 0 && pool.running >= pool.max
 }
 
-type RandomPool struct{}
+// pop takes the job with highest priority from the next group
+func (pool *WorkerPool) pop() *job {
+	if pool.next >= len(pool.ring) {
+		pool.next = 0
+	}
+	group := pool.ring[pool.next]
+	jobs := pool.groups[group]
+	j, _ := jobs.Peek()
+	jobs.Pop()
+	if jobs.Empty() {
+		pool.ring = append(pool.ring[:pool.next], pool.ring[pool.next+1:]...)
+		delete(pool.groups, group)
+	} else {
+		pool.next++
+	}
+	return j
+}
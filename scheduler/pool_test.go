@@ -0,0 +1,81 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yocks
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWorkerPool(t *testing.T) {
+	pool := newWorkerPool(2)
+	var (
+		running, peak int32
+		mut           sync.Mutex
+		order         []string
+	)
+	for i := 0; i < 3; i++ {
+		for _, group := range []string{"a", "b"} {
+			name := fmt.Sprintf("%s%d", group, i)
+			pool.GoWith(group, i, func() {
+				cur := atomic.AddInt32(&running, 1)
+				for {
+					old := atomic.LoadInt32(&peak)
+					if cur <= old || atomic.CompareAndSwapInt32(&peak, old, cur) {
+						break
+					}
+				}
+				mut.Lock()
+				order = append(order, name)
+				mut.Unlock()
+				time.Sleep(10 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+			})
+		}
+	}
+	go pool.Run()
+	if !pool.Shutdown(time.Second) {
+		t.Fatal("pool isn't drained")
+	}
+	fmt.Println(order)
+	if peak > 2 {
+		t.Fatalf("running goroutines exceed the limit, %d", peak)
+	}
+	if len(order) != 6 || order[0][1] != '2' {
+		t.Fatal("jobs with higher priority should run first")
+	}
+}
+
+func TestTaskWaitGoroutine(t *testing.T) {
+	ys := Default(OptionLibPath("../lib/yock"), OptionMaxGoroutine(1))
+	go ys.EventLoop()
+	defer ys.Shutdown(time.Second)
+	if err := ys.Eval(`
+job("a", function(ctx)
+    go(function() notify("a") end)
+    wait("a")
+end)
+job("b", function(ctx)
+    go(function() notify("b") end)
+    wait("b")
+end)`); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan *RunReport)
+	go func() {
+		done <- ys.LaunchTasks("a", "b")
+	}()
+	select {
+	case report := <-done:
+		if report.Failed() {
+			t.Fatal("tasks should succeed", report.Results)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("task waiting for its goroutine is blocked")
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yocks
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ansurfen/yock/util"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// JobStatus is the final state of job after scheduling
+type JobStatus int
+
+const (
+	JobSucceeded JobStatus = iota
+	JobFailed
+	// JobSkipped means the job isn't run because what its task needs failed
+	JobSkipped
+	// JobUpToDate means the job isn't run because its inputs and outputs are unchanged
+	JobUpToDate
+)
+
+func (s JobStatus) String() string {
+	switch s {
+	case JobSucceeded:
+		return "succeeded"
+	case JobFailed:
+		return "failed"
+	case JobSkipped:
+		return "skipped"
+	case JobUpToDate:
+		return "up-to-date"
+	default:
+		return "unknown"
+	}
+}
+
+// JobResult records how a job ends
+type JobResult struct {
+	Task   string
+	Source string
+	// Cell is the coordinates of matrix, and it's empty when job isn't expanded from matrix
+	Cell string
+	// Node is the name of yockd where job runs, and it's empty for local job
+	Node   string
+	Status JobStatus
+	// Error is the message raised by job, and it's empty when job succeeds
+	Error string
+	// Traceback is the lua stack when error is raised
+	Traceback string
+	Duration  time.Duration
+	ExitCode  int
+	// Attempts is the times of running job, including retries
+	Attempts int
+}
+
+func (res *JobResult) Failed() bool {
+	return res.Status == JobFailed
+}
+
+// table converts result into lua table, which is passed to hooks
+func (res *JobResult) table() *lua.LTable {
+	tbl := &lua.LTable{}
+	tbl.RawSetString("task", lua.LString(res.Task))
+	tbl.RawSetString("source", lua.LString(res.Source))
+	tbl.RawSetString("cell", lua.LString(res.Cell))
+	tbl.RawSetString("node", lua.LString(res.Node))
+	tbl.RawSetString("status", lua.LString(res.Status.String()))
+	tbl.RawSetString("error", lua.LString(res.Error))
+	tbl.RawSetString("traceback", lua.LString(res.Traceback))
+	tbl.RawSetString("duration", lua.LNumber(res.Duration))
+	tbl.RawSetString("exit_code", lua.LNumber(res.ExitCode))
+	tbl.RawSetString("attempts", lua.LNumber(res.Attempts))
+	return tbl
+}
+
+// RunReport collects results of all jobs in a launch
+type RunReport struct {
+	mut      sync.Mutex
+	Results  []*JobResult
+	Duration time.Duration
+}
+
+func newRunReport() *RunReport {
+	return &RunReport{}
+}
+
+func (report *RunReport) append(res ...*JobResult) {
+	report.mut.Lock()
+	defer report.mut.Unlock()
+	report.Results = append(report.Results, res...)
+}
+
+// Failed reports whether any job failed
+func (report *RunReport) Failed() bool {
+	for _, res := range report.Results {
+		if res.Failed() {
+			return true
+		}
+	}
+	return false
+}
+
+// Print prints the summary of report and the traceback of failed jobs
+func (report *RunReport) Print() {
+	rows := [][]string{}
+	for _, res := range report.Results {
+		rows = append(rows, []string{
+			res.Source, res.Status.String(), fmt.Sprintf("%d", res.ExitCode),
+			res.Duration.Round(time.Millisecond).String(), res.Error})
+	}
+	util.Prinf(util.PrintfOpt{MaxLen: 50}, []string{"Job", "Status", "ExitCode", "Duration", "Error"}, rows)
+	if cells := summarizeCells(report.Results); len(cells) > 0 {
+		rows = [][]string{}
+		for _, cell := range cells {
+			rows = append(rows, cell.row())
+		}
+		fmt.Println()
+		util.Prinf(util.PrintfOpt{MaxLen: 50}, []string{"Cell", "Status", "Jobs"}, rows)
+	}
+	for _, res := range report.Results {
+		if res.Failed() && len(res.Traceback) > 0 {
+			fmt.Printf("\n[%s] %s\n%s\n", res.Source, res.Error, strings.TrimSpace(res.Traceback))
+		}
+	}
+}
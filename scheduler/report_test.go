@@ -0,0 +1,56 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yocks
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunReport(t *testing.T) {
+	ys := Default(OptionLibPath("../lib/yock"))
+	go ys.EventLoop()
+	defer ys.Shutdown(time.Second)
+	err := ys.Eval(`
+job("lint", function(ctx)
+    ctx.exit(1)
+end)
+job("build", function(ctx)
+    error("compile error")
+end, { needs = "lint" })
+job("test", function(ctx) end, { needs = "build" })
+job("package", function(ctx) end, { needs = "test" })`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := ys.LaunchTasks("package")
+	if !report.Failed() {
+		t.Fatal("report should fail")
+	}
+	status := map[string]*JobResult{}
+	for _, res := range report.Results {
+		status[res.Task] = res
+	}
+	if len(status) != 4 {
+		t.Fatal("unexpected results", report.Results)
+	}
+	if res := status["lint"]; res.Status != JobSucceeded || res.ExitCode != 1 {
+		t.Fatal("exit code of lint should be kept", res)
+	}
+	res := status["build"]
+	if res.Status != JobFailed || res.ExitCode != 0 || !res.Failed() {
+		t.Fatal("build should fail", res)
+	}
+	if !strings.Contains(res.Error, "compile error") || !strings.Contains(res.Traceback, "stack traceback") {
+		t.Fatal("error and traceback of build should be captured", res)
+	}
+	if res := status["test"]; res.Status != JobSkipped || res.Error != "build failed" {
+		t.Fatal("test should be skipped", res)
+	}
+	if res := status["package"]; res.Status != JobSkipped || res.Error != "test failed" {
+		t.Fatal("package should be skipped", res)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yocks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ansurfen/yock/daemon/net"
+	pb "github.com/ansurfen/yock/daemon/proto"
+	yocki "github.com/ansurfen/yock/interface"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// nodeSelector picks the members of cluster where job runs by their metadata,
+// and the empty field matches any member.
+type nodeSelector struct {
+	os     string
+	arch   string
+	labels []string
+}
+
+// selector returns the selector of job declared by the table of node,
+// and it's nil when job runs on the named node or local.
+func (job *yockJob) selector() *nodeSelector {
+	if job.opt == nil {
+		return nil
+	}
+	tbl, ok := job.opt.RawGetString("node").(*lua.LTable)
+	if !ok {
+		return nil
+	}
+	return &nodeSelector{
+		os:     lua.LVAsString(tbl.RawGetString("os")),
+		arch:   lua.LVAsString(tbl.RawGetString("arch")),
+		labels: checkStrings(tbl.RawGetString("labels")),
+	}
+}
+
+// remote reports whether job runs on yockd instead of local
+func (job *yockJob) remote() bool {
+	return job.node() != "" || job.selector() != nil
+}
+
+func (sel *nodeSelector) String() string {
+	fields := []string{}
+	if len(sel.os) > 0 {
+		fields = append(fields, "os="+sel.os)
+	}
+	if len(sel.arch) > 0 {
+		fields = append(fields, "arch="+sel.arch)
+	}
+	if len(sel.labels) > 0 {
+		fields = append(fields, "labels="+strings.Join(sel.labels, ","))
+	}
+	return "{" + strings.Join(fields, " ") + "}"
+}
+
+// match reports whether m is alive and has all metadata of selector
+func (sel *nodeSelector) match(m *pb.Member) bool {
+	if m.GetState() != pb.MemberState_Alive ||
+		(len(sel.os) > 0 && sel.os != m.GetOs()) ||
+		(len(sel.arch) > 0 && sel.arch != m.GetArch()) {
+		return false
+	}
+	labels := map[string]bool{}
+	for _, label := range m.GetLabels() {
+		labels[label] = true
+	}
+	for _, label := range sel.labels {
+		if !labels[label] {
+			return false
+		}
+	}
+	return true
+}
+
+// memberLoad is the average usage percent of cpu and memory of member
+func memberLoad(m *pb.Member) float64 {
+	return (m.GetCpu() + m.GetMem()) / 2
+}
+
+// selectNodes returns the members matched by sel, and the least-loaded is the first.
+func selectNodes(members []*pb.Member, sel *nodeSelector) []*pb.Member {
+	res := []*pb.Member{}
+	for _, m := range members {
+		if sel.match(m) {
+			res = append(res, m)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		if li, lj := memberLoad(res[i]), memberLoad(res[j]); li != lj {
+			return li < lj
+		}
+		return res[i].GetName() < res[j].GetName()
+	})
+	return res
+}
+
+// clusterMembers returns the members of cluster known by the default yockd
+func (yocks *YockScheduler) clusterMembers() ([]*pb.Member, error) {
+	members, err := yocks.defaultYockd().ClusterMembers()
+	if err != nil {
+		return nil, fmt.Errorf("fail to list members of cluster, %s", err)
+	}
+	return members, nil
+}
+
+// memberClient returns the client of member, which is dialed by its address at first,
+// and dialed again when the member rejoins at another address.
+func (yocks *YockScheduler) memberClient(m *pb.Member) (yocki.YockdClient, error) {
+	yocks.daemonMu.Lock()
+	defer yocks.daemonMu.Unlock()
+	if cli, ok := yocks.daemon[m.GetName()]; ok {
+		direct, ok := cli.(*net.DirectClient)
+		if !ok || direct.Addr() == m.GetAddr() {
+			return cli, nil
+		}
+		direct.Close()
+		delete(yocks.daemon, m.GetName())
+	}
+	cli, err := net.NewDirectAddr(m.GetAddr())
+	if err != nil {
+		return nil, err
+	}
+	yocks.daemon[m.GetName()] = cli
+	return cli, nil
+}
@@ -0,0 +1,36 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yocks
+
+import (
+	"testing"
+
+	"github.com/ansurfen/yock/daemon/net"
+	pb "github.com/ansurfen/yock/daemon/proto"
+)
+
+func TestMemberClient(t *testing.T) {
+	ys := New()
+	member := &pb.Member{Name: "web-1", Addr: "127.0.0.1:9090"}
+	cli, err := ys.memberClient(member)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached, _ := ys.memberClient(member); cached != cli {
+		t.Fatal("client of member should be cached")
+	}
+	// the member rejoins at another address
+	member.Addr = "127.0.0.1:9091"
+	redialed, err := ys.memberClient(member)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if redialed == cli || redialed.(*net.DirectClient).Addr() != member.Addr {
+		t.Fatal("client of member should be dialed again", redialed)
+	}
+	if ys.daemon["web-1"] != redialed {
+		t.Fatal("stale client is kept")
+	}
+}
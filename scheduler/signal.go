@@ -5,6 +5,9 @@
 package yocks
 
 import (
+	"sync"
+	"time"
+
 	"github.com/ansurfen/yock/daemon/net"
 	yocki "github.com/ansurfen/yock/interface"
 	"github.com/ansurfen/yock/util"
@@ -19,23 +22,70 @@ var (
 // where signals only flow in the process.
 type SingleSignalStream struct {
 	sigs *util.SafeMap[bool]
+
+	mut *sync.Mutex
+	// subs holds the channels of subscribers waiting for the signal
+	subs map[string][]chan struct{}
 }
 
 func NewSingleSignalStream() *SingleSignalStream {
 	return &SingleSignalStream{
 		sigs: util.NewSafeMap[bool](),
+		mut:  &sync.Mutex{},
+		subs: make(map[string][]chan struct{}),
 	}
 }
 
 // Load returns the value of the specified singal.
 // If the singal isn't exist, the second parameter returns false, and vice versa.
 func (stream *SingleSignalStream) Load(sig string) (any, bool) {
-	return stream.sigs.Get(sig)
+	return stream.sigs.SafeGet(sig)
 }
 
 // Store settings specify the value of the singal, similar to map's kv storage.
+// When v is true, all subscribers of the signal will be woken up.
 func (stream *SingleSignalStream) Store(sig string, v bool) {
+	stream.mut.Lock()
+	defer stream.mut.Unlock()
 	stream.sigs.SafeSet(sig, v)
+	if v {
+		for _, ch := range stream.subs[sig] {
+			close(ch)
+		}
+		delete(stream.subs, sig)
+	}
+}
+
+// Subscribe returns a channel which is closed once the signal is stored with true,
+// and the function to cancel subscription.
+// If the signal is true already, the channel returned is closed.
+func (stream *SingleSignalStream) Subscribe(sig string) (<-chan struct{}, func()) {
+	stream.mut.Lock()
+	defer stream.mut.Unlock()
+	ch := make(chan struct{})
+	if v, ok := stream.sigs.SafeGet(sig); ok && v {
+		close(ch)
+		return ch, func() {}
+	}
+	stream.subs[sig] = append(stream.subs[sig], ch)
+	return ch, func() {
+		stream.unsubscribe(sig, ch)
+	}
+}
+
+func (stream *SingleSignalStream) unsubscribe(sig string, ch chan struct{}) {
+	stream.mut.Lock()
+	defer stream.mut.Unlock()
+	subs := stream.subs[sig]
+	for i, sub := range subs {
+		if sub == ch {
+			stream.subs[sig] = append(subs[:i], subs[i+1:]...)
+			break
+		}
+	}
+	if len(stream.subs[sig]) == 0 {
+		delete(stream.subs, sig)
+	}
 }
 
 // CooperationSingalStream is a distributed implementation of SignalStream,
@@ -43,20 +93,29 @@ func (stream *SingleSignalStream) Store(sig string, v bool) {
 type CooperationSingalStream struct {
 	*SingleSignalStream
 	cli yocki.YockdClient
+	// ns is the namespace of signals on daemon, such as the run id,
+	// which keeps runs from colliding, and the signals are global when it's empty.
+	ns string
+	// ttl is how long the signals sent to daemon live, and they never expire when 0.
+	ttl time.Duration
 }
 
-func NewCooperationSingalStream(c *net.DirectClient) *CooperationSingalStream {
+func NewCooperationSingalStream(c *net.DirectClient, ns string, ttl time.Duration) *CooperationSingalStream {
 	return &CooperationSingalStream{
 		SingleSignalStream: NewSingleSignalStream(),
 		cli:                c,
+		ns:                 ns,
+		ttl:                ttl,
 	}
 }
 
 // upgradeSingalStream upgrades SingleSignalStream to CooperationSingalStream to meet distributed needs.
-func upgradeSingalStream(stream yocki.SignalStream, c yocki.YockdClient) yocki.SignalStream {
+func upgradeSingalStream(stream yocki.SignalStream, c yocki.YockdClient, ns string, ttl time.Duration) yocki.SignalStream {
 	return &CooperationSingalStream{
 		cli:                c,
 		SingleSignalStream: stream.(*SingleSignalStream),
+		ns:                 ns,
+		ttl:                ttl,
 	}
 }
 
@@ -65,9 +124,9 @@ func upgradeSingalStream(stream yocki.SignalStream, c yocki.YockdClient) yocki.S
 // In CooperationSingalStream, each load will send a request to daemon to ask for the signal status,
 // and set the value if it exists.
 func (stream *CooperationSingalStream) Load(sig string) (any, bool) {
-	v, ok := stream.sigs.Get(sig)
+	v, ok := stream.sigs.SafeGet(sig)
 	if !ok {
-		v, _ = stream.cli.SignalWait(sig)
+		v, _ = stream.cli.SignalWait(stream.ns, sig, stream.ttl)
 		if v {
 			stream.sigs.SafeSet(sig, v)
 		}
@@ -78,5 +137,50 @@ func (stream *CooperationSingalStream) Load(sig string) (any, bool) {
 // Store settings specify the value of the singal, similar to map's kv storage and send it to daemon.
 func (stream *CooperationSingalStream) Store(sig string, v bool) {
 	stream.SingleSignalStream.Store(sig, v)
-	stream.cli.SignalNotify(sig)
+	stream.cli.SignalNotify(stream.ns, sig, stream.ttl)
+}
+
+// Subscribe returns a channel which is closed once the signal is stored with true,
+// and the function to cancel subscription.
+// Because the signals notified by peers can only be known by asking daemon,
+// CooperationSingalStream keeps polling daemon with backoff until the signal
+// arrives or subscription is canceled.
+func (stream *CooperationSingalStream) Subscribe(sig string) (<-chan struct{}, func()) {
+	ch, unsubscribe := stream.SingleSignalStream.Subscribe(sig)
+	done := make(chan struct{})
+	go func() {
+		interval := 100 * time.Millisecond
+		for {
+			select {
+			case <-ch:
+				return
+			case <-done:
+				return
+			case <-time.After(interval):
+			}
+			if ok, err := stream.cli.SignalWait(stream.ns, sig, stream.ttl); err == nil && ok {
+				stream.SingleSignalStream.Store(sig, true)
+				return
+			}
+			if interval < 2*time.Second {
+				interval *= 2
+			}
+		}
+	}()
+	once := sync.Once{}
+	return ch, func() {
+		once.Do(func() {
+			close(done)
+			unsubscribe()
+		})
+	}
+}
+
+// signalNamespace returns the namespace of signals on daemon, which is
+// decided by the signal stream, and it's global for SingleSignalStream.
+func (yocks *YockScheduler) signalNamespace() string {
+	if stream, ok := yocks.signals.(*CooperationSingalStream); ok {
+		return stream.ns
+	}
+	return ""
 }
@@ -0,0 +1,37 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yocks
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSignalSubscribe(t *testing.T) {
+	stream := NewSingleSignalStream()
+	ch, unsubscribe := stream.Subscribe("x")
+	defer unsubscribe()
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		stream.Store("x", false)
+		stream.Store("x", true)
+	}()
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		t.Fatal("subscriber isn't woken up")
+	}
+	ch, _ = stream.Subscribe("x")
+	select {
+	case <-ch:
+	default:
+		t.Fatal("signal is notified already")
+	}
+	_, unsubscribe = stream.Subscribe("y")
+	unsubscribe()
+	if len(stream.subs) != 0 {
+		t.Fatal("subscription isn't canceled")
+	}
+}
@@ -6,6 +6,8 @@ package yocks
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/ansurfen/yock/daemon/net"
 	pb "github.com/ansurfen/yock/daemon/proto"
@@ -17,13 +19,26 @@ func (yocks *YockScheduler) LoadYockd() {
 	lib := yocks.CreateLib("yockd")
 	lib.SetField(map[string]any{
 		"ping": func(name string) error {
-			return yocks.daemon[name].Ping()
+			if err := yocks.guard(yockr.CapNet); err != nil {
+				return err
+			}
+			cli, err := yocks.nodeClient(name)
+			if err != nil {
+				return err
+			}
+			return cli.Ping()
 		},
-		"dial": func(name, ip string, port int) {
+		"dial": func(name, ip string, port int) error {
+			if err := yocks.guard(yockr.CapNet, ip); err != nil {
+				return err
+			}
+			yocks.daemonMu.Lock()
+			defer yocks.daemonMu.Unlock()
 			yocks.daemon[name] = net.NewDirect(&net.YockdClientOption{
 				IP:   ip,
 				Port: port,
 			})
+			return nil
 		},
 		"upload": func(name, src, dst string) {
 			// yocks.daemon[name].Upload(src)
@@ -33,7 +48,7 @@ func (yocks *YockScheduler) LoadYockd() {
 	signal.SetFields(yocks.LState(), map[string]any{
 		"list": func() *lua.LTable {
 			tbl := &lua.LTable{}
-			sigs, err := yocks.defaultYockd().SignalList()
+			sigs, err := yocks.defaultYockd().SignalList(yocks.signalNamespace())
 			if err != nil {
 				return tbl
 			}
@@ -43,18 +58,30 @@ func (yocks *YockScheduler) LoadYockd() {
 			return tbl
 		},
 		"clear": func(sigs ...string) error {
-			return yocks.defaultYockd().SignalClear(sigs...)
+			return yocks.defaultYockd().SignalClear(yocks.signalNamespace(), sigs...)
 		},
 		"info": func(sig string) (bool, bool, error) {
-			return yocks.defaultYockd().SignalInfo(sig)
+			return yocks.defaultYockd().SignalInfo(yocks.signalNamespace(), sig)
 		},
 	})
 	fs := yockr.NewTable()
 	fs.SetFields(yocks.LState(), map[string]any{
 		"put": func(src, dst string) error {
+			if err := yocks.guard(yockr.CapFSRead, src); err != nil {
+				return err
+			}
+			if err := yocks.guard(yockr.CapFSWrite, dst); err != nil {
+				return err
+			}
 			return yocks.defaultYockd().FileSystemPut(src, dst)
 		},
 		"get": func(src, dst string) error {
+			if err := yocks.guard(yockr.CapFSRead, src); err != nil {
+				return err
+			}
+			if err := yocks.guard(yockr.CapFSWrite, dst); err != nil {
+				return err
+			}
 			return yocks.defaultYockd().FileSystemGet(src, dst)
 		},
 	})
@@ -62,6 +89,9 @@ func (yocks *YockScheduler) LoadYockd() {
 	net.SetFields(yocks.LState(), map[string]any{
 		"dial": func(fromName, fromIP string, fromPort int32, fromPublic bool,
 			toName, toIP string, toPort int32, toPublic bool) error {
+			if err := yocks.guard(yockr.CapNet, fromIP, toIP); err != nil {
+				return err
+			}
 			return yocks.defaultYockd().Dial(&pb.NodeInfo{
 				Name:   fromName,
 				Ip:     fromIP,
@@ -75,12 +105,19 @@ func (yocks *YockScheduler) LoadYockd() {
 			})
 		},
 		"call": func(node, method string, args ...string) (string, error) {
+			// the node is named rather than addressed, so that net isn't scoped
+			if err := yocks.guard(yockr.CapNet); err != nil {
+				return "", err
+			}
 			return yocks.defaultYockd().Call(node, method, args...)
 		},
 	})
 	process := yockr.NewTable()
 	process.SetFields(yocks.LState(), map[string]any{
 		"spawn": func(t, spec, cmd string) (int64, error) {
+			if err := yocks.guard(yockr.CapExec, cmd); err != nil {
+				return 0, err
+			}
 			switch t {
 			case "cron":
 				return yocks.defaultYockd().ProcessSpawn(pb.ProcessSpawnType_Cron, spec, cmd)
@@ -104,6 +141,9 @@ func (yocks *YockScheduler) LoadYockd() {
 			return
 		},
 		"kill": func(pid int64) error {
+			if err := yocks.guard(yockr.CapExec, "kill"); err != nil {
+				return err
+			}
 			return yocks.defaultYockd().ProcessKill(pid)
 		},
 		"list": func() (*lua.LTable, error) {
@@ -122,11 +162,109 @@ func (yocks *YockScheduler) LoadYockd() {
 			}
 			return tbl, nil
 		},
+		"attach": func(l *lua.LState) int {
+			pid := l.CheckInt64(1)
+			fn := l.OptFunction(2, nil)
+			last := &lua.LTable{}
+			var cbErr error
+			err := yocks.defaultYockd().ProcessAttach(pid, func(b []byte) {
+				if fn == nil {
+					fmt.Print(string(b))
+					return
+				}
+				if cbErr == nil {
+					cbErr = l.CallByParam(lua.P{Fn: fn, Protect: true}, lua.LString(b))
+				}
+			}, func(state *pb.ProcessStateChange) {
+				last = processStateTable(state)
+			})
+			if err == nil {
+				err = cbErr
+			}
+			l.Push(last)
+			if err != nil {
+				l.Push(lua.LString(err.Error()))
+			} else {
+				l.Push(lua.LNil)
+			}
+			return 2
+		},
+	})
+	cluster := yockr.NewTable()
+	cluster.SetFields(yocks.LState(), map[string]any{
+		"members": func(l *lua.LState) int {
+			cli := yocks.defaultYockd()
+			if l.GetTop() > 0 {
+				var err error
+				if cli, err = yocks.nodeClient(l.CheckString(1)); err != nil {
+					l.Push(lua.LNil)
+					l.Push(lua.LString(err.Error()))
+					return 2
+				}
+			}
+			members, err := cli.ClusterMembers()
+			if err != nil {
+				l.Push(lua.LNil)
+				l.Push(lua.LString(err.Error()))
+				return 2
+			}
+			tbl := &lua.LTable{}
+			for _, m := range members {
+				tbl.Append(memberTable(m))
+			}
+			l.Push(tbl)
+			l.Push(lua.LNil)
+			return 2
+		},
 	})
 	lib.SetField(map[string]any{
 		"signal":  signal.Value(),
 		"fs":      fs.Value(),
 		"net":     net.Value(),
 		"process": process.Value(),
+		"cluster": cluster.Value(),
 	})
 }
+
+// guard returns *yockr.SandboxError when the sandbox of scheduler doesn't
+// grant the capability on any of targets. Yockd acts out of the state of script,
+// so that what it does on behalf of script is checked here.
+func (yocks *YockScheduler) guard(c string, targets ...string) error {
+	if len(targets) == 0 {
+		targets = []string{""}
+	}
+	for _, target := range targets {
+		if err := yocks.sandbox.Check(c, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// memberTable converts the member of cluster into lua table
+func memberTable(m *pb.Member) *lua.LTable {
+	tbl := &lua.LTable{}
+	labels := &lua.LTable{}
+	for _, label := range m.GetLabels() {
+		labels.Append(lua.LString(label))
+	}
+	tbl.RawSetString("name", lua.LString(m.GetName()))
+	tbl.RawSetString("addr", lua.LString(m.GetAddr()))
+	tbl.RawSetString("state", lua.LString(strings.ToLower(m.GetState().String())))
+	tbl.RawSetString("heartbeat", lua.LNumber(m.GetHeartbeat()))
+	tbl.RawSetString("os", lua.LString(m.GetOs()))
+	tbl.RawSetString("arch", lua.LString(m.GetArch()))
+	tbl.RawSetString("labels", labels)
+	tbl.RawSetString("cpu", lua.LNumber(m.GetCpu()))
+	tbl.RawSetString("mem", lua.LNumber(m.GetMem()))
+	return tbl
+}
+
+// processStateTable converts the state of process into lua table
+func processStateTable(state *pb.ProcessStateChange) *lua.LTable {
+	tbl := &lua.LTable{}
+	tbl.RawSetString("state", lua.LNumber(state.GetState()))
+	tbl.RawSetString("name", lua.LString(state.GetName()))
+	tbl.RawSetString("exit_code", lua.LNumber(state.GetExitCode()))
+	return tbl
+}
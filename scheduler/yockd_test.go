@@ -0,0 +1,46 @@
+// Copyright 2023 The Yock Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package yocks
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	yockr "github.com/ansurfen/yock/runtime"
+)
+
+func TestYockdSandbox(t *testing.T) {
+	sb, err := yockr.NewSandbox("third-party", "exec:git", "fs:read:.", "net:github.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ys := Default(OptionLibPath("../lib/yock"), OptionSandbox(sb))
+	go ys.EventLoop()
+	defer ys.Shutdown(time.Second)
+	err = ys.Eval(`
+local function denied(cap, ...)
+    local res = { ... }
+    local err = res[#res]:Error()
+    assert(err:find("sandbox third-party denies " .. cap, 1, true), err)
+end
+denied("exec rm -rf ~", yockd.process.spawn("script", "", "rm -rf ~"))
+denied("exec git status; rm -rf ~", yockd.process.spawn("cron", "@every 1s", "git status; rm -rf ~"))
+denied("exec kill", yockd.process.kill(1))
+denied("fs:write /etc/passwd", yockd.fs.put("a.txt", "/etc/passwd"))
+denied("fs:read /etc/passwd", yockd.fs.get("/etc/passwd", "a.txt"))
+denied("net 10.0.0.1", yockd.net.dial("a", "10.0.0.1", 1, false, "b", "github.com", 2, false))
+denied("net", yockd.net.call("b", "exec", "rm"))
+denied("net 10.0.0.1", yockd.dial("b", "10.0.0.1", 9090))
+denied("net", yockd.ping("b"))
+job("remote", function(ctx) end, { node = "b" })`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := ys.LaunchTasks("remote").Results[0]
+	if res.Status != JobFailed || !strings.Contains(res.Error, "sandbox third-party denies") {
+		t.Fatal("job is dispatched in sandbox", res)
+	}
+}
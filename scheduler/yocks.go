@@ -5,9 +5,12 @@
 package yocks
 
 import (
+	"fmt"
 	"os"
 	"path"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/ansurfen/yock/ctl/conf"
 	"github.com/ansurfen/yock/daemon/net"
@@ -56,11 +59,26 @@ type YockScheduler struct {
 	// By default, the scheduler assigns coroutines to each task.
 	task map[string][]*yockJob
 
+	// graph records the needs declared by job,
+	// which decides the order and concurrency of tasks when launching.
+	graph *taskGraph
+
+	// hooks are called around jobs, such as cleaning up after job ends.
+	hooks *taskHooks
+
 	// goroutines organizes and manages asynchronous functions in scripts.
 	// Due to the single-threaded setting of Lua coroutines, the advantages of multi-core CPUs cannot be exploited.
 	// Yock exported the Golang's coroutines to provide Lua with true asynchronous capabilities.
 	goroutines yocki.GoPool
 
+	// tasks runs the tasks launched, apart from goroutines, so that the task
+	// waiting for what it spawns doesn't hold the quota of goroutines.
+	tasks yocki.GoPool
+
+	// groups maps the state of task or goroutine to the group of goroutines pool,
+	// so that the goroutines spawned by task are queued with the task.
+	groups sync.Map
+
 	// signals manage the signals generated when the script runs.
 	// Using the wait and notify methods provided by yock,
 	// you can easily implement the synchronization relationship of asynchronous tasks.
@@ -74,8 +92,14 @@ type YockScheduler struct {
 	// yockd on each computer can be regarded as a node, and
 	// different nodes can form clusters to complete parallel build, deployment and etc.
 	daemon map[string]yocki.YockdClient
+	// daemonMu guards daemon, which is dialed by jobs running concurrently
+	daemonMu sync.Mutex
 
 	libPath string
+	// bytecode caches the compiled libraries, and it's disabled when nil.
+	bytecode *yockr.BytecodeCache
+	// sandbox limits what scripts can do, and it's disabled when nil.
+	sandbox *yockr.Sandbox
 
 	*yocksDB
 }
@@ -85,7 +109,10 @@ func New(opts ...YockSchedulerOption) *YockScheduler {
 		YockRuntime: yockr.New(),
 		signals:     NewSingleSignalStream(),
 		task:        make(map[string][]*yockJob),
-		goroutines:  newChannelPool(10),
+		graph:       newTaskGraph(),
+		hooks:       newTaskHooks(),
+		goroutines:  newWorkerPool(0),
+		tasks:       newWorkerPool(0),
 		yocksDB:     newYocksDB(),
 		yocki:       newYockInterface(),
 		daemon:      make(map[string]yocki.YockdClient),
@@ -195,6 +222,10 @@ func (yocks *YockScheduler) GetTask(name string) bool {
 }
 
 func (yocks *YockScheduler) AppendTask(name string, job yocki.YockJob) {
+	if j, ok := job.(*yockJob); ok {
+		yocks.task[name] = append(yocks.task[name], &yockJob{fn: j.fn, name: j.name, spec: j.spec, opt: j.opt, cell: j.cell})
+		return
+	}
 	yocks.task[name] = append(yocks.task[name], &yockJob{fn: job.Func(), name: job.Name()})
 }
 
@@ -204,6 +235,63 @@ func (yocks *YockScheduler) Do(f func()) {
 	yocks.goroutines.Go(f)
 }
 
+// Spawn runs fn with a new state in goroutines, and the new state inherits
+// the group of parent, so that goroutines spawned by different tasks
+// take turns to run in the pool.
+func (yocks *YockScheduler) Spawn(parent yocki.YockState, priority int, fn func(yocki.YockState)) {
+	group := yocks.groupOf(parent)
+	yocks.goroutines.GoWith(group, priority, func() {
+		s, cancel := yocks.NewState()
+		if cancel != nil {
+			defer cancel()
+		}
+		defer yocks.bindGroup(s, group)()
+		fn(s)
+	})
+}
+
+// bindGroup binds state to group, and returns the function to unbind
+func (yocks *YockScheduler) bindGroup(s yocki.YockState, group string) func() {
+	if len(group) == 0 {
+		return func() {}
+	}
+	yocks.groups.Store(s.LState(), group)
+	return func() {
+		yocks.groups.Delete(s.LState())
+	}
+}
+
+func (yocks *YockScheduler) groupOf(s yocki.YockState) string {
+	if group, ok := yocks.groups.Load(s.LState()); ok {
+		return group.(string)
+	}
+	return ""
+}
+
+// TaskOf returns the task which state belongs to, such as the state of
+// job's context and the goroutines it spawns, and empty for others.
+func (yocks *YockScheduler) TaskOf(l *lua.LState) string {
+	if task, ok := yocks.groups.Load(l); ok {
+		return task.(string)
+	}
+	return ""
+}
+
+// Shutdown waits for tasks and goroutines to finish gracefully, and gives up after timeout when timeout > 0.
+func (yocks *YockScheduler) Shutdown(timeout time.Duration) bool {
+	start := time.Now()
+	if !yocks.tasks.Shutdown(timeout) {
+		return false
+	}
+	if timeout > 0 {
+		// the goroutines share what's left of timeout
+		if timeout -= time.Since(start); timeout <= 0 {
+			timeout = time.Nanosecond
+		}
+	}
+	return yocks.goroutines.Shutdown(timeout)
+}
+
 // deprecated
 func (yocks *YockScheduler) LoadLibsV1() {
 	loadDriver(yocks)
@@ -238,8 +326,14 @@ func (yocks *YockScheduler) LoadYocki() {
 
 // LoadLibs loads the libraries that go provides to Lua
 func (yocks *YockScheduler) LoadLibs() {
-	for _, load := range libgo {
-		load(yocks)
+	for _, lib := range libgo {
+		granted := true
+		for _, c := range lib.caps {
+			granted = granted && yocks.sandbox.Granted(c)
+		}
+		if granted {
+			lib.load(yocks)
+		}
 	}
 
 	for _, load := range libyock {
@@ -263,7 +357,7 @@ func (yocks *YockScheduler) LoadLibs() {
 	}
 	for _, file := range files {
 		if fn := file.Name(); filepath.Ext(fn) == ".lua" {
-			if err := yocks.EvalFile(path.Join(yocks.libPath, fn)); err != nil {
+			if err := yocks.evalLib(path.Join(yocks.libPath, fn)); err != nil {
 				ycho.Fatal(err)
 			}
 		}
@@ -278,7 +372,7 @@ func (yocks *YockScheduler) LoadLibs() {
 		}
 		for _, file := range files {
 			if fn := file.Name(); filepath.Ext(fn) == ".lua" {
-				if err := yocks.EvalFile(path.Join(boot_path, fn)); err != nil {
+				if err := yocks.evalLib(path.Join(boot_path, fn)); err != nil {
 					ycho.Fatal(err)
 				}
 			}
@@ -286,6 +380,20 @@ func (yocks *YockScheduler) LoadLibs() {
 	}
 }
 
+// evalLib executes file of library, which is loaded from bytecode cache if possible
+func (yocks *YockScheduler) evalLib(file string) error {
+	if yocks.bytecode == nil {
+		return yocks.EvalFile(file)
+	}
+	proto, err := yocks.bytecode.CompileFile(file)
+	if err != nil {
+		return err
+	}
+	l := yocks.State().LState()
+	l.Push(l.NewFunctionFromProto(proto))
+	return l.PCall(0, lua.MultRet, nil)
+}
+
 func (yocks *YockScheduler) setGlobalVars(vars map[string]lua.LValue) {
 	for k, v := range vars {
 		yocks.SetGlobalVar(k, v)
@@ -293,6 +401,8 @@ func (yocks *YockScheduler) setGlobalVars(vars map[string]lua.LValue) {
 }
 
 func (yocks *YockScheduler) defaultYockd() yocki.YockdClient {
+	yocks.daemonMu.Lock()
+	defer yocks.daemonMu.Unlock()
 	if d, ok := yocks.daemon["default"]; !ok {
 		conf := yocke.GetEnv[*conf.YockConf]().Conf()
 		yocks.daemon["default"] = net.NewDirect(&net.YockdClientOption{
@@ -318,37 +428,183 @@ func (yocks *YockScheduler) Env() yocki.YockLib {
 }
 
 // LaunchTask executes the corresponding task based on the task name
-func (yocks *YockScheduler) LaunchTask(name string) {
-	defer func() {
-		msg := recover()
-		switch msg.(type) {
-		case error:
+func (yocks *YockScheduler) LaunchTask(name string) *RunReport {
+	return yocks.LaunchTasks(name)
+}
 
+// LaunchTasks executes the specified tasks together with what they need,
+// and returns the report collecting results of all jobs.
+// The tasks whose needs are all finished will be assigned to the pool of tasks,
+// so that independent branches run concurrently, and a task will be skipped
+// once any of its needs fails.
+func (yocks *YockScheduler) LaunchTasks(names ...string) *RunReport {
+	type taskResult struct {
+		name string
+		ok   bool
+	}
+	start := time.Now()
+	report := newRunReport()
+	order := yocks.graph.order(names...)
+	pending := make(map[string]int)
+	dependents := make(map[string][]string)
+	for _, name := range order {
+		pending[name] = len(yocks.graph.depends(name))
+		for _, need := range yocks.graph.depends(name) {
+			dependents[need] = append(dependents[need], name)
 		}
-	}()
-	var flags yocki.Table
-	if yocks.opt != nil {
-		if tmp, ok := yocks.opt.GetTable("flags"); ok {
-			flags = tmp
+	}
+	done := make(chan taskResult, len(order))
+	dispatch := func(name string) {
+		yocks.tasks.GoWith(name, yocks.taskPriority(name), func() {
+			ok := yocks.runTask(name, report)
+			done <- taskResult{name: name, ok: ok}
+		})
+	}
+	for _, name := range order {
+		if pending[name] == 0 {
+			dispatch(name)
+		}
+	}
+	failed := make(map[string]bool)
+	for remain := len(order); remain > 0; remain-- {
+		res := <-done
+		if !res.ok {
+			failed[res.name] = true
+		}
+		for _, next := range dependents[res.name] {
+			pending[next]--
+			if pending[next] > 0 {
+				continue
+			}
+			skip := false
+			for _, need := range yocks.graph.depends(next) {
+				if failed[need] {
+					ycho.Warnf("[%s] skipped, because %s failed", next, need)
+					report.append(&JobResult{
+						Task:   next,
+						Source: next,
+						Status: JobSkipped,
+						Error:  fmt.Sprintf("%s failed", need),
+					})
+					skip = true
+					break
+				}
+			}
+			if skip {
+				done <- taskResult{name: next, ok: false}
+			} else {
+				dispatch(next)
+			}
+		}
+	}
+	report.Duration = time.Since(start)
+	return report
+}
+
+// runTask runs the jobs of task one by one, appends their results into report,
+// and reports whether the task is finished without error.
+func (yocks *YockScheduler) runTask(name string, report *RunReport) (ok bool) {
+	defer func() {
+		if msg := recover(); msg != nil {
+			report.append(&JobResult{
+				Task:   name,
+				Source: name,
+				Status: JobFailed,
+				Error:  fmt.Sprint(msg),
+			})
+			ok = false
 		}
+	}()
+	jobs, found := yocks.task[name]
+	if !found {
+		ycho.Errorf("[%s] %s", name, util.ErrJobNotFound)
+		report.append(&JobResult{
+			Task:   name,
+			Source: name,
+			Status: JobFailed,
+			Error:  util.ErrJobNotFound.Error(),
+		})
+		return false
 	}
+	flags := yocks.flags()
 	var (
-		inherit bool
-		super   *Context
+		inherit    bool
+		super      *Context
+		cellFailed bool
 	)
-	for _, job := range yocks.task[name] {
-		ctx := newContext(name, job, flags, yocks)
+	for _, job := range jobs {
+		if job.spec != nil && job.spec.upToDate(jobSource(name, job)) {
+			ycho.Infof("[%s] up-to-date, skip", jobSource(name, job))
+			report.append(&JobResult{
+				Task:     name,
+				Source:   jobSource(name, job),
+				Cell:     job.cellName(),
+				Status:   JobUpToDate,
+				ExitCode: 1,
+			})
+			continue
+		}
+		policy := yocks.jobPolicy(job)
+		var (
+			ctx *Context
+			res *JobResult
+		)
+		for attempt := 1; ; attempt++ {
+			ctx = newContext(name, job, flags, yocks)
+			if inherit {
+				ctx.Extends(super)
+			}
+			if attempt == 1 {
+				if err := yocks.callHooks(hookBefore, ctx, nil); err != nil {
+					res = &JobResult{Task: name, Source: ctx.source, Cell: job.cellName(), Status: JobFailed, Error: err.Error()}
+					break
+				}
+			}
+			if job.remote() {
+				res = yocks.dispatch(ctx, job, policy.timeout)
+			} else {
+				res = ctx.run(job, policy.timeout)
+			}
+			res.Attempts = attempt
+			if !res.Failed() || attempt > policy.retry {
+				break
+			}
+			ctx.Close()
+			delay := policy.backoff.wait(attempt)
+			ycho.Warnf("[%s] %s, retry in %s (%d/%d)", ctx.source, res.Error, delay, attempt, policy.retry)
+			time.Sleep(delay)
+		}
+		if !res.Failed() {
+			if err := yocks.callHooks(hookAfter, ctx, res); err != nil {
+				res.Status = JobFailed
+				res.Error = err.Error()
+			}
+		}
+		if res.Failed() {
+			if err := yocks.callHooks(hookOnFailure, ctx, res); err != nil {
+				ycho.Warnf("[%s] %s", ctx.source, err)
+			}
+		}
+		if err := yocks.callHooks(hookFinally, ctx, res); err != nil {
+			ycho.Warnf("[%s] %s", ctx.source, err)
+		}
 		defer ctx.Close()
-		if inherit {
-			ctx.Extends(super)
-			inherit = false
-			super = nil
+		inherit = false
+		super = nil
+		report.append(res)
+		if res.Failed() {
+			ycho.Errorf("[%s] %s", ctx.source, res.Error)
+			// a failed cell doesn't stop others, and fails the task at last
+			if job.cell != nil {
+				cellFailed = true
+				continue
+			}
+			return false
 		}
-		code := ctx.Call(job.fn)
-		ycho.Infof("[%s] exit, %s", ctx.source, code)
-		switch code {
+		ycho.Infof("[%s] exit, %s", ctx.source, contextExitCode(res.ExitCode))
+		switch res.ExitCode {
 		case 0:
-			return
+			return !cellFailed
 		case 1:
 			// continue
 		case 2:
@@ -359,11 +615,13 @@ func (yocks *YockScheduler) LaunchTask(name string) {
 		}
 	}
 	ycho.Infof("[%s] exit", name)
+	return !cellFailed
 }
 
-// EventLoop periodically takes fn from goroutines and assigns goroutine execution
+// EventLoop periodically takes fn from tasks and goroutines and assigns goroutine execution
 //
 // In a future version, event loop will be based on the AST syntax tree or not
 func (yocks *YockScheduler) EventLoop() {
+	go yocks.tasks.Run()
 	yocks.goroutines.Run()
 }
@@ -77,7 +77,7 @@ func (heap *SliceHeap[T]) float(cur int) {
 	temp := heap.data[cur]
 	parent := (cur - 1) / 2
 	// assume conditions: [0, 0], cur = 1, [0, 0, 0], cur = 2
-	for parent > 0 && heap.compare(temp, heap.data[parent]) == cmpVal {
+	for cur > 0 && heap.compare(temp, heap.data[parent]) == cmpVal {
 		heap.data[cur] = heap.data[parent]
 		cur = parent
 		parent = (cur - 1) / 2
@@ -11,12 +11,17 @@ var (
 
 	ErrInvalidModuleName = errors.New("invalid module name")
 
-	ErrDumplicateJobName = errors.New("dumplicate job name")
+	ErrDumplicateJobName  = errors.New("dumplicate job name")
+	ErrJobNotFound        = errors.New("job not found")
+	ErrJobTimeout         = errors.New("job timeout")
+	ErrCircularDependency = errors.New("circular dependency")
+	ErrNodeNotFound       = errors.New("node not found")
 
 	ErrCreateSession  = errors.New("fail to create session")
 	ErrExecuteCommand = errors.New("fail to execute command")
 	ErrAllocTerm      = errors.New("fail to allocate term")
 	ErrAllocShell     = errors.New("fail to allocate shell")
+	ErrNoRecord       = errors.New("no record in cassette")
 
 	// net
 	ErrInvalidPort      = errors.New("invalid port")
@@ -29,6 +34,8 @@ var (
 
 	ErrNoSupportPlatform = errors.New("not support the platform")
 	ErrNoSupportHardward = errors.New("not support the hardward")
+	ErrNoSupportDispatch = errors.New("not support to dispatch job")
+	ErrNoSupportAttach   = errors.New("not support to attach process")
 
 	ErrInvalidPath = errors.New("invalid path")
 